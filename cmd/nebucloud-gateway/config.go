@@ -0,0 +1,173 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the root of gateway configuration file
+type Config struct {
+	// Address to listen, default is ":8080"
+	Listen string `yaml:"listen"`
+	// Path of GraphQL endpoint, default is "/graphql"
+	Path string `yaml:"path"`
+	// Serve HTTPS when certificate and key are provided
+	TLS *ServerTLS `yaml:"tls"`
+	// Maximum time to drain in-flight requests on shutdown, default is 30s
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	// Serve the schema as Apollo Federation v2 subgraph
+	Federation bool `yaml:"federation"`
+	// Manifest of trusted documents, only the listed operations are executed when provided
+	TrustedDocuments string `yaml:"trusted_documents"`
+	// Names of operation validation rules to skip for compatibility with existing clients
+	DisabledValidationRules []string `yaml:"disabled_validation_rules"`
+	// Number of parsed and validated operations to cache, caching is disabled when it's zero
+	DocumentCacheSize int `yaml:"document_cache_size"`
+
+	Middlewares Middlewares `yaml:"middlewares"`
+	Backends    []*Backend  `yaml:"backends"`
+}
+
+// ServerTLS is a certificate of the gateway
+type ServerTLS struct {
+	CertFile string `yaml:"cert_file"`
+	KeyFile  string `yaml:"key_file"`
+}
+
+// Middlewares enables middlewares which are applied in order of CORS, auth and rate limit
+type Middlewares struct {
+	Cors *struct {
+		AllowedOrigins []string `yaml:"allowed_origins"`
+	} `yaml:"cors"`
+	Auth *struct {
+		// Accepted bearer tokens, "${ENV}" is expanded to keep secrets out of file, and they must not be empty
+		BearerTokens []string `yaml:"bearer_tokens"`
+	} `yaml:"auth"`
+	RateLimit *struct {
+		RequestsPerSecond float64 `yaml:"requests_per_second"`
+		Burst             int     `yaml:"burst"`
+	} `yaml:"rate_limit"`
+}
+
+// Backend is a gRPC server whose services are exposed through the gateway.
+// Settings apply to all services of the backend, except connections which Services override.
+type Backend struct {
+	// Name is used in logs and errors, default is host
+	Name       string `yaml:"name"`
+	Connection `yaml:",inline"`
+	// Connections of services which are served by other hosts than the one descriptors are loaded from,
+	// keyed by fully qualified service name like "billing.v1.InvoiceService"
+	Services map[string]*Connection `yaml:"services"`
+	// Maximum time to connect and load descriptors on startup, default is 10s
+	DialTimeout time.Duration `yaml:"dial_timeout"`
+	// Nest queries and mutations of the backend under namespace field
+	Namespace string `yaml:"namespace"`
+	// Transform field names to lower camel case
+	FieldCamelCase bool `yaml:"field_camel_case"`
+	// Map google.protobuf.Timestamp to DateTime scalar of RFC 3339 string
+	DateTime bool `yaml:"date_time"`
+	// Map 64-bit integers to Int instead of Int64 and UInt64 scalars for compatibility with existing clients
+	Int64AsInt bool `yaml:"int64_as_int"`
+	// Map google.protobuf wrappers to object types instead of nullable scalars for compatibility with existing clients
+	WrappersAsObject bool `yaml:"wrappers_as_object"`
+	// Descriptor source, protoset files are used if provided, otherwise server reflection
+	Protosets []string `yaml:"protosets"`
+}
+
+// Connection is how the gateway connects to gRPC server
+type Connection struct {
+	Host string `yaml:"host"`
+	// Connect without transport security
+	Insecure bool `yaml:"insecure"`
+	// Transport security of the connection, system roots are used when CA file is not provided
+	TLS *ClientTLS `yaml:"tls"`
+}
+
+// ClientTLS verifies certificate of gRPC server
+type ClientTLS struct {
+	CAFile     string `yaml:"ca_file"`
+	ServerName string `yaml:"server_name"`
+}
+
+// LoadConfig reads configuration file and fills default values
+func LoadConfig(path string) (*Config, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseConfig(buf)
+}
+
+// envPattern matches "${ENV}" only, so that "$" which appears in values like tokens is kept as is
+var envPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+func expandEnv(buf []byte) []byte {
+	return envPattern.ReplaceAllFunc(buf, func(m []byte) []byte {
+		return []byte(os.Getenv(string(m[2 : len(m)-1])))
+	})
+}
+
+func parseConfig(buf []byte) (*Config, error) {
+	var c Config
+	if err := yaml.Unmarshal(expandEnv(buf), &c); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	if c.Listen == "" {
+		c.Listen = ":8080"
+	}
+	if c.Path == "" {
+		c.Path = "/graphql"
+	}
+	if c.ShutdownTimeout == 0 {
+		c.ShutdownTimeout = 30 * time.Second
+	}
+	if c.TLS != nil && (c.TLS.CertFile == "" || c.TLS.KeyFile == "") {
+		return nil, errors.New("tls requires both of cert_file and key_file")
+	}
+	if a := c.Middlewares.Auth; a != nil {
+		if len(a.BearerTokens) == 0 {
+			return nil, errors.New("auth requires at least one bearer token")
+		}
+		for i, t := range a.BearerTokens {
+			if t == "" {
+				return nil, fmt.Errorf("bearer token #%d of auth is empty", i)
+			}
+		}
+	}
+	if r := c.Middlewares.RateLimit; r != nil && (r.RequestsPerSecond <= 0 || r.Burst <= 0) {
+		return nil, errors.New("rate_limit requires positive requests_per_second and burst")
+	}
+
+	if len(c.Backends) == 0 {
+		return nil, errors.New("at least one backend must be configured")
+	}
+	for i, b := range c.Backends {
+		if b.Host == "" {
+			return nil, fmt.Errorf("host of backend #%d is empty", i)
+		}
+		if b.Name == "" {
+			b.Name = b.Host
+		}
+		if b.DialTimeout == 0 {
+			b.DialTimeout = 10 * time.Second
+		}
+		if b.Insecure && b.TLS != nil {
+			return nil, fmt.Errorf("backend %s can't be both of insecure and tls", b.Name)
+		}
+		for name, s := range b.Services {
+			if s == nil || s.Host == "" {
+				return nil, fmt.Errorf("host of service %s of backend %s is empty", name, b.Name)
+			}
+			if s.Insecure && s.TLS != nil {
+				return nil, fmt.Errorf("service %s of backend %s can't be both of insecure and tls", name, b.Name)
+			}
+		}
+	}
+	return &c, nil
+}
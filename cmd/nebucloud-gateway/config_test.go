@@ -0,0 +1,82 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseConfig(t *testing.T) {
+	t.Setenv("GATEWAY_TOKEN", "secret")
+	c, err := parseConfig([]byte(`
+tls:
+  cert_file: server.crt
+  key_file: server.key
+shutdown_timeout: 5s
+middlewares:
+  auth:
+    bearer_tokens: ["${GATEWAY_TOKEN}"]
+  rate_limit:
+    requests_per_second: 10
+    burst: 20
+backends:
+  - host: localhost:50051
+    insecure: true
+    namespace: users
+  - name: billing
+    host: billing:443
+    protosets: [billing.protoset]
+    services:
+      billing.InvoiceService:
+        host: invoices:50051
+        insecure: true
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, ":8080", c.Listen)
+	assert.Equal(t, "/graphql", c.Path)
+	assert.Equal(t, 5*time.Second, c.ShutdownTimeout)
+	assert.Equal(t, []string{"secret"}, c.Middlewares.Auth.BearerTokens)
+	assert.Nil(t, c.Middlewares.Cors)
+	assert.Equal(t, "localhost:50051", c.Backends[0].Name)
+	assert.Equal(t, 10*time.Second, c.Backends[0].DialTimeout)
+	assert.Equal(t, []string{"billing.protoset"}, c.Backends[1].Protosets)
+	assert.Equal(t, &Connection{Host: "invoices:50051", Insecure: true}, c.Backends[1].Services["billing.InvoiceService"])
+}
+
+func TestParseConfigValidation(t *testing.T) {
+	for config, message := range map[string]string{
+		`listen: ":80"`:                                                  "at least one backend must be configured",
+		`backends: [{insecure: true}]`:                                   "host of backend #0 is empty",
+		`{tls: {cert_file: a.crt}, backends: [{host: a}]}`:               "tls requires both of cert_file and key_file",
+		`{middlewares: {rate_limit: {burst: 1}}, backends: [{host: a}]}`: "rate_limit requires positive requests_per_second and burst",
+		`{middlewares: {auth: {bearer_tokens: ["${GATEWAY_UNSET_TOKEN}"]}}, backends: [{host: a}]}`: "bearer token #0 of auth is empty",
+		`backends: [{host: a, services: {b.Service: {insecure: true}}}]`:                            "host of service b.Service of backend a is empty",
+		`backends: [{host: a, services: {b.Service: {host: b, insecure: true, tls: {}}}}]`:          "service b.Service of backend a can't be both of insecure and tls",
+	} {
+		_, err := parseConfig([]byte(config))
+		assert.EqualError(t, err, message, config)
+	}
+}
+
+func TestParseConfigExpandsOnlyBracedEnv(t *testing.T) {
+	t.Setenv("GATEWAY_TOKEN", "secret")
+	c, err := parseConfig([]byte(`
+middlewares:
+  auth:
+    bearer_tokens: ["${GATEWAY_TOKEN}", "pa$GATEWAY_TOKEN"]
+backends:
+  - host: localhost:50051
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"secret", "pa$GATEWAY_TOKEN"}, c.Middlewares.Auth.BearerTokens)
+}
+
+func TestLoadExampleConfig(t *testing.T) {
+	t.Setenv("GATEWAY_TOKEN", "secret")
+	c, err := LoadConfig("gateway.example.yaml")
+	assert.NoError(t, err)
+	assert.Len(t, c.Backends, 2)
+	assert.Equal(t, "billing", c.Backends[1].Namespace)
+	assert.Equal(t, "invoices.internal:443", c.Backends[1].Services["billing.v1.InvoiceService"].Host)
+}
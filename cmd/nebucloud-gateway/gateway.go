@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/nebucloud/nebucloud-gateway/dynamic"
+	"github.com/nebucloud/nebucloud-gateway/runtime"
+)
+
+// gateway serves handlers of all backends on one ServeMux
+type gateway struct {
+	mux   *runtime.ServeMux
+	cors  runtime.MiddlewareFunc
+	conns []*grpc.ClientConn
+}
+
+func newGateway(ctx context.Context, c *Config) (*gateway, error) {
+	g := &gateway{}
+	var middlewares []runtime.MiddlewareFunc
+	if cors := c.Middlewares.Cors; cors != nil {
+		g.cors = runtime.CorsWithOrigins(cors.AllowedOrigins...)
+		middlewares = append(middlewares, g.cors)
+	}
+	if auth := c.Middlewares.Auth; auth != nil {
+		middlewares = append(middlewares, runtime.BearerAuth(auth.BearerTokens...))
+	}
+	if limit := c.Middlewares.RateLimit; limit != nil {
+		middlewares = append(middlewares, runtime.RateLimit(limit.RequestsPerSecond, limit.Burst))
+	}
+	g.mux = runtime.NewServeMux(middlewares...)
+	if c.Federation {
+		if err := g.mux.EnableFederation(); err != nil {
+			return nil, err
+		}
+	}
+	if c.TrustedDocuments != "" {
+		documents, err := runtime.LoadTrustedDocuments(c.TrustedDocuments)
+		if err != nil {
+			return nil, err
+		}
+		g.mux.TrustedDocuments = documents
+	}
+	if err := g.mux.DisableValidationRules(c.DisabledValidationRules...); err != nil {
+		return nil, err
+	}
+	if c.DocumentCacheSize > 0 {
+		g.mux.DocumentCache = runtime.NewDocumentCache(c.DocumentCacheSize)
+	}
+
+	for _, b := range c.Backends {
+		if err := g.addBackend(ctx, b); err != nil {
+			g.Close()
+			return nil, fmt.Errorf("backend %s: %w", b.Name, err)
+		}
+	}
+	return g, nil
+}
+
+// addBackend connects to backend and registers handlers which are built from its descriptors
+func (g *gateway) addBackend(ctx context.Context, b *Backend) error {
+	conn, err := g.connect(&b.Connection)
+	if err != nil {
+		return err
+	}
+
+	var opts []dynamic.Option
+	for name, s := range b.Services {
+		sc, err := g.connect(s)
+		if err != nil {
+			return fmt.Errorf("service %s: %w", name, err)
+		}
+		opts = append(opts, dynamic.WithServiceConn(name, sc))
+	}
+	if b.FieldCamelCase {
+		opts = append(opts, dynamic.WithFieldCamelCase())
+	}
+	if b.DateTime {
+		opts = append(opts, dynamic.WithDateTime())
+	}
+	if b.Int64AsInt {
+		opts = append(opts, dynamic.WithInt64AsInt())
+	}
+	if b.WrappersAsObject {
+		opts = append(opts, dynamic.WithWrappersAsObject())
+	}
+	var handlers []runtime.GraphqlHandler
+	if len(b.Protosets) > 0 {
+		handlers, err = dynamic.NewHandlersFromProtoset(conn, b.Protosets, opts...)
+	} else {
+		ctx, cancel := context.WithTimeout(ctx, b.DialTimeout)
+		defer cancel()
+		handlers, err = dynamic.NewHandlers(ctx, conn, opts...)
+	}
+	if err != nil {
+		return err
+	}
+	if len(handlers) == 0 {
+		return errors.New("no service declares GraphQL queries or mutations")
+	}
+	for name := range b.Services {
+		if !containsHandler(handlers, name) {
+			return fmt.Errorf("service %s is not served by the backend", name)
+		}
+	}
+
+	for _, h := range handlers {
+		var hopts []runtime.HandlerOption
+		if b.Namespace != "" {
+			hopts = append(hopts, runtime.WithNamespace(b.Namespace))
+		}
+		if err := g.mux.AddHandler(h, hopts...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// connect creates client of the connection which is closed with the gateway
+func (g *gateway) connect(c *Connection) (*grpc.ClientConn, error) {
+	creds, err := transportCredentials(c)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := grpc.NewClient(c.Host, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, err
+	}
+	g.conns = append(g.conns, conn)
+	return conn, nil
+}
+
+// containsHandler reports whether handlers have the handler of fully qualified service name
+func containsHandler(handlers []runtime.GraphqlHandler, name string) bool {
+	for _, h := range handlers {
+		if named, ok := h.(interface{ Name() string }); ok && named.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+func transportCredentials(c *Connection) (credentials.TransportCredentials, error) {
+	if c.Insecure {
+		return insecure.NewCredentials(), nil
+	}
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+	if c.TLS != nil {
+		config.ServerName = c.TLS.ServerName
+		if c.TLS.CAFile != "" {
+			pem, err := os.ReadFile(c.TLS.CAFile)
+			if err != nil {
+				return nil, err
+			}
+			config.RootCAs = x509.NewCertPool()
+			if !config.RootCAs.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no certificate is found in %s", c.TLS.CAFile)
+			}
+		}
+	}
+	return credentials.NewTLS(config), nil
+}
+
+// Handler returns http handler which serves GraphQL endpoint on path.
+// CORS preflight requests are answered here because ServeMux accepts only GraphQL requests.
+func (g *gateway) Handler(path string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodOptions {
+			if g.cors != nil {
+				g.cors(r.Context(), w, r) // nolint: errcheck
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		g.mux.ServeHTTP(w, r)
+	})
+	return mux
+}
+
+// Close closes connections to backends
+func (g *gateway) Close() {
+	for _, conn := range g.conns {
+		conn.Close()
+	}
+}
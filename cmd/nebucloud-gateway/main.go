@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+var version = "dev"
+var printVersion = flag.Bool("v", false, "show binary version")
+var configPath = flag.String("config", "gateway.yaml", "path to configuration file")
+
+func main() {
+	flag.Parse()
+	if *printVersion {
+		io.WriteString(os.Stdout, version)
+		os.Exit(0)
+	}
+
+	if err := run(); err != nil {
+		log.Fatalln("[NEBUCLOUD-GATEWAY] Error:", err)
+	}
+}
+
+func run() error {
+	c, err := LoadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+
+	// SIGTERM starts graceful shutdown, and the second signal terminates immediately by default behavior
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
+	g, err := newGateway(ctx, c)
+	if err != nil {
+		return err
+	}
+	defer g.Close()
+
+	server := &http.Server{
+		Addr:              c.Listen,
+		Handler:           g.Handler(c.Path),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	errs := make(chan error, 1)
+	go func() {
+		log.Printf("[NEBUCLOUD-GATEWAY] Listening on %s%s\n", c.Listen, c.Path)
+		if c.TLS != nil {
+			errs <- server.ListenAndServeTLS(c.TLS.CertFile, c.TLS.KeyFile)
+		} else {
+			errs <- server.ListenAndServe()
+		}
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+	stop()
+
+	// Stop accepting new connections and wait for in-flight requests
+	log.Printf("[NEBUCLOUD-GATEWAY] Shutting down, draining requests up to %s\n", c.ShutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), c.ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errs; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
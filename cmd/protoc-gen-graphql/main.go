@@ -73,7 +73,7 @@ func main() {
 	// in order to access easily plugin options, package name, comment, etc...
 	var files []*spec.File
 	for _, f := range req.GetProtoFile() {
-		files = append(files, spec.NewFile(f, req.GetCompilerVersion(), args.FieldCamelCase))
+		files = append(files, spec.NewFile(f, req.GetCompilerVersion(), args))
 	}
 
 	g := generator.New(files, args)
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	graphqlv1 "github.com/nebucloud/nebucloud-gateway/gen/go/graphql/v1"
+	"github.com/nebucloud/nebucloud-gateway/generator"
+	"github.com/nebucloud/nebucloud-gateway/spec"
+
+	// Generated code which the test type checks imports the runtime and its dependencies
+	_ "github.com/nebucloud/nebucloud-gateway/runtime"
+	_ "github.com/pkg/errors"
+	_ "google.golang.org/grpc"
+)
+
+// libraryProto declares namespaced service with query, mutation, nested resolver, connection,
+// and message which is both federation entity and Relay node
+const libraryProto = `
+name: "library/library.proto"
+package: "library"
+dependency: ["graphql/v1/graphql.proto", "google/protobuf/timestamp.proto"]
+options { go_package: "example.com/library;library" }
+service {
+  name: "LibraryService"
+  options { [graphql.v1.service] { host: "localhost:50051" insecure: true namespace: "library" } }
+  method {
+    name: "GetBook" input_type: ".library.GetBookRequest" output_type: ".library.Book"
+    options { [graphql.v1.schema] { name: "book" policy { timeout: "1s" idempotent: true } } }
+  }
+  method {
+    name: "ListBooks" input_type: ".library.ListBooksRequest" output_type: ".library.ListBooksResponse"
+    options { [graphql.v1.schema] { name: "books" response { connection: true } } }
+  }
+  method {
+    name: "GetAuthor" input_type: ".library.GetAuthorRequest" output_type: ".library.Author"
+    options { [graphql.v1.schema] { type: GRAPHQL_TYPE_RESOLVER name: "author" } }
+  }
+  method {
+    name: "CreateBook" input_type: ".library.CreateBookRequest" output_type: ".library.Book"
+    options { [graphql.v1.schema] { type: GRAPHQL_TYPE_MUTATION name: "createBook" request { name: "input" } } }
+  }
+}
+message_type {
+  name: "Book"
+  options {
+    [graphql.v1.entity] { keys: ["id"] resolver: "book" }
+    [graphql.v1.node] { id: "id" resolver: "book" }
+  }
+  field { name: "id" number: 1 label: LABEL_OPTIONAL type: TYPE_INT64 }
+  field { name: "title" number: 2 label: LABEL_OPTIONAL type: TYPE_STRING }
+  field { name: "author_id" number: 3 label: LABEL_OPTIONAL type: TYPE_STRING }
+  field {
+    name: "author" number: 4 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".library.Author"
+    options { [graphql.v1.field] { resolver: "author" } }
+  }
+  field { name: "published_at" number: 5 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".google.protobuf.Timestamp" }
+}
+message_type {
+  name: "Author"
+  field { name: "author_id" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING }
+  field { name: "name" number: 2 label: LABEL_OPTIONAL type: TYPE_STRING }
+}
+message_type {
+  name: "GetBookRequest"
+  field { name: "id" number: 1 label: LABEL_OPTIONAL type: TYPE_INT64 }
+}
+message_type {
+  name: "GetAuthorRequest"
+  field { name: "author_id" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING }
+}
+message_type {
+  name: "ListBooksRequest"
+  field { name: "page_size" number: 1 label: LABEL_OPTIONAL type: TYPE_INT32 }
+  field { name: "page_token" number: 2 label: LABEL_OPTIONAL type: TYPE_STRING }
+}
+message_type {
+  name: "ListBooksResponse"
+  field { name: "books" number: 1 label: LABEL_REPEATED type: TYPE_MESSAGE type_name: ".library.Book" }
+  field { name: "next_page_token" number: 2 label: LABEL_OPTIONAL type: TYPE_STRING }
+}
+message_type {
+  name: "CreateBookRequest"
+  field { name: "title" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING }
+}
+`
+
+func TestGenerateLibraryProto(t *testing.T) {
+	var fdp descriptorpb.FileDescriptorProto
+	assert.NoError(t, prototext.Unmarshal([]byte(libraryProto), &fdp))
+
+	params, err := spec.NewParams("paths=source_relative,datetime")
+	assert.NoError(t, err)
+	var files []*spec.File
+	for _, d := range []*descriptorpb.FileDescriptorProto{
+		protodesc.ToFileDescriptorProto(graphqlv1.File_graphql_v1_graphql_proto),
+		protodesc.ToFileDescriptorProto(timestamppb.File_google_protobuf_timestamp_proto),
+		&fdp,
+	} {
+		files = append(files, spec.NewFile(d, nil, params))
+	}
+
+	// Generate renders the template and formats rendered code by go/format
+	genFiles, err := generator.New(files, params).Generate(goTemplate, []string{fdp.GetName()})
+	assert.NoError(t, err)
+	if !assert.Len(t, genFiles, 1) {
+		return
+	}
+	assert.Equal(t, "library/library.graphql.go", genFiles[0].GetName())
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, genFiles[0].GetName(), genFiles[0].GetContent(), 0)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// Generated code refers messages and client which protoc-gen-go and protoc-gen-go-grpc generate
+	// in the same package, so it must type check except those undefined identifiers
+	generated := map[string]bool{"NewLibraryServiceClient": true}
+	for _, m := range fdp.GetMessageType() {
+		generated[m.GetName()] = true
+	}
+	var typeErrors []string
+	conf := types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+		Error: func(err error) {
+			msg := err.(types.Error).Msg
+			if name, ok := strings.CutPrefix(msg, "undefined: "); ok && generated[name] {
+				return
+			}
+			typeErrors = append(typeErrors, err.Error())
+		},
+	}
+	conf.Check("library", fset, []*ast.File{f}, nil) // nolint: errcheck
+	assert.Empty(t, typeErrors)
+
+	content := genFiles[0].GetContent()
+	for _, s := range []string{
+		`func (x *graphql__resolver_LibraryService) Namespace() string`,
+		`"author": func(p runtime.ResolveParams) (interface{}, error) {`,
+		`Entities: []*runtime.Entity{`,
+		`Nodes: []*runtime.Node{`,
+		`return pagination.Connection(resp.GetBooks(), resp.GetNextPageToken())`,
+		`return mux.AddHandler(new_graphql_resolver_LibraryService(conn), opts...)`,
+	} {
+		assert.Contains(t, content, s)
+	}
+}
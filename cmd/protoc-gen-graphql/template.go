@@ -7,198 +7,72 @@ package {{ .RootPackage.Name }}
 import (
 {{- if .Services }}
 	"context"
+{{- range .Imports }}
+	"{{ . }}"
+{{- end }}
 
-	"github.com/nebucloud/grpc-graphql-gateway/runtime"
 	"google.golang.org/grpc"
 	"github.com/pkg/errors"
 {{- end }}
-	"github.com/graphql-go/graphql"
+{{- if or .Services .Enums }}
+	"github.com/nebucloud/nebucloud-gateway/runtime"
+{{- end }}
 
+{{- if .Services }}
 {{- range .Packages }}
 	{{ if .Path }}{{ .Name }} "{{ .Path }}"{{ end }}
-{{ end }}
-)
-
-var (
-	{{- range .Enums }}
-	gql__enum_{{ .Name }} *graphql.Enum // enum {{ .Name }} in {{ .Filename }}
-	{{- end }}
-	{{- range .Interfaces }}
-	gql__interface_{{ .Name }} *graphql.Interface // message {{ .Name }} in {{ .Filename }}
-	{{- end }}
-	{{- range .Types }}
-	gql__type_{{ .TypeName }} *graphql.Object // message {{ .Name }} in {{ .Filename }}
-	{{- end }}
-	{{- range .Inputs }}
-	gql__input_{{ .TypeName }} *graphql.InputObject // message {{ .Name }} in {{ .Filename }}
-	{{- end }}
+{{- end }}
+{{- end }}
 )
 
 {{ range $enum := .Enums -}}
-func Gql__enum_{{ .Name }}() *graphql.Enum {
+var gql__enum_{{ .Name }} *runtime.Enum // enum {{ .Name }} in {{ .Filename }}
+
+// Gql__enum_{{ .Name }} returns definition of enum {{ .Name }} with its internal values
+func Gql__enum_{{ .Name }}() *runtime.Enum {
 	if gql__enum_{{ .Name }} == nil {
-		gql__enum_{{ .Name }} =  graphql.NewEnum(graphql.EnumConfig{
-			Name: "{{ $.RootPackage.CamelName }}_Enum_{{ .Name }}",
-			Values: graphql.EnumValueConfigMap{
+		gql__enum_{{ .Name }} = &runtime.Enum{
+			Name: "{{ .GraphqlEnumName }}",
+			Definition: ` + "`" + `{{ .EnumDefinition }}` + "`" + `,
+			Values: map[string]interface{}{
 {{- range .Values }}
-				"{{ .Name }}": &graphql.EnumValueConfig{
-					{{- if .Comment }}
-					Description: ` + "`" + `{{ .Comment }}` + "`" + `,
-					{{- end }}
-					Value: {{ $enum.Name }}({{ .Number }}),
-				},
+				"{{ .Name }}": {{ $enum.Name }}({{ .Number }}),
 {{- end }}
 			},
-		})
+		}
 	}
 	return gql__enum_{{ .Name }}
 }
 
 {{ end }}
 
-{{ range .Interfaces -}}
-func Gql__interface_{{ .TypeName }}() *graphql.Interface {
-	if gql__interface_{{ .TypeName }} == nil {
-		gql__interface_{{ .TypeName }} =  graphql.NewInterface(graphql.InterfaceConfig{
-			Name: "{{ $.RootPackage.CamelName }}_Interface_{{ .TypeName }}",
-			{{- if .Comment }}
-			Description: ` + "`" + `{{ .Comment }}` + "`" + `,
-			{{- end }}
-			Fields: graphql.Fields{
-{{- range .Fields }}
-			{{- if not .IsCyclic }}
-				"{{ .FieldName }}": &graphql.Field{
-					Type: {{ .FieldType $.RootPackage.Name }},
-					{{- if .Comment }}
-					Description: ` + "`" + `{{ .Comment }}` + "`" + `,
-					{{- end }}
-				},
-			{{- end }}
-{{- end }}
-			},
-			ResolveType: func(p graphql.ResolveTypeParams) *graphql.Object {
-				return Gql__type_{{ .TypeName }}()
-			},
-		})
-	}
-	return gql__interface_{{ .TypeName }}
+{{- range .Types -}}
+// Gql__type_{{ .TypeName }} returns object type definition of message {{ .Name }} in {{ .Filename }}
+func Gql__type_{{ .TypeName }}() string {
+	return ` + "`" + `{{ .ObjectDefinition $.Services }}` + "`" + `
 }
 
 {{ end }}
 
-{{ range .Types -}}
-func Gql__type_{{ .TypeName }}() *graphql.Object {
-	if gql__type_{{ .TypeName }} == nil {
-		gql__type_{{ .TypeName }} =  graphql.NewObject(graphql.ObjectConfig{
-			Name: "{{ $.RootPackage.CamelName }}_Type_{{ .TypeName }}",
-			{{- if .Comment }}
-			Description: ` + "`" + `{{ .Comment }}` + "`" + `,
-			{{- end }}
-			Fields: graphql.Fields {
-{{- range .Fields }}
-				{{- if .IsResolve }}
-				{{ $query := .ResolveSubField $.Services }}
-				"{{ .FieldName }}": &graphql.Field{
-						Type: {{ $query.QueryType }},
-						{{- if $query.Comment }}
-						Description: ` + "`" + `{{ $query.Comment }}` + "`" + `,
-						{{- end }}
-						Args: graphql.FieldConfigArgument{
-						{{- range $query.Args }}
-							"{{ .FieldName }}": &graphql.ArgumentConfig{
-								Type: {{ .FieldTypeInput $.RootPackage.Name }},
-								{{- if .Comment }}
-								Description: ` + "`" + `{{ .Comment }}` + "`" + `,
-								{{- end }}
-								{{- if .DefaultValue }}
-								DefaultValue: {{ .DefaultValue }},
-								{{- end }}
-							},
-						{{- end }}
-						},
-						Resolve: func(p graphql.ResolveParams) (interface{}, error) {
-							var req {{ $query.InputType }}
-							if err := runtime.MarshalRequest(p.Source, &req, {{ if $query.IsCamel }}true{{ else }}false{{ end }}); err != nil {
-								return nil, errors.Wrap(err, "Failed to marshal resolver source for {{ $query.QueryName }}")
-							} else if err = runtime.MarshalRequest(p.Args, &req, {{ if $query.IsCamel }}true{{ else }}false{{ end }}); err != nil {
-								return nil, errors.Wrap(err, "Failed to marshal resolver request for {{ $query.QueryName }}")
-							}
-							{{ $s := index $.Services 0 }}
-							x := new_graphql_resolver_{{ $s.Name }}(nil)
-							conn, closer, err := x.CreateConnection(p.Context)
-							if err != nil {
-								return nil, errors.Wrap(err, "Failed to create gRPC connection for nested resolver")
-							}
-							defer closer()
-							client := {{ $query.Package }}New{{ $query.Method.Service.Name }}Client(conn)
-							resp, err := client.{{ $query.Method.Name }}(p.Context, &req)
-							if err != nil {
-								return nil, errors.Wrap(err, "Failed to call RPC {{ $query.Method.Name }}")
-							}
-							{{- if $query.IsPluckResponse }}
-								{{- if $query.IsCamel }}
-								return runtime.MarshalResponse(resp.Get{{ $query.PluckResponseFieldName }}()), nil
-								{{- else }}
-								return resp.Get{{ $query.PluckResponseFieldName }}(), nil
-								{{- end }}
-							{{- else }}
-								{{- if $query.IsCamel }}
-								return runtime.MarshalResponse(resp), nil
-								{{- else }}
-								return resp, nil
-								{{- end }}
-							{{- end }}
-						},
-				},
-				{{- else }}
-				"{{ .FieldName }}": &graphql.Field{
-					Type: {{ .FieldType $.RootPackage.Name }},
-					{{- if .Comment }}
-					Description: ` + "`" + `{{ .Comment }}` + "`" + `,
-					{{- end }}
-				},
-				{{- end }}
-{{- end }}
-			},
-			{{- if .Interfaces }}
-			Interfaces: []*graphql.Interface{
-{{- range .Interfaces }}
-			Gql__interface_{{ .TypeName }}(),
-{{- end }}
-			},
-			{{- end }}
-		})
-	}
-	return gql__type_{{ .TypeName }}
+{{- range .Inputs -}}
+// Gql__input_{{ .TypeName }} returns input object type definition of message {{ .Name }} in {{ .Filename }}
+func Gql__input_{{ .TypeName }}() string {
+	return ` + "`" + `{{ .InputDefinition }}` + "`" + `
 }
 
 {{ end }}
 
-{{ range .Inputs -}}
-func Gql__input_{{ .TypeName }}() *graphql.InputObject {
-	if gql__input_{{ .TypeName }} == nil {
-		gql__input_{{ .TypeName }} =  graphql.NewInputObject(graphql.InputObjectConfig{
-			Name: "{{ $.RootPackage.CamelName }}_Input_{{ .TypeName }}",
-			Fields: graphql.InputObjectConfigFieldMap{
-{{- range .Fields }}
-				"{{ .FieldName }}": &graphql.InputObjectFieldConfig{
-					{{- if .Comment }}
-					Description: ` + "`" + `{{ .Comment }}` + "`" + `,
-					{{- end }}
-					Type: {{ .FieldTypeInput $.RootPackage.Name }},
-				},
-{{- end }}
-			},
-		})
-	}
-	return gql__input_{{ .TypeName }}
-}
+{{ range .Services -}}
+{{ range .CallPolicies -}}
+// {{ .CallPolicyName }} is a backend call policy of {{ .ServiceName }}.{{ .Name }}
+var {{ .CallPolicyName }} = {{ .CallPolicyLiteral }}
 
 {{ end }}
+{{- end }}
 
 {{ range $_, $service := .Services -}}
 // graphql__resolver_{{ $service.Name }} is a struct for making query, mutation and resolve fields.
-// This struct must be implemented runtime.SchemaBuilder interface.
+// This struct must be implemented runtime.GraphqlHandler interface.
 type graphql__resolver_{{ $service.Name }} struct {
 
 	// Automatic connection host
@@ -240,40 +114,299 @@ func (x *graphql__resolver_{{ $service.Name }}) CreateConnection(ctx context.Con
 	return conn, func() { conn.Close() }, nil
 }
 
-// GetQueries returns acceptable graphql.Fields for Query.
-func (x *graphql__resolver_{{ $service.Name }}) GetQueries(conn *grpc.ClientConn) graphql.Fields {
-	return graphql.Fields{
+// backendHost returns the host which circuit breaker of backend calls is keyed by,
+// that is the target of injected connection, or automatic connection host.
+func (x *graphql__resolver_{{ $service.Name }}) backendHost() string {
+	if x.conn != nil {
+		return x.conn.Target()
+	}
+	return x.host
+}
+
+// Name returns full name of the service which identifies this handler in schema conflict errors.
+func (x *graphql__resolver_{{ $service.Name }}) Name() string {
+	return "{{ if $service.Package }}{{ $service.Package }}.{{ end }}{{ $service.Name }}"
+}
+
+{{- if $service.Namespace }}
+// Namespace returns the field name which queries and mutations of this service are nested under.
+func (x *graphql__resolver_{{ $service.Name }}) Namespace() string {
+	return "{{ $service.Namespace }}"
+}
+{{ end }}
+// GetTypes returns definitions of types which queries and mutations refer.
+func (x *graphql__resolver_{{ $service.Name }}) GetTypes() runtime.Types {
+	return runtime.Types{
+		Definitions: []string{
+{{- range $.Types }}
+			{{ .TypeFunc $.RootPackage.Name }},
+{{- end }}
+{{- range $.Inputs }}
+			{{ .InputFunc $.RootPackage.Name }},
+{{- end }}
+{{- range $.ExternalTypes }}
+			{{ .TypeFunc $.RootPackage.Name }},
+			{{ .InputFunc $.RootPackage.Name }},
+{{- end }}
+{{- range $.Connections }}
+			` + "`" + `{{ . }}` + "`" + `,
+{{- end }}
+{{- range $.Scalars }}
+			` + "`" + `{{ . }}` + "`" + `,
+{{- end }}
+{{- range $.Unions }}
+			` + "`" + `{{ . }}` + "`" + `,
+{{- end }}
+		},
+		Enums: []*runtime.Enum{
+{{- range $.Enums }}
+			{{ .EnumFunc $.RootPackage.Name }},
+{{- end }}
+{{- range $.ExternalEnums }}
+			{{ .EnumFunc $.RootPackage.Name }},
+{{- end }}
+		},
+		{{- /* Field, entity and node resolvers are registered by the handler of the service which defines the RPC, so x.backendHost() is the host of the called backend */}}
+		Resolvers: map[string]map[string]runtime.FieldResolveFn{
+{{- range $.Types }}
+{{- if .ResolveFields }}
+			"{{ .GraphqlTypeName }}": {
+{{- range .ResolveFields }}
+{{- $query := .ResolveSubField $.Services }}
+{{- if eq $query.Method.Service $service }}
+				"{{ .FieldName }}": func(p runtime.ResolveParams) (interface{}, error) {
+					{{- if $query.IsConnection }}
+					pagination, err := runtime.NewPagination(p.Args)
+					if err != nil {
+						return nil, err
+					}
+					if c, ok := pagination.Empty(); ok {
+						return c, nil
+					}
+					{{- end }}
+					var req {{ $query.InputType }}
+					if err := runtime.MarshalRequest(p.Source, &req, {{ if $query.IsCamel }}true{{ else }}false{{ end }}); err != nil {
+						return nil, errors.Wrap(err, "Failed to marshal resolver source for {{ $query.QueryName }}")
+					} else if err = runtime.MarshalRequest(p.Args, &req, {{ if $query.IsCamel }}true{{ else }}false{{ end }}); err != nil {
+						return nil, errors.Wrap(err, "Failed to marshal resolver request for {{ $query.QueryName }}")
+					}
+					{{- if $query.IsConnection }}
+					if err := runtime.MarshalRequest(pagination.Request(), &req, false); err != nil {
+						return nil, errors.Wrap(err, "Failed to marshal pagination for {{ $query.QueryName }}")
+					}
+					{{- end }}
+					{{- if $query.HasFieldMask }}
+					{{- if $query.IsConnection }}
+					req.{{ $query.FieldMaskFieldName }} = pagination.FieldMask(p.Info, "{{ $query.FieldMaskPrefix }}", {{ if $query.IsCamel }}true{{ else }}false{{ end }})
+					{{- else }}
+					req.{{ $query.FieldMaskFieldName }} = runtime.NewFieldMask(p.Info, "{{ $query.FieldMaskPrefix }}", {{ if $query.IsCamel }}true{{ else }}false{{ end }})
+					{{- end }}
+					{{- end }}
+					conn, closer, err := x.CreateConnection(p.Context)
+					if err != nil {
+						return nil, errors.Wrap(err, "Failed to create gRPC connection for nested resolver")
+					}
+					defer closer()
+					client := {{ $query.Package }}New{{ $query.Method.Service.Name }}Client(conn)
+					var resp *{{ $query.OutputType }}
+					err = runtime.Invoke(p.Context, x.backendHost(), {{ $query.CallPolicyName }}, func(ctx context.Context) (err error) {
+						resp, err = client.{{ $query.Method.Name }}(ctx, &req)
+						return err
+					})
+					if err != nil {
+						return nil, errors.Wrap(err, "Failed to call RPC {{ $query.Method.Name }}")
+					}
+					{{- if $query.IsConnection }}
+						{{- if $query.IsCamel }}
+						return pagination.Connection(runtime.MarshalResponse(resp.Get{{ $query.ConnectionItemsFieldName }}()), resp.GetNextPageToken())
+						{{- else }}
+						return pagination.Connection(resp.Get{{ $query.ConnectionItemsFieldName }}(), resp.GetNextPageToken())
+						{{- end }}
+					{{- else if $query.IsPluckResponse }}
+						{{- if $query.IsCamel }}
+						return runtime.MarshalResponse(resp.Get{{ $query.PluckResponseFieldName }}()), nil
+						{{- else }}
+						return resp.Get{{ $query.PluckResponseFieldName }}(), nil
+						{{- end }}
+					{{- else }}
+						{{- if $query.IsCamel }}
+						return runtime.MarshalResponse(resp), nil
+						{{- else }}
+						return resp, nil
+						{{- end }}
+					{{- end }}
+				},
+{{- end }}
+{{- end }}
+			},
+{{- end }}
+{{- end }}
+		},
+		Entities: []*runtime.Entity{
+{{- range $.Types }}
+{{- if .IsEntity }}
+{{- $query := .EntityResolver $.Services }}
+{{- if eq $query.Method.Service $service }}
+			{
+				Name: "{{ .GraphqlTypeName }}",
+				Keys: []string{ {{- range $i, $key := .EntityKeys }}{{ if $i }}, {{ end }}{{ printf "%q" $key }}{{ end -}} },
+				Resolve: func(ctx context.Context, representation map[string]interface{}) (interface{}, error) {
+					var req {{ $query.InputType }}
+					if err := runtime.MarshalRequest(representation, &req, {{ if $query.IsCamel }}true{{ else }}false{{ end }}); err != nil {
+						return nil, errors.Wrap(err, "Failed to marshal entity representation for {{ $query.QueryName }}")
+					}
+					conn, closer, err := x.CreateConnection(ctx)
+					if err != nil {
+						return nil, errors.Wrap(err, "Failed to create gRPC connection for entity {{ .GraphqlTypeName }}")
+					}
+					defer closer()
+					client := {{ $query.Package }}New{{ $query.Method.Service.Name }}Client(conn)
+					var resp *{{ $query.OutputType }}
+					err = runtime.Invoke(ctx, x.backendHost(), {{ $query.CallPolicyName }}, func(ctx context.Context) (err error) {
+						resp, err = client.{{ $query.Method.Name }}(ctx, &req)
+						return err
+					})
+					if err != nil {
+						return nil, errors.Wrap(err, "Failed to call RPC {{ $query.Method.Name }}")
+					}
+					{{- if $query.IsPluckResponse }}
+						{{- if $query.IsCamel }}
+						return runtime.MarshalResponse(resp.Get{{ $query.PluckResponseFieldName }}()), nil
+						{{- else }}
+						return resp.Get{{ $query.PluckResponseFieldName }}(), nil
+						{{- end }}
+					{{- else }}
+						{{- if $query.IsCamel }}
+						return runtime.MarshalResponse(resp), nil
+						{{- else }}
+						return resp, nil
+						{{- end }}
+					{{- end }}
+				},
+			},
+{{- end }}
+{{- end }}
+{{- end }}
+		},
+		Nodes: []*runtime.Node{
+{{- range $.ExternalTypes }}
+{{- if .IsNode }}
+			{Name: "{{ .GraphqlTypeName }}", IDField: "{{ .NodeIDField.FieldName }}"},
+{{- end }}
+{{- end }}
+{{- range $.Types }}
+{{- if .IsNode }}
+{{- $query := .NodeResolver $.Services }}
+			{
+				Name: "{{ .GraphqlTypeName }}",
+				IDField: "{{ .NodeIDField.FieldName }}",
+{{- if eq $query.Method.Service $service }}
+				Resolve: func(ctx context.Context, id interface{}) (interface{}, error) {
+					var req {{ $query.InputType }}
+					if err := runtime.MarshalRequest(map[string]interface{}{"{{ .NodeIDField.FieldName }}": id}, &req, {{ if $query.IsCamel }}true{{ else }}false{{ end }}); err != nil {
+						return nil, errors.Wrap(err, "Failed to marshal node ID for {{ $query.QueryName }}")
+					}
+					conn, closer, err := x.CreateConnection(ctx)
+					if err != nil {
+						return nil, errors.Wrap(err, "Failed to create gRPC connection for node {{ .GraphqlTypeName }}")
+					}
+					defer closer()
+					client := {{ $query.Package }}New{{ $query.Method.Service.Name }}Client(conn)
+					var resp *{{ $query.OutputType }}
+					err = runtime.Invoke(ctx, x.backendHost(), {{ $query.CallPolicyName }}, func(ctx context.Context) (err error) {
+						resp, err = client.{{ $query.Method.Name }}(ctx, &req)
+						return err
+					})
+					if err != nil {
+						return nil, errors.Wrap(err, "Failed to call RPC {{ $query.Method.Name }}")
+					}
+					{{- if $query.IsPluckResponse }}
+						{{- if $query.IsCamel }}
+						return runtime.MarshalResponse(resp.Get{{ $query.PluckResponseFieldName }}()), nil
+						{{- else }}
+						return resp.Get{{ $query.PluckResponseFieldName }}(), nil
+						{{- end }}
+					{{- else }}
+						{{- if $query.IsCamel }}
+						return runtime.MarshalResponse(resp), nil
+						{{- else }}
+						return resp, nil
+						{{- end }}
+					{{- end }}
+				},
+{{- end }}
+			},
+{{- end }}
+{{- end }}
+		},
+{{- if $.AnyTypes }}
+		AnyTypes: map[string]string{
+{{- range $.AnyTypes }}
+			"{{ .FullPath }}": "{{ .GraphqlTypeName }}",
+{{- end }}
+		},
+{{- end }}
+	}
+}
+
+// GetQueries returns acceptable runtime.Fields for Query.
+func (x *graphql__resolver_{{ $service.Name }}) GetQueries() runtime.Fields {
+	return runtime.Fields{
 {{- range .Queries }}
 	{{- if not .IsResolver }}
-	   "{{ .QueryName }}": &graphql.Field{
-			Type: {{ .QueryType }},
+		"{{ .QueryName }}": &runtime.Field{
 			{{- if .Comment }}
 			Description: ` + "`" + `{{ .Comment }}` + "`" + `,
 			{{- end }}
-			Args: graphql.FieldConfigArgument{
-			{{- range .Args }}
-				"{{ .FieldName }}": &graphql.ArgumentConfig{
-					Type: {{ .FieldTypeInput $.RootPackage.Name }},
-					{{- if .Comment }}
-					Description: ` + "`" + `{{ .Comment }}` + "`" + `,
-					{{- end }}
-					{{- if .DefaultValue }}
-					DefaultValue: {{ .DefaultValue }},
-					{{- end }}
-				},
-			{{- end }}
-			},
-			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			Args: ` + "`" + `{{ .SchemaArgs }}` + "`" + `,
+			Type: ` + "`" + `{{ .QueryType }}` + "`" + `,
+			Resolve: func(p runtime.ResolveParams) (interface{}, error) {
+				{{- if .IsConnection }}
+				pagination, err := runtime.NewPagination(p.Args)
+				if err != nil {
+					return nil, err
+				}
+				if c, ok := pagination.Empty(); ok {
+					return c, nil
+				}
+				{{- end }}
 				var req {{ .InputType }}
 				if err := runtime.MarshalRequest(p.Args, &req, {{ if .IsCamel }}true{{ else }}false{{ end }}); err != nil {
 					return nil, errors.Wrap(err, "Failed to marshal request for {{ .QueryName }}")
 				}
+				{{- if .IsConnection }}
+				if err := runtime.MarshalRequest(pagination.Request(), &req, false); err != nil {
+					return nil, errors.Wrap(err, "Failed to marshal pagination for {{ .QueryName }}")
+				}
+				{{- end }}
+				{{- if .HasFieldMask }}
+				{{- if .IsConnection }}
+				req.{{ .FieldMaskFieldName }} = pagination.FieldMask(p.Info, "{{ .FieldMaskPrefix }}", {{ if .IsCamel }}true{{ else }}false{{ end }})
+				{{- else }}
+				req.{{ .FieldMaskFieldName }} = runtime.NewFieldMask(p.Info, "{{ .FieldMaskPrefix }}", {{ if .IsCamel }}true{{ else }}false{{ end }})
+				{{- end }}
+				{{- end }}
+				conn, closer, err := x.CreateConnection(p.Context)
+				if err != nil {
+					return nil, errors.Wrap(err, "Failed to create gRPC connection for {{ .QueryName }}")
+				}
+				defer closer()
 				client := New{{ .Method.Service.Name }}Client(conn)
-				resp, err := client.{{ .Method.Name }}(p.Context, &req)
+				var resp *{{ .OutputType }}
+				err = runtime.Invoke(p.Context, x.backendHost(), {{ .CallPolicyName }}, func(ctx context.Context) (err error) {
+					resp, err = client.{{ .Method.Name }}(ctx, &req)
+					return err
+				})
 				if err != nil {
 					return nil, errors.Wrap(err, "Failed to call RPC {{ .Method.Name }}")
 				}
-				{{- if .IsPluckResponse }}
+				{{- if .IsConnection }}
+					{{- if .IsCamel }}
+					return pagination.Connection(runtime.MarshalResponse(resp.Get{{ .ConnectionItemsFieldName }}()), resp.GetNextPageToken())
+					{{- else }}
+					return pagination.Connection(resp.Get{{ .ConnectionItemsFieldName }}(), resp.GetNextPageToken())
+					{{- end }}
+				{{- else if .IsPluckResponse }}
 					{{- if .IsCamel }}
 					return runtime.MarshalResponse(resp.Get{{ .PluckResponseFieldName }}()), nil
 					{{- else }}
@@ -287,41 +420,23 @@ func (x *graphql__resolver_{{ $service.Name }}) GetQueries(conn *grpc.ClientConn
 					{{- end }}
 				{{- end }}
 			},
-	   },
+		},
 	{{- end }}
 {{- end }}
 	}
 }
 
-// GetMutations returns acceptable graphql.Fields for Mutation.
-func (x *graphql__resolver_{{ $service.Name }}) GetMutations(conn *grpc.ClientConn) graphql.Fields {
-	return graphql.Fields{
+// GetMutations returns acceptable runtime.Fields for Mutation.
+func (x *graphql__resolver_{{ $service.Name }}) GetMutations() runtime.Fields {
+	return runtime.Fields{
 {{- range .Mutations }}
-		"{{ .MutationName }}": &graphql.Field{
-			Type: {{ .MutationType }},
+		"{{ .MutationName }}": &runtime.Field{
 			{{- if .Comment }}
 			Description: ` + "`" + `{{ .Comment }}` + "`" + `,
-			{{ end }}
-			Args: graphql.FieldConfigArgument{
-			{{- if .InputName }}
-				"{{ .InputName }}": &graphql.ArgumentConfig{
-					Type: graphql.NewNonNull(Gql__input_{{ .Input.TypeName }}()),
-				},
-			{{- else }}
-			{{- range .Args }}
-				"{{ .FieldName }}": &graphql.ArgumentConfig{
-					Type: {{ .FieldTypeInput $.RootPackage.Name }},
-					{{- if .Comment }}
-					Description: ` + "`" + `{{ .Comment }}` + "`" + `,
-					{{- end }}
-					{{- if .DefaultValue }}
-					DefaultValue: {{ .DefaultValue }},
-					{{- end }}
-				},
-			{{- end }}
 			{{- end }}
-			},
-			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			Args: ` + "`" + `{{ .SchemaArgs }}` + "`" + `,
+			Type: ` + "`" + `{{ .MutationType }}` + "`" + `,
+			Resolve: func(p runtime.ResolveParams) (interface{}, error) {
 				var req {{ .InputType }}
 				{{- if .InputName }}
 				if err := runtime.MarshalRequest(p.Args["{{ .InputName }}"], &req, {{ if .IsCamel }}true{{ else }}false{{ end }}); err != nil {
@@ -330,8 +445,22 @@ func (x *graphql__resolver_{{ $service.Name }}) GetMutations(conn *grpc.ClientCo
 				{{- end }}
 					return nil, errors.Wrap(err, "Failed to marshal request for {{ .MutationName }}")
 				}
+				{{- if .HasUpdateMask }}
+				if req.{{ .UpdateMaskFieldName }} == nil {
+					req.{{ .UpdateMaskFieldName }} = runtime.NewUpdateMask(p.Info, "{{ .UpdateMaskArgument }}", {{ if .IsCamel }}true{{ else }}false{{ end }})
+				}
+				{{- end }}
+				conn, closer, err := x.CreateConnection(p.Context)
+				if err != nil {
+					return nil, errors.Wrap(err, "Failed to create gRPC connection for {{ .MutationName }}")
+				}
+				defer closer()
 				client := New{{ $service.Name }}Client(conn)
-				resp, err := client.{{ .Method.Name }}(p.Context, &req)
+				var resp *{{ .OutputType }}
+				err = runtime.Invoke(p.Context, x.backendHost(), {{ .CallPolicyName }}, func(ctx context.Context) (err error) {
+					resp, err = client.{{ .Method.Name }}(ctx, &req)
+					return err
+				})
 				if err != nil {
 					return nil, errors.Wrap(err, "Failed to call RPC {{ .Method.Name }}")
 				}
@@ -350,7 +479,7 @@ func (x *graphql__resolver_{{ $service.Name }}) GetMutations(conn *grpc.ClientCo
 				{{- end }}
 			},
 		},
-{{ end }}
+{{- end }}
 	}
 }
 
@@ -358,8 +487,9 @@ func (x *graphql__resolver_{{ $service.Name }}) GetMutations(conn *grpc.ClientCo
 // therefore gRPC connection will be opened and closed automatically.
 // Occasionally you may worry about open/close performance for each handling graphql request,
 // then you can call Register{{ .Name }}GraphqlHandler with *grpc.ClientConn manually.
-func Register{{ .Name }}Graphql(mux *runtime.ServeMux) error {
-	return Register{{ .Name }}GraphqlHandler(mux, nil)
+// opts are applied to the handler when it is added to mux, e.g. runtime.WithNamespace.
+func Register{{ .Name }}Graphql(mux *runtime.ServeMux, opts ...runtime.HandlerOption) error {
+	return Register{{ .Name }}GraphqlHandler(mux, nil, opts...)
 }
 
 // Register package divided graphql handler "with" *grpc.ClientConn.
@@ -375,8 +505,8 @@ func Register{{ .Name }}Graphql(mux *runtime.ServeMux) error {
 //
 //    ...with RPC definitions
 // }
-func Register{{ .Name }}GraphqlHandler(mux *runtime.ServeMux, conn *grpc.ClientConn) error {
-	return mux.AddHandler(new_graphql_resolver_{{ .Name }}(conn))
+func Register{{ .Name }}GraphqlHandler(mux *runtime.ServeMux, conn *grpc.ClientConn, opts ...runtime.HandlerOption) error {
+	return mux.AddHandler(new_graphql_resolver_{{ .Name }}(conn), opts...)
 }
 
 {{ end }}
@@ -0,0 +1,66 @@
+package dynamic
+
+import (
+	"crypto/tls"
+	"fmt"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/nebucloud/nebucloud-gateway/spec"
+)
+
+// hostConn is a client of the host which graphql.service option declares.
+// It is shared by handlers of services on the same host, and closed when all of them are closed.
+type hostConn struct {
+	conn *grpc.ClientConn
+
+	mu   sync.Mutex
+	refs int
+}
+
+func (c *hostConn) acquire() *grpc.ClientConn {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.refs++
+	return c.conn
+}
+
+func (c *hostConn) release() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.refs--
+	if c.refs > 0 {
+		return nil
+	}
+	return c.conn.Close()
+}
+
+// hostConns opens one client for each host while handlers are built,
+// like generated handlers connect to the host of graphql.service option when connection is not provided.
+type hostConns map[string]*hostConn
+
+func (cs hostConns) get(s *spec.Service, name string) (*hostConn, error) {
+	host := s.Host()
+	if host == "" {
+		return nil, fmt.Errorf("host of %s is not declared by graphql.service option", name)
+	}
+	key := fmt.Sprintf("%s insecure=%t", host, s.Insecure())
+	if c, ok := cs[key]; ok {
+		return c, nil
+	}
+
+	creds := credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
+	if s.Insecure() {
+		creds = insecure.NewCredentials()
+	}
+	conn, err := grpc.NewClient(host, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, err
+	}
+	c := &hostConn{conn: conn}
+	cs[key] = c
+	return c, nil
+}
@@ -0,0 +1,204 @@
+// Package dynamic builds GraphQL handlers from descriptors which backends expose through gRPC server reflection,
+// so that gateway can serve services without code generated by protoc-gen-graphql.
+// Schema is built by the same rules as protoc-gen-graphql including graphql.proto options,
+// and RPCs are called with dynamicpb messages.
+package dynamic
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/nebucloud/nebucloud-gateway/generator"
+	"github.com/nebucloud/nebucloud-gateway/runtime"
+	"github.com/nebucloud/nebucloud-gateway/spec"
+)
+
+type options struct {
+	fieldCamelCase  bool
+	dateTime        bool
+	int64AsInt      bool
+	wrapperAsObject bool
+	// connections of services which are served by other hosts, keyed by full name of service
+	serviceConns map[string]*grpc.ClientConn
+}
+
+// Option configures building handlers
+type Option func(*options)
+
+// WithFieldCamelCase transforms field names to lower camel case, same as field_camel parameter of protoc-gen-graphql
+func WithFieldCamelCase() Option {
+	return func(o *options) {
+		o.fieldCamelCase = true
+	}
+}
+
+// WithDateTime maps google.protobuf.Timestamp to DateTime scalar, same as datetime parameter of protoc-gen-graphql
+func WithDateTime() Option {
+	return func(o *options) {
+		o.dateTime = true
+	}
+}
+
+// WithInt64AsInt maps 64-bit integers to Int instead of Int64 and UInt64 scalars for compatibility with existing clients,
+// same as int64=int parameter of protoc-gen-graphql. Values beyond 32-bit raise field errors then because Int is 32-bit.
+func WithInt64AsInt() Option {
+	return func(o *options) {
+		o.int64AsInt = true
+	}
+}
+
+// WithWrappersAsObject maps google.protobuf wrappers like Int32Value to object types of value field
+// instead of nullable scalars for compatibility with existing clients, same as wrappers=object parameter of protoc-gen-graphql.
+func WithWrappersAsObject() Option {
+	return func(o *options) {
+		o.wrapperAsObject = true
+	}
+}
+
+// WithServiceConn calls RPCs of the service by conn instead of the connection which handlers are built with,
+// where the service is served by another host than the one descriptors are loaded from.
+// service is fully qualified name like "billing.v1.InvoiceService", and caller is responsible for closing conn.
+func WithServiceConn(service string, conn *grpc.ClientConn) Option {
+	return func(o *options) {
+		if o.serviceConns == nil {
+			o.serviceConns = make(map[string]*grpc.ClientConn)
+		}
+		o.serviceConns[service] = conn
+	}
+}
+
+// NewHandlers pulls descriptors from the backend through server reflection,
+// and returns handlers of services which declare queries or mutations.
+// Connection is used for both of reflection and RPC calls, and caller is responsible for closing it.
+func NewHandlers(ctx context.Context, conn *grpc.ClientConn, opts ...Option) ([]runtime.GraphqlHandler, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	services, descriptors, err := fetchDescriptors(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+	return buildHandlers(conn, serviceFiles(descriptors, services), descriptors, &o)
+}
+
+// Register builds handlers of the backend and adds them to mux
+func Register(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn, opts ...Option) error {
+	handlers, err := NewHandlers(ctx, conn, opts...)
+	if err != nil {
+		return err
+	}
+	for _, h := range handlers {
+		if err := mux.AddHandler(h); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// buildHandlers analyzes descriptors by the same rules as protoc-gen-graphql,
+// and makes handlers of services defined in targets files.
+func buildHandlers(
+	conn *grpc.ClientConn,
+	targets []string,
+	descriptors []*descriptorpb.FileDescriptorProto,
+	o *options,
+) ([]runtime.GraphqlHandler, error) {
+
+	registry, err := protodesc.NewFiles(&descriptorpb.FileDescriptorSet{File: descriptors})
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve descriptors: %w", err)
+	}
+	params := &spec.Params{FieldCamelCase: o.fieldCamelCase, DateTime: o.dateTime}
+	if o.int64AsInt {
+		params.Int64 = "int"
+	}
+	if o.wrapperAsObject {
+		params.Wrappers = "object"
+	}
+	var files []*spec.File
+	for _, d := range descriptors {
+		files = append(files, spec.NewFile(d, nil, params))
+	}
+
+	g := generator.New(files, params)
+	templates, err := g.Analyze(targets)
+	if err != nil {
+		return nil, err
+	}
+
+	var all []*spec.Service
+	for _, t := range templates {
+		all = append(all, t.Services...)
+	}
+
+	var handlers []runtime.GraphqlHandler
+	fail := func(err error) ([]runtime.GraphqlHandler, error) {
+		for _, h := range handlers {
+			h.(*handler).Close() // nolint: errcheck
+		}
+		return nil, err
+	}
+	conns := hostConns{}
+	for _, t := range templates {
+		for _, s := range t.Services {
+			h, err := newHandler(&handler{
+				conn:            conn,
+				service:         s,
+				template:        t,
+				services:        all,
+				files:           registry,
+				isCamel:         o.fieldCamelCase,
+				wrapperAsObject: o.wrapperAsObject,
+			})
+			if err != nil {
+				return fail(err)
+			}
+			if sc, ok := o.serviceConns[h.Name()]; ok {
+				h.conn = sc
+			} else if conn == nil {
+				c, err := conns.get(s, h.Name())
+				if err != nil {
+					return fail(err)
+				}
+				h.hostConn = c
+				h.conn = c.acquire()
+			}
+			handlers = append(handlers, h)
+		}
+	}
+	return handlers, nil
+}
+
+// serviceFiles returns names of files which define services.
+// All files which have any service are returned when services is nil.
+func serviceFiles(descriptors []*descriptorpb.FileDescriptorProto, services []string) []string {
+	var files []string
+	for _, d := range descriptors {
+		for _, s := range d.GetService() {
+			name := s.GetName()
+			if pkg := d.GetPackage(); pkg != "" {
+				name = pkg + "." + name
+			}
+			if services == nil || contains(services, name) {
+				files = append(files, d.GetName())
+				break
+			}
+		}
+	}
+	return files
+}
+
+func contains(values []string, v string) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,488 @@
+package dynamic
+
+import (
+	"context"
+	"net"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/reflection"
+	reflectionv1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	graphqlv1 "github.com/nebucloud/nebucloud-gateway/gen/go/graphql/v1"
+	"github.com/nebucloud/nebucloud-gateway/runtime"
+)
+
+// testProto is users.proto which is served only through reflection, no Go code is generated for it
+const testProto = `
+name: "users/users.proto"
+package: "users"
+dependency: ["graphql/v1/graphql.proto", "google/protobuf/timestamp.proto"]
+options { go_package: "example.com/users;users" }
+service {
+  name: "UserService"
+  method {
+    name: "GetUser" input_type: ".users.GetUserRequest" output_type: ".users.User"
+    options { [graphql.v1.schema] { name: "user" } }
+  }
+  method {
+    name: "CreateUser" input_type: ".users.CreateUserRequest" output_type: ".users.User"
+    options { [graphql.v1.schema] { type: GRAPHQL_TYPE_MUTATION name: "createUser" request { name: "input" } } }
+  }
+  method {
+    name: "ListPosts" input_type: ".users.ListPostsRequest" output_type: ".users.ListPostsResponse"
+    options { [graphql.v1.schema] { type: GRAPHQL_TYPE_RESOLVER name: "posts" response { pluck: "posts" } } }
+  }
+}
+message_type {
+  name: "User"
+  options { [graphql.v1.entity] { keys: "id" resolver: "user" } }
+  field { name: "id" number: 1 label: LABEL_OPTIONAL type: TYPE_INT64 }
+  field { name: "name" number: 2 label: LABEL_OPTIONAL type: TYPE_STRING options { [graphql.v1.field] { required: true } } }
+  field { name: "role" number: 3 label: LABEL_OPTIONAL type: TYPE_ENUM type_name: ".users.Role" }
+  field { name: "created_at" number: 4 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".google.protobuf.Timestamp" }
+  field { name: "posts" number: 5 label: LABEL_REPEATED type: TYPE_MESSAGE type_name: ".users.Post" options { [graphql.v1.field] { resolver: "posts" } } }
+  field { name: "labels" number: 6 label: LABEL_REPEATED type: TYPE_MESSAGE type_name: ".users.User.LabelsEntry" }
+  nested_type {
+    name: "LabelsEntry"
+    field { name: "key" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING }
+    field { name: "value" number: 2 label: LABEL_OPTIONAL type: TYPE_STRING }
+    options { map_entry: true }
+  }
+}
+message_type {
+  name: "Post"
+  field { name: "title" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING }
+}
+message_type {
+  name: "GetUserRequest"
+  field { name: "id" number: 1 label: LABEL_OPTIONAL type: TYPE_INT64 options { [graphql.v1.field] { required: true } } }
+}
+message_type {
+  name: "CreateUserRequest"
+  field { name: "name" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING options { [graphql.v1.field] { required: true } } }
+  field { name: "role" number: 2 label: LABEL_OPTIONAL type: TYPE_ENUM type_name: ".users.Role" }
+}
+message_type {
+  name: "ListPostsRequest"
+  field { name: "id" number: 1 label: LABEL_OPTIONAL type: TYPE_INT64 }
+}
+message_type {
+  name: "ListPostsResponse"
+  field { name: "posts" number: 1 label: LABEL_REPEATED type: TYPE_MESSAGE type_name: ".users.Post" }
+}
+enum_type {
+  name: "Role"
+  value { name: "ROLE_UNSPECIFIED" number: 0 }
+  value { name: "ADMIN" number: 1 }
+}
+syntax: "proto3"
+`
+
+// startTestServer serves UserService with reflection, messages are handled with dynamicpb
+func startTestServer(t *testing.T) *grpc.ClientConn {
+	return startTestServerWithProto(t, testProto)
+}
+
+// startTestServerWithProto serves UserService which is defined by descriptor text of users.proto
+func startTestServerWithProto(t *testing.T, text string) *grpc.ClientConn {
+	var fdp descriptorpb.FileDescriptorProto
+	assert.NoError(t, prototext.Unmarshal([]byte(text), &fdp))
+	fd, err := protodesc.NewFile(&fdp, protoregistry.GlobalFiles)
+	assert.NoError(t, err)
+
+	files := new(protoregistry.Files)
+	for _, f := range []protoreflect.FileDescriptor{
+		fd,
+		graphqlv1.File_graphql_v1_graphql_proto,
+		descriptorpb.File_google_protobuf_descriptor_proto,
+		timestamppb.File_google_protobuf_timestamp_proto,
+		anypb.File_google_protobuf_any_proto,
+		fieldmaskpb.File_google_protobuf_field_mask_proto,
+		wrapperspb.File_google_protobuf_wrappers_proto,
+	} {
+		assert.NoError(t, files.RegisterFile(f))
+	}
+
+	sd := fd.Services().ByName("UserService")
+	method := func(name string, fn func(req, resp *dynamicpb.Message) error) grpc.MethodDesc {
+		md := sd.Methods().ByName(protoreflect.Name(name))
+		return grpc.MethodDesc{
+			MethodName: name,
+			Handler: func(_ interface{}, _ context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+				req, resp := dynamicpb.NewMessage(md.Input()), dynamicpb.NewMessage(md.Output())
+				if err := dec(req); err != nil {
+					return nil, err
+				}
+				return resp, fn(req, resp)
+			},
+		}
+	}
+	field := func(m *dynamicpb.Message, name string) protoreflect.FieldDescriptor {
+		return m.Descriptor().Fields().ByName(protoreflect.Name(name))
+	}
+	setUser := func(resp *dynamicpb.Message, id int64, name string, role protoreflect.EnumNumber) {
+		resp.Set(field(resp, "id"), protoreflect.ValueOfInt64(id))
+		resp.Set(field(resp, "name"), protoreflect.ValueOfString(name))
+		resp.Set(field(resp, "role"), protoreflect.ValueOfEnum(role))
+	}
+
+	s := grpc.NewServer()
+	s.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "users.UserService",
+		HandlerType: (*interface{})(nil),
+		Methods: []grpc.MethodDesc{
+			method("GetUser", func(req, resp *dynamicpb.Message) error {
+				if req.Get(field(req, "id")).Int() != 1 {
+					return status.Error(codes.NotFound, "user not found")
+				}
+				setUser(resp, 1, "alice", 1)
+				if fd := field(req, "read_mask"); fd != nil {
+					mask := req.Get(fd).Message()
+					paths := mask.Get(mask.Descriptor().Fields().ByName("paths")).List()
+					var names []string
+					for i := 0; i < paths.Len(); i++ {
+						names = append(names, paths.Get(i).String())
+					}
+					resp.Set(field(resp, "name"), protoreflect.ValueOfString(strings.Join(names, ",")))
+				}
+				if fd := field(resp, "nickname"); fd != nil {
+					nickname := resp.Mutable(fd).Message()
+					nickname.Set(nickname.Descriptor().Fields().ByName("value"), protoreflect.ValueOfString("ally"))
+				}
+				createdAt := resp.Mutable(field(resp, "created_at")).Message()
+				createdAt.Set(createdAt.Descriptor().Fields().ByName("seconds"), protoreflect.ValueOfInt64(1700000000))
+				labels := resp.Mutable(field(resp, "labels")).Map()
+				labels.Set(protoreflect.ValueOfString("team").MapKey(), protoreflect.ValueOfString("core"))
+				for name, title := range map[string]string{"payload": "pinned", "extra": "draft"} {
+					if fd := field(resp, name); fd != nil {
+						post := dynamicpb.NewMessage(fd.ParentFile().Messages().ByName("Post"))
+						post.Set(post.Descriptor().Fields().ByName("title"), protoreflect.ValueOfString(title))
+						value, err := proto.Marshal(post)
+						if err != nil {
+							return err
+						}
+						a := resp.Mutable(fd).Message()
+						a.Set(a.Descriptor().Fields().ByName("type_url"), protoreflect.ValueOfString("type.googleapis.com/users.Post"))
+						a.Set(a.Descriptor().Fields().ByName("value"), protoreflect.ValueOfBytes(value))
+					}
+				}
+				return nil
+			}),
+			method("CreateUser", func(req, resp *dynamicpb.Message) error {
+				setUser(resp, 2, req.Get(field(req, "name")).String(), req.Get(field(req, "role")).Enum())
+				if fd := field(req, "nickname"); fd != nil && req.Has(fd) {
+					resp.Set(field(resp, "nickname"), req.Get(fd))
+				}
+				return nil
+			}),
+			method("UpdateUser", func(req, resp *dynamicpb.Message) error {
+				user := req.Get(field(req, "user")).Message()
+				mask := req.Get(field(req, "update_mask")).Message()
+				paths := mask.Get(mask.Descriptor().Fields().ByName("paths")).List()
+				var names []string
+				for i := 0; i < paths.Len(); i++ {
+					names = append(names, paths.Get(i).String())
+				}
+				setUser(resp, user.Get(user.Descriptor().Fields().ByName("id")).Int(), strings.Join(names, ","), 0)
+				return nil
+			}),
+			method("ListPosts", func(req, resp *dynamicpb.Message) error {
+				posts := resp.Mutable(field(resp, "posts")).List()
+				post := posts.NewElement()
+				post.Message().Set(post.Message().Descriptor().Fields().ByName("title"), protoreflect.ValueOfString("post of "+req.Get(field(req, "id")).String()))
+				posts.Append(post)
+				if fd := field(resp, "next_page_token"); fd != nil {
+					resp.Set(fd, protoreflect.ValueOfString("next"))
+				}
+				return nil
+			}),
+		},
+	}, nil)
+	reflectionv1.RegisterServerReflectionServer(s, reflection.NewServerV1(reflection.ServerOptions{
+		Services:           s,
+		DescriptorResolver: files,
+	}))
+
+	lis := bufconn.Listen(1024 * 1024)
+	go s.Serve(lis) // nolint: errcheck
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func serveTestQuery(mux *runtime.ServeMux, query string) string {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/graphql", strings.NewReader(`{"query":"`+query+`"}`))
+	mux.ServeHTTP(w, r)
+	return strings.TrimSpace(w.Body.String())
+}
+
+func TestDynamicHandlers(t *testing.T) {
+	conn := startTestServer(t)
+	mux := runtime.NewServeMux()
+	assert.NoError(t, Register(context.Background(), mux, conn))
+
+	assert.JSONEq(t, `{"data":{"user":{
+  "id": "1",
+  "name": "alice",
+  "role": "ADMIN",
+  "created_at": {"seconds": 1700000000},
+  "labels": [{"key": "team", "value": "core"}],
+  "posts": [{"title": "post of 1"}]
+}}}`, serveTestQuery(mux, `{ user(id: \"1\") { id name role created_at { seconds } labels { key value } posts { title } } }`))
+
+	assert.JSONEq(t, `{"data":{"createUser":{"id":"2","name":"bob","role":"ADMIN"}}}`,
+		serveTestQuery(mux, `mutation { createUser(input: { name: \"bob\", role: ADMIN }) { id name role } }`))
+
+	assert.JSONEq(t, `{"data":{"user":null},"errors":[{
+  "message": "user not found",
+  "path": ["user"],
+  "locations": [{"line": 1, "column": 3}],
+  "extensions": {"code": "NOT_FOUND"}
+}]}`, serveTestQuery(mux, `{ user(id: 2) { name } }`))
+}
+
+func TestDynamicHandlersWithFieldCamelCase(t *testing.T) {
+	conn := startTestServer(t)
+	handlers, err := NewHandlers(context.Background(), conn, WithFieldCamelCase())
+	assert.NoError(t, err)
+	assert.Len(t, handlers, 1)
+
+	mux := runtime.NewServeMux()
+	assert.NoError(t, mux.ReplaceHandlers(handlers...))
+	assert.JSONEq(t, `{"data":{"user":{"createdAt":{"seconds":1700000000}}}}`,
+		serveTestQuery(mux, `{ user(id: 1) { createdAt { seconds } } }`))
+}
+
+func TestDynamicHandlersDateTime(t *testing.T) {
+	conn := startTestServer(t)
+	mux := runtime.NewServeMux()
+	assert.NoError(t, Register(context.Background(), mux, conn, WithDateTime()))
+	assert.JSONEq(t, `{"data":{"user":{"created_at":"2023-11-14T22:13:20Z"}}}`,
+		serveTestQuery(mux, `{ user(id: \"1\") { created_at } }`))
+
+	// Timestamp which DateTime scalar is parsed into is set into dynamic message
+	var fdp descriptorpb.FileDescriptorProto
+	assert.NoError(t, prototext.Unmarshal([]byte(testProto), &fdp))
+	fd, err := protodesc.NewFile(&fdp, protoregistry.GlobalFiles)
+	assert.NoError(t, err)
+	user := dynamicpb.NewMessage(fd.Messages().ByName("User"))
+	assert.NoError(t, (&handler{}).setMessage(user, map[string]interface{}{
+		"created_at": timestamppb.New(time.Unix(1700000000, 5)),
+	}))
+	createdAt := user.Get(user.Descriptor().Fields().ByName("created_at")).Message()
+	assert.Equal(t, int64(1700000000), createdAt.Get(createdAt.Descriptor().Fields().ByName("seconds")).Int())
+	assert.Equal(t, int64(5), createdAt.Get(createdAt.Descriptor().Fields().ByName("nanos")).Int())
+}
+
+func TestDynamicHandlersInt64AsInt(t *testing.T) {
+	conn := startTestServer(t)
+	mux := runtime.NewServeMux()
+	assert.NoError(t, Register(context.Background(), mux, conn, WithInt64AsInt()))
+
+	assert.JSONEq(t, `{"data":{"user":{"id":1,"name":"alice"}}}`, serveTestQuery(mux, `{ user(id: 1) { id name } }`))
+	assert.JSONEq(t, `{"data":{"__type":null}}`, serveTestQuery(mux, `{ __type(name: \"Int64\") { name } }`))
+}
+
+func TestDynamicJSONMessages(t *testing.T) {
+	// Value of JSON scalar is converted to Struct which the field requires
+	value := dynamicpb.NewMessage((&structpb.Value{}).ProtoReflect().Descriptor())
+	input, err := structpb.NewValue(map[string]interface{}{"name": "alice", "tags": []interface{}{"a"}})
+	assert.NoError(t, err)
+	assert.NoError(t, (&handler{}).setMessage(value, map[string]interface{}{"struct_value": input}))
+	assert.Error(t, (&handler{}).setMessage(value, map[string]interface{}{"list_value": input}))
+
+	// and converted back to generated type so that JSON scalar serializes it
+	out, ok := (&handler{}).messageToMap(value)["struct_value"].(*structpb.Struct)
+	assert.True(t, ok)
+	assert.Equal(t, map[string]interface{}{"name": "alice", "tags": []interface{}{"a"}}, out.AsMap())
+}
+
+func TestDynamicHandlersAny(t *testing.T) {
+	text := strings.NewReplacer(
+		`"google/protobuf/timestamp.proto"]`, `"google/protobuf/timestamp.proto", "google/protobuf/any.proto"]`,
+		`  field { name: "labels"`, `  field { name: "payload" number: 7 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".google.protobuf.Any" options { [graphql.v1.field] { any_types: "users.Post" } } }
+  field { name: "extra" number: 8 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".google.protobuf.Any" }
+  field { name: "labels"`,
+	).Replace(testProto)
+	conn := startTestServerWithProto(t, text)
+	mux := runtime.NewServeMux()
+	assert.NoError(t, Register(context.Background(), mux, conn))
+
+	assert.JSONEq(t, `{"data":{"user":{
+  "payload": {"__typename": "Users_Type_Post", "title": "pinned"},
+  "extra": {"@type": "type.googleapis.com/users.Post", "title": "draft"}
+}}}`, serveTestQuery(mux, `{ user(id: \"1\") { payload { __typename ... on Users_Type_Post { title } } extra } }`))
+}
+
+func TestDynamicHandlersFieldMask(t *testing.T) {
+	text := strings.NewReplacer(
+		`"google/protobuf/timestamp.proto"]`, `"google/protobuf/timestamp.proto", "google/protobuf/field_mask.proto"]`,
+		`[graphql.v1.schema] { name: "user" }`, `[graphql.v1.schema] { name: "user" request { field_mask: "read_mask" } }`,
+		`name: "GetUserRequest"`, `name: "GetUserRequest"
+  field { name: "read_mask" number: 2 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".google.protobuf.FieldMask" }`,
+	).Replace(testProto)
+	conn := startTestServerWithProto(t, text)
+	handlers, err := NewHandlers(context.Background(), conn, WithFieldCamelCase())
+	assert.NoError(t, err)
+	mux := runtime.NewServeMux()
+	assert.NoError(t, mux.ReplaceHandlers(handlers...))
+
+	// read_mask is not exposed as argument, backend receives paths of selected fields
+	assert.Contains(t, serveTestQuery(mux, `{ user(id: 1, readMask: \"name\") { name } }`), `Unknown argument`)
+	assert.JSONEq(t, `{"data":{"user":{"__typename":"Users_Type_User","name":"name,created_at.seconds","createdAt":{"seconds":1700000000}}}}`,
+		serveTestQuery(mux, `{ user(id: 1) { __typename name createdAt { seconds } } }`))
+}
+
+func TestDynamicHandlersUpdateMask(t *testing.T) {
+	text := strings.NewReplacer(
+		`"google/protobuf/timestamp.proto"]`, `"google/protobuf/timestamp.proto", "google/protobuf/field_mask.proto"]`,
+		`  method {
+    name: "ListPosts"`, `  method {
+    name: "UpdateUser" input_type: ".users.UpdateUserRequest" output_type: ".users.User"
+    options { [graphql.v1.schema] { type: GRAPHQL_TYPE_MUTATION name: "updateUser" request { name: "input" update_mask: "update_mask" } } }
+  }
+  method {
+    name: "ListPosts"`,
+		`message_type {
+  name: "ListPostsRequest"`, `message_type {
+  name: "UpdateUserRequest"
+  field { name: "user" number: 1 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".users.User" }
+  field { name: "update_mask" number: 2 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".google.protobuf.FieldMask" }
+}
+message_type {
+  name: "ListPostsRequest"`,
+	).Replace(testProto)
+	conn := startTestServerWithProto(t, text)
+	mux := runtime.NewServeMux()
+	assert.NoError(t, Register(context.Background(), mux, conn))
+
+	// backend receives paths of provided fields, relative to the user
+	assert.JSONEq(t, `{"data":{"updateUser":{"name":"name,role,created_at.seconds,created_at.nanos"}}}`,
+		serveTestQuery(mux, `mutation { updateUser(input: { user: { name: \"bob\", role: null, created_at: { seconds: 1, nanos: 0 } } }) { name } }`))
+	assert.JSONEq(t, `{"data":{"updateUser":{"name":"*"}}}`,
+		serveTestQuery(mux, `mutation { updateUser(input: { user: { name: \"bob\" }, update_mask: \"*\" }) { name } }`))
+}
+
+// wrappersTestProto adds fields of google.protobuf wrappers to testProto
+func wrappersTestProto() string {
+	return strings.NewReplacer(
+		`"google/protobuf/timestamp.proto"]`, `"google/protobuf/timestamp.proto", "google/protobuf/wrappers.proto"]`,
+		`  field { name: "labels"`, `  field { name: "nickname" number: 7 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".google.protobuf.StringValue" }
+  field { name: "age" number: 8 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".google.protobuf.Int32Value" }
+  field { name: "labels"`,
+		`  field { name: "role" number: 2 label: LABEL_OPTIONAL type: TYPE_ENUM type_name: ".users.Role" }
+}`, `  field { name: "role" number: 2 label: LABEL_OPTIONAL type: TYPE_ENUM type_name: ".users.Role" }
+  field { name: "nickname" number: 3 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".google.protobuf.StringValue" }
+}`,
+	).Replace(testProto)
+}
+
+func TestDynamicHandlersWrappers(t *testing.T) {
+	conn := startTestServerWithProto(t, wrappersTestProto())
+	mux := runtime.NewServeMux()
+	assert.NoError(t, Register(context.Background(), mux, conn))
+
+	// wrappers are nullable scalars of their value
+	assert.JSONEq(t, `{"data":{"user":{"nickname":"ally","age":null}}}`,
+		serveTestQuery(mux, `{ user(id: 1) { nickname age } }`))
+	assert.JSONEq(t, `{"data":{"createUser":{"nickname":""}}}`,
+		serveTestQuery(mux, `mutation { createUser(input: { name: \"bob\", nickname: \"\" }) { nickname } }`))
+	assert.JSONEq(t, `{"data":{"createUser":{"nickname":null}}}`,
+		serveTestQuery(mux, `mutation { createUser(input: { name: \"bob\", nickname: null }) { nickname } }`))
+}
+
+func TestDynamicHandlersWrappersAsObject(t *testing.T) {
+	conn := startTestServerWithProto(t, wrappersTestProto())
+	mux := runtime.NewServeMux()
+	assert.NoError(t, Register(context.Background(), mux, conn, WithWrappersAsObject()))
+
+	// wrappers are objects of value field
+	assert.JSONEq(t, `{"data":{"user":{"nickname":{"value":"ally"},"age":null}}}`,
+		serveTestQuery(mux, `{ user(id: 1) { nickname { value } age { value } } }`))
+	assert.JSONEq(t, `{"data":{"createUser":{"nickname":{"value":"bob"}}}}`,
+		serveTestQuery(mux, `mutation { createUser(input: { name: \"bob\", nickname: { value: \"bob\" } }) { nickname { value } } }`))
+}
+
+func TestDynamicHandlersFederation(t *testing.T) {
+	conn := startTestServer(t)
+	mux := runtime.NewServeMux()
+	assert.NoError(t, mux.EnableFederation())
+	assert.NoError(t, Register(context.Background(), mux, conn))
+
+	assert.Contains(t, serveTestQuery(mux, `{ _service { sdl } }`), `extend type Users_Type_User @key(fields: \"id\")`)
+	assert.JSONEq(t, `{"data":{"_entities":[{"name":"alice","posts":[{"title":"post of 1"}]}]}}`,
+		serveTestQuery(mux, `{ _entities(representations: [{__typename: \"Users_Type_User\", id: 1}]) { ... on Users_Type_User { name posts { title } } } }`))
+	// Int64 key is sent as string like the field is serialized
+	assert.JSONEq(t, `{"data":{"_entities":[{"id":"1","name":"alice"}]}}`,
+		serveTestQuery(mux, `{ _entities(representations: [{__typename: \"Users_Type_User\", id: \"1\"}]) { ... on Users_Type_User { id name } } }`))
+}
+
+func TestDynamicHandlersNode(t *testing.T) {
+	conn := startTestServerWithProto(t, strings.Replace(testProto,
+		`[graphql.v1.entity] { keys: "id" resolver: "user" }`,
+		`[graphql.v1.node] { resolver: "user" }`, 1))
+	mux := runtime.NewServeMux()
+	assert.NoError(t, Register(context.Background(), mux, conn))
+
+	id, err := runtime.EncodeGlobalID("Users_Type_User", 1)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"data":{"user":{"id":"`+id+`"}}}`, serveTestQuery(mux, `{ user(id: 1) { id } }`))
+	assert.JSONEq(t, `{"data":{"node":{"id":"`+id+`","name":"alice"}}}`,
+		serveTestQuery(mux, `{ node(id: \"`+id+`\") { id ... on Users_Type_User { name } } }`))
+}
+
+func TestDynamicHandlersConnection(t *testing.T) {
+	text := strings.NewReplacer(
+		`response { pluck: "posts" }`, `response { pluck: "posts" connection: true }`,
+		`name: "ListPostsRequest"`, `name: "ListPostsRequest"
+  field { name: "page_size" number: 2 label: LABEL_OPTIONAL type: TYPE_INT32 }
+  field { name: "page_token" number: 3 label: LABEL_OPTIONAL type: TYPE_STRING }`,
+		`name: "ListPostsResponse"`, `name: "ListPostsResponse"
+  field { name: "next_page_token" number: 2 label: LABEL_OPTIONAL type: TYPE_STRING }`,
+	).Replace(testProto)
+	conn := startTestServerWithProto(t, text)
+	mux := runtime.NewServeMux()
+	assert.NoError(t, Register(context.Background(), mux, conn))
+
+	assert.JSONEq(t, `{"data":{"user":{"posts":{
+  "edges": [{"node": {"title": "post of 1"}}],
+  "pageInfo": {"hasNextPage": true, "hasPreviousPage": false}
+}}}}`, serveTestQuery(mux, `{ user(id: 1) { posts(first: 1) { edges { node { title } } pageInfo { hasNextPage hasPreviousPage } } } }`))
+
+	// first: 0 responds empty connection without calling the backend
+	assert.JSONEq(t, `{"data":{"user":{"posts":{
+  "edges": [],
+  "pageInfo": {"hasNextPage": false, "hasPreviousPage": false}
+}}}}`, serveTestQuery(mux, `{ user(id: 1) { posts(first: 0) { edges { node { title } } pageInfo { hasNextPage hasPreviousPage } } } }`))
+}
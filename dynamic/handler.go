@@ -0,0 +1,444 @@
+package dynamic
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/nebucloud/nebucloud-gateway/generator"
+	"github.com/nebucloud/nebucloud-gateway/ptypes"
+	"github.com/nebucloud/nebucloud-gateway/runtime"
+	"github.com/nebucloud/nebucloud-gateway/spec"
+)
+
+// handler is runtime.GraphqlHandler of a gRPC service which is built from descriptors at runtime.
+// It provides the same schema as generated handler of the service.
+type handler struct {
+	conn *grpc.ClientConn
+	// hostConn is set when handler owns conn which is opened for the host of graphql.service option
+	hostConn  *hostConn
+	closeOnce sync.Once
+
+	service  *spec.Service
+	template *generator.Template
+	// services of all analyzed files, which resolver fields of external types refer
+	services []*spec.Service
+	files    *protoregistry.Files
+	isCamel  bool
+	// wrappers are mapped to object types instead of nullable scalars
+	wrapperAsObject bool
+	// messageTypes resolves messages which google.protobuf.Any contains
+	messageTypes *dynamicpb.Types
+
+	types     runtime.Types
+	queries   runtime.Fields
+	mutations runtime.Fields
+}
+
+// newHandler builds schema of service at once.
+// spec panics when resolver field refers undefined query, it is returned as error.
+func newHandler(h *handler) (_ *handler, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to build schema of %s: %v", h.Name(), r)
+		}
+	}()
+	if h.files != nil {
+		h.messageTypes = dynamicpb.NewTypes(h.files)
+	}
+	h.types = h.buildTypes()
+	h.queries = h.buildQueries()
+	h.mutations = h.buildMutations()
+	return h, nil
+}
+
+// Name returns full name of the service which identifies this handler in schema conflict errors.
+func (h *handler) Name() string {
+	if pkg := h.service.Package(); pkg != "" {
+		return pkg + "." + h.service.Name()
+	}
+	return h.service.Name()
+}
+
+// Namespace returns the field name which queries and mutations of this service are nested under.
+func (h *handler) Namespace() string {
+	return h.service.Namespace()
+}
+
+// CreateConnection returns the connection which handler was built with.
+// Connection is reused across calls so that closing function does nothing.
+func (h *handler) CreateConnection(ctx context.Context) (*grpc.ClientConn, func(), error) {
+	return h.conn, func() {}, nil
+}
+
+// Close closes the connection which handler opened for the host of graphql.service option,
+// after the handler is removed from ServeMux or replaced. Connection provided by caller is not closed.
+func (h *handler) Close() (err error) {
+	h.closeOnce.Do(func() {
+		if h.hostConn != nil {
+			err = h.hostConn.release()
+		}
+	})
+	return err
+}
+
+// GetTypes returns definitions of types which queries and mutations refer.
+func (h *handler) GetTypes() runtime.Types {
+	return h.types
+}
+
+// GetQueries returns acceptable runtime.Fields for Query.
+func (h *handler) GetQueries() runtime.Fields {
+	return h.queries
+}
+
+// GetMutations returns acceptable runtime.Fields for Mutation.
+func (h *handler) GetMutations() runtime.Fields {
+	return h.mutations
+}
+
+func (h *handler) buildTypes() runtime.Types {
+	t := h.template
+	types := runtime.Types{
+		Resolvers: make(map[string]map[string]runtime.FieldResolveFn),
+	}
+	for _, m := range t.Types {
+		types.Definitions = append(types.Definitions, m.ObjectDefinition(t.Services))
+	}
+	for _, m := range t.Inputs {
+		types.Definitions = append(types.Definitions, m.InputDefinition())
+	}
+	for _, m := range t.ExternalTypes {
+		if fns, ok := ptypes.Definitions[m.FullPath()]; ok {
+			types.Definitions = append(types.Definitions, fns[0](), fns[1]())
+			continue
+		}
+		types.Definitions = append(types.Definitions, m.ObjectDefinition(h.services), m.InputDefinition())
+	}
+	for _, e := range append(append([]*spec.Enum{}, t.Enums...), t.ExternalEnums...) {
+		types.Enums = append(types.Enums, enumDefinition(e))
+	}
+
+	for _, m := range t.Types {
+		for _, f := range m.ResolveFields() {
+			q := f.ResolveSubField(t.Services)
+			if q.Method.Service.Name() != h.service.Name() {
+				continue
+			}
+			if _, ok := types.Resolvers[m.GraphqlTypeName()]; !ok {
+				types.Resolvers[m.GraphqlTypeName()] = make(map[string]runtime.FieldResolveFn)
+			}
+			types.Resolvers[m.GraphqlTypeName()][f.FieldName()] = h.resolveQuery(q)
+		}
+		if m.IsEntity() {
+			if entity := h.entity(m); entity != nil {
+				types.Entities = append(types.Entities, entity)
+			}
+		}
+		if m.IsNode() {
+			types.Nodes = append(types.Nodes, h.node(m))
+		}
+	}
+	for _, m := range t.ExternalTypes {
+		if m.IsNode() {
+			types.Nodes = append(types.Nodes, &runtime.Node{
+				Name:    m.GraphqlTypeName(),
+				IDField: m.NodeIDField().FieldName(),
+			})
+		}
+	}
+	types.Definitions = append(types.Definitions, t.Connections...)
+	types.Definitions = append(types.Definitions, t.Scalars...)
+	types.Definitions = append(types.Definitions, t.Unions...)
+	if len(t.AnyTypes) > 0 {
+		types.AnyTypes = make(map[string]string, len(t.AnyTypes))
+		for _, m := range t.AnyTypes {
+			types.AnyTypes[m.FullPath()] = m.GraphqlTypeName()
+		}
+	}
+	return types
+}
+
+// node returns Relay node of message, which is fetched by this handler if the resolver is defined in this service.
+// Request of the resolver is filled with backend ID which is decoded from global ID.
+func (h *handler) node(m *spec.Message) *runtime.Node {
+	idField := m.NodeIDField().FieldName()
+	node := &runtime.Node{
+		Name:    m.GraphqlTypeName(),
+		IDField: idField,
+	}
+	q := m.NodeResolver(h.template.Services)
+	if q == nil || q.Method.Service.Name() != h.service.Name() {
+		return node
+	}
+	resolve := h.resolveField(q.Method, q.QueryName(), q.PluckResponse(), q.IsPluckResponse(), "")
+	node.Resolve = func(ctx context.Context, id interface{}) (interface{}, error) {
+		return resolve(runtime.ResolveParams{
+			Context: ctx,
+			Source:  map[string]interface{}{idField: id},
+		})
+	}
+	return node
+}
+
+// entity returns federation entity of message whose resolver is defined in this service, or nil.
+// Request of the resolver is filled from key fields of the representation.
+func (h *handler) entity(m *spec.Message) *runtime.Entity {
+	q := m.EntityResolver(h.template.Services)
+	if q == nil || q.Method.Service.Name() != h.service.Name() {
+		return nil
+	}
+	resolve := h.resolveField(q.Method, q.QueryName(), q.PluckResponse(), q.IsPluckResponse(), "")
+	return &runtime.Entity{
+		Name: m.GraphqlTypeName(),
+		Keys: m.EntityKeys(),
+		Resolve: func(ctx context.Context, representation map[string]interface{}) (interface{}, error) {
+			return resolve(runtime.ResolveParams{
+				Context: ctx,
+				Source:  representation,
+			})
+		},
+	}
+}
+
+func (h *handler) buildQueries() runtime.Fields {
+	fields := make(runtime.Fields)
+	for _, q := range h.service.Queries {
+		if q.IsResolver() {
+			continue
+		}
+		fields[q.QueryName()] = &runtime.Field{
+			Description: q.Comment(),
+			Args:        q.SchemaArgs(),
+			Type:        q.QueryType(),
+			Resolve:     h.resolveQuery(q),
+		}
+	}
+	return fields
+}
+
+func (h *handler) buildMutations() runtime.Fields {
+	fields := make(runtime.Fields)
+	for _, m := range h.service.Mutations {
+		fields[m.MutationName()] = &runtime.Field{
+			Description: m.Comment(),
+			Args:        m.SchemaArgs(),
+			Type:        m.MutationType(),
+			Resolve:     h.resolveMutation(m),
+		}
+	}
+	return fields
+}
+
+// resolveMutation returns resolver of mutation.
+// FieldMask of the request is set from provided input fields when update_mask option is declared,
+// unless the client provides it.
+func (h *handler) resolveMutation(m *spec.Mutation) runtime.FieldResolveFn {
+	resolve := h.resolveField(m.Method, m.MutationName(), m.PluckResponse(), m.IsPluckResponse(), m.InputName())
+	if !m.HasUpdateMask() {
+		return resolve
+	}
+	field := m.UpdateMaskField()
+	return func(p runtime.ResolveParams) (interface{}, error) {
+		input := p.Args
+		if name := m.InputName(); name != "" {
+			input, _ = p.Args[name].(map[string]interface{}) // nolint: errcheck
+		}
+		if input[field.FieldName()] != nil {
+			return resolve(p)
+		}
+		values := make(map[string]interface{}, len(input)+1)
+		for k, v := range input {
+			values[k] = v
+		}
+		values[field.FieldName()] = runtime.NewUpdateMask(p.Info, m.UpdateMaskArgument(), h.isCamel)
+		if name := m.InputName(); name != "" {
+			args := make(map[string]interface{}, len(p.Args))
+			for k, v := range p.Args {
+				args[k] = v
+			}
+			args[name] = values
+			values = args
+		}
+		p.Args = values
+		return resolve(p)
+	}
+}
+
+// resolveQuery returns resolver of query or resolver field.
+// Connection translates first and after arguments into page_size and page_token, and the page into edges.
+// FieldMask of the request is set from selected fields when field_mask option is declared.
+func (h *handler) resolveQuery(q *spec.Query) runtime.FieldResolveFn {
+	if !q.IsConnection() {
+		resolve := h.resolveField(q.Method, q.QueryName(), q.PluckResponse(), q.IsPluckResponse(), "")
+		if !q.HasFieldMask() {
+			return resolve
+		}
+		return func(p runtime.ResolveParams) (interface{}, error) {
+			args := make(map[string]interface{}, len(p.Args)+1)
+			for k, v := range p.Args {
+				args[k] = v
+			}
+			args[q.FieldMaskField().Name()] = runtime.NewFieldMask(p.Info, q.FieldMaskPrefix(), h.isCamel)
+			p.Args = args
+			return resolve(p)
+		}
+	}
+	resolve := h.resolveField(q.Method, q.QueryName(), nil, false, "")
+	items := q.ConnectionItemsField().FieldName()
+	nextPageToken := "next_page_token"
+	if h.isCamel {
+		nextPageToken = "nextPageToken"
+	}
+	return func(p runtime.ResolveParams) (interface{}, error) {
+		pagination, err := runtime.NewPagination(p.Args)
+		if err != nil {
+			return nil, err
+		}
+		if c, ok := pagination.Empty(); ok {
+			return c, nil
+		}
+		args := make(map[string]interface{}, len(p.Args)+2)
+		for k, v := range p.Args {
+			args[k] = v
+		}
+		for k, v := range pagination.Request() {
+			args[k] = v
+		}
+		if q.HasFieldMask() {
+			args[q.FieldMaskField().Name()] = pagination.FieldMask(p.Info, q.FieldMaskPrefix(), h.isCamel)
+		}
+		p.Args = args
+
+		v, err := resolve(p)
+		if err != nil {
+			return nil, err
+		}
+		out, _ := v.(map[string]interface{})    // nolint: errcheck
+		token, _ := out[nextPageToken].(string) // nolint: errcheck
+		return pagination.Connection(out[items], token)
+	}
+}
+
+// resolveField returns resolver which calls RPC with dynamicpb messages.
+// Request is filled from parent value for resolver fields, then from arguments,
+// or from the input argument when mutation declares its name.
+func (h *handler) resolveField(
+	m *spec.Method,
+	fieldName string,
+	pluck []*spec.Field,
+	isPluck bool,
+	inputName string,
+) runtime.FieldResolveFn {
+
+	fullMethod := "/" + m.ServiceName() + "/" + m.Name()
+	if pkg := m.Package(); pkg != "" {
+		fullMethod = "/" + pkg + "." + m.ServiceName() + "/" + m.Name()
+	}
+	policy, err := callPolicy(m)
+	if err != nil {
+		// newHandler recovers it as the error of building schema
+		panic(err)
+	}
+
+	return func(p runtime.ResolveParams) (interface{}, error) {
+		md, err := h.findMethod(fullMethod)
+		if err != nil {
+			return nil, err
+		}
+		req := dynamicpb.NewMessage(md.Input())
+		if source, ok := p.Source.(map[string]interface{}); ok {
+			if err := h.setMessage(req, source); err != nil {
+				return nil, fmt.Errorf("Failed to marshal resolver source for %s: %w", fieldName, err)
+			}
+		}
+		args := p.Args
+		if inputName != "" {
+			args, _ = p.Args[inputName].(map[string]interface{}) // nolint: errcheck
+		}
+		if err := h.setMessage(req, args); err != nil {
+			return nil, fmt.Errorf("Failed to marshal request for %s: %w", fieldName, err)
+		}
+
+		conn, closer, err := h.CreateConnection(p.Context)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to create gRPC connection for %s: %w", fieldName, err)
+		}
+		defer closer()
+		resp := dynamicpb.NewMessage(md.Output())
+		err = runtime.Invoke(p.Context, conn.Target(), policy, func(ctx context.Context) error {
+			return conn.Invoke(ctx, fullMethod, req, resp)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Failed to call RPC %s: %w", m.Name(), err)
+		}
+
+		// RPC may return the message which JSON scalar represents as it is
+		// Any is checked first because types of messages which it contains are resolved from descriptors of the backend
+		if md.Output().FullName() == anyMessage && !isPluck {
+			return h.anyToJSON(resp)
+		}
+		if newMessage, ok := runtime.NewJSONMessage(md.Output().FullName()); ok && !isPluck {
+			return toGenerated(resp, newMessage)
+		}
+		out := h.messageToMap(resp)
+		if isPluck {
+			return out[pluck[0].FieldName()], nil
+		}
+		return out, nil
+	}
+}
+
+func (h *handler) findMethod(fullMethod string) (protoreflect.MethodDescriptor, error) {
+	name := strings.ReplaceAll(strings.TrimPrefix(fullMethod, "/"), "/", ".")
+	d, err := h.files.FindDescriptorByName(protoreflect.FullName(name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find method %s: %w", name, err)
+	}
+	md, ok := d.(protoreflect.MethodDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a method", name)
+	}
+	return md, nil
+}
+
+// enumDefinition returns enum whose internal values are protobuf enum numbers
+func enumDefinition(e *spec.Enum) *runtime.Enum {
+	values := make(map[string]interface{}, len(e.Values()))
+	for _, v := range e.Values() {
+		values[v.Name()] = protoreflect.EnumNumber(v.Number())
+	}
+	return &runtime.Enum{
+		Name:       e.GraphqlEnumName(),
+		Definition: e.EnumDefinition(),
+		Values:     values,
+	}
+}
+
+// callPolicy converts policy option of method to runtime.CallPolicy by the parser which generator shares.
+func callPolicy(m *spec.Method) (*runtime.CallPolicy, error) {
+	p, err := m.ParseCallPolicy()
+	if err != nil || p == nil {
+		return nil, err
+	}
+	policy := &runtime.CallPolicy{
+		Timeout:    p.Timeout,
+		Idempotent: p.Idempotent,
+	}
+	if r := p.Retry; r != nil {
+		policy.Retry = &runtime.RetryPolicy{
+			MaxAttempts:       r.MaxAttempts,
+			InitialBackoff:    r.InitialBackoff,
+			MaxBackoff:        r.MaxBackoff,
+			BackoffMultiplier: r.BackoffMultiplier,
+			RetryableCodes:    r.RetryableCodes,
+		}
+	}
+	return policy, nil
+}
@@ -0,0 +1,403 @@
+package dynamic
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+	"github.com/nebucloud/nebucloud-gateway/runtime"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	graphqlv1 "github.com/nebucloud/nebucloud-gateway/gen/go/graphql/v1"
+)
+
+// anyMessage is full name of google.protobuf.Any, which is represented as JSON scalar or union of any_types option
+const anyMessage protoreflect.FullName = "google.protobuf.Any"
+
+// isScalarWrapper reports whether the message is a google.protobuf wrapper which is mapped to nullable scalar of its value.
+// BytesValue is always Bytes scalar, while others are object types when handler maps wrappers as object.
+func (h *handler) isScalarWrapper(md protoreflect.MessageDescriptor) bool {
+	return runtime.IsWrapper(md) && (!h.wrapperAsObject || md.FullName() == "google.protobuf.BytesValue")
+}
+
+// setMessage sets values of GraphQL arguments or parent object into message.
+// Keys are accepted as protobuf field name or lower camel case one,
+// and keys which message doesn't have are ignored like encoding/json does on generated structs.
+func (h *handler) setMessage(msg protoreflect.Message, values map[string]interface{}) error {
+	fields := msg.Descriptor().Fields()
+	for key, v := range values {
+		fd := fields.ByName(protoreflect.Name(key))
+		if fd == nil {
+			fd = fields.ByName(protoreflect.Name(strcase.ToSnake(key)))
+		}
+		if fd == nil || v == nil {
+			continue
+		}
+
+		var err error
+		switch {
+		case fd.IsMap():
+			err = h.setMap(msg.Mutable(fd).Map(), fd, v)
+		case fd.IsList():
+			err = h.setList(msg.Mutable(fd).List(), fd, v)
+		default:
+			var pv protoreflect.Value
+			if pv, err = h.toValue(fd, v, msg.NewField(fd)); err == nil {
+				msg.Set(fd, pv)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("field %s: %w", fd.Name(), err)
+		}
+	}
+	return nil
+}
+
+func (h *handler) setList(list protoreflect.List, fd protoreflect.FieldDescriptor, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice {
+		return fmt.Errorf("expected list but got %T", v)
+	}
+	for i := 0; i < rv.Len(); i++ {
+		item, err := h.toValue(fd, rv.Index(i).Interface(), list.NewElement())
+		if err != nil {
+			return err
+		}
+		list.Append(item)
+	}
+	return nil
+}
+
+// setMap accepts object keyed by map key, or list of key and value entries which is the GraphQL representation of map
+func (h *handler) setMap(m protoreflect.Map, fd protoreflect.FieldDescriptor, v interface{}) error {
+	set := func(k, val interface{}) error {
+		key, err := h.toValue(fd.MapKey(), k, protoreflect.Value{})
+		if err != nil {
+			return err
+		}
+		value, err := h.toValue(fd.MapValue(), val, m.NewValue())
+		if err != nil {
+			return err
+		}
+		m.Set(key.MapKey(), value)
+		return nil
+	}
+
+	if obj, ok := v.(map[string]interface{}); ok {
+		for k, val := range obj {
+			if err := set(k, val); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice {
+		return fmt.Errorf("expected map entries but got %T", v)
+	}
+	for i := 0; i < rv.Len(); i++ {
+		entry, ok := rv.Index(i).Interface().(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("expected map entry but got %T", rv.Index(i).Interface())
+		}
+		if err := set(entry["key"], entry["value"]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// toValue converts a singular value, message value is set into newValue which caller allocated
+// nolint: gocyclo
+func (h *handler) toValue(fd protoreflect.FieldDescriptor, v interface{}, newValue protoreflect.Value) (protoreflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		if b, ok := v.(bool); ok {
+			return protoreflect.ValueOfBool(b), nil
+		}
+	case protoreflect.StringKind:
+		if s, ok := v.(string); ok {
+			return protoreflect.ValueOfString(s), nil
+		}
+	case protoreflect.BytesKind:
+		if b, ok := v.([]byte); ok {
+			return protoreflect.ValueOfBytes(b), nil
+		}
+		if s, ok := v.(string); ok {
+			b, err := base64.StdEncoding.DecodeString(s)
+			if err != nil {
+				return protoreflect.Value{}, fmt.Errorf("bytes value must be base64 encoded: %w", err)
+			}
+			return protoreflect.ValueOfBytes(b), nil
+		}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		if n, ok := toInt64(v); ok && n >= math.MinInt32 && n <= math.MaxInt32 {
+			return protoreflect.ValueOfInt32(int32(n)), nil
+		}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		if n, ok := toInt64(v); ok {
+			return protoreflect.ValueOfInt64(n), nil
+		}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		if n, ok := toInt64(v); ok && n >= 0 && n <= math.MaxUint32 {
+			return protoreflect.ValueOfUint32(uint32(n)), nil
+		}
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		if n, ok := v.(uint64); ok {
+			return protoreflect.ValueOfUint64(n), nil
+		}
+		if n, ok := toInt64(v); ok && n >= 0 {
+			return protoreflect.ValueOfUint64(uint64(n)), nil
+		}
+	case protoreflect.FloatKind:
+		if f, ok := toFloat64(v); ok {
+			return protoreflect.ValueOfFloat32(float32(f)), nil
+		}
+	case protoreflect.DoubleKind:
+		if f, ok := toFloat64(v); ok {
+			return protoreflect.ValueOfFloat64(f), nil
+		}
+	case protoreflect.EnumKind:
+		if name, ok := v.(string); ok {
+			if ev := fd.Enum().Values().ByName(protoreflect.Name(name)); ev != nil {
+				return protoreflect.ValueOfEnum(ev.Number()), nil
+			}
+			return protoreflect.Value{}, fmt.Errorf("unknown enum value %s of %s", name, fd.Enum().FullName())
+		}
+		if n, ok := toInt64(v); ok {
+			return protoreflect.ValueOfEnum(protoreflect.EnumNumber(n)), nil
+		}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		// JSON scalar is parsed into Value, which is converted to Struct or ListValue as the field requires
+		if jv, ok := v.(*structpb.Value); ok && fd.Message().FullName() == anyMessage {
+			if err := runtime.UnmarshalAnyJSON(jv, newValue.Message().Interface(), h.anyResolver()); err != nil {
+				return protoreflect.Value{}, err
+			}
+			return newValue, nil
+		}
+		if jv, ok := v.(*structpb.Value); ok {
+			m, err := runtime.ConvertJSON(jv, fd.Message().FullName())
+			if err != nil {
+				return protoreflect.Value{}, err
+			}
+			v = m
+		}
+		// well-known message which scalar is parsed into, e.g. Timestamp of DateTime
+		if m, ok := v.(proto.Message); ok && m.ProtoReflect().Descriptor().FullName() == fd.Message().FullName() {
+			if _, err := toGenerated(m, newValue.Message().Interface()); err != nil {
+				return protoreflect.Value{}, err
+			}
+			return newValue, nil
+		}
+		// scalar of wrapper is set to its value field
+		if _, ok := v.(map[string]interface{}); !ok && h.isScalarWrapper(fd.Message()) {
+			valueField := fd.Message().Fields().ByName("value")
+			pv, err := h.toValue(valueField, v, newValue.Message().NewField(valueField))
+			if err != nil {
+				return protoreflect.Value{}, err
+			}
+			newValue.Message().Set(valueField, pv)
+			return newValue, nil
+		}
+		if obj, ok := v.(map[string]interface{}); ok {
+			if err := h.setMessage(newValue.Message(), obj); err != nil {
+				return protoreflect.Value{}, err
+			}
+			return newValue, nil
+		}
+	}
+	return protoreflect.Value{}, fmt.Errorf("cannot use %T as %s", v, fd.Kind())
+}
+
+func toInt64(v interface{}) (int64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if u := rv.Uint(); u <= math.MaxInt64 {
+			return int64(u), true
+		}
+	case reflect.Float32, reflect.Float64:
+		if f := rv.Float(); f == math.Trunc(f) {
+			return int64(f), true
+		}
+	}
+	return 0, false
+}
+
+func toFloat64(v interface{}) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	if n, ok := toInt64(v); ok {
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// messageToMap converts message to the value which default resolvers read,
+// keyed by the same field names as schema.
+// Like generated structs, unset message fields and empty lists are null and unset scalars are zero values.
+func (h *handler) messageToMap(msg protoreflect.Message) map[string]interface{} {
+	fields := msg.Descriptor().Fields()
+	out := make(map[string]interface{}, fields.Len())
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		name := string(fd.Name())
+		if h.isCamel {
+			name = strcase.ToLowerCamel(name)
+		}
+
+		switch {
+		case fd.IsMap():
+			out[name] = h.mapToEntries(msg.Get(fd).Map(), fd)
+		case fd.IsList():
+			out[name] = h.listToSlice(msg.Get(fd).List(), fd)
+		case !msg.Has(fd) && (fd.Message() != nil || fd.ContainingOneof() != nil):
+			out[name] = nil
+		default:
+			out[name] = h.fromValue(fd, msg.Get(fd))
+		}
+	}
+	return out
+}
+
+func (h *handler) listToSlice(list protoreflect.List, fd protoreflect.FieldDescriptor) []interface{} {
+	if list.Len() == 0 {
+		return nil
+	}
+	items := make([]interface{}, list.Len())
+	for i := range items {
+		items[i] = h.fromValue(fd, list.Get(i))
+	}
+	return items
+}
+
+// mapToEntries converts map to list of key and value entries which are sorted by key
+func (h *handler) mapToEntries(m protoreflect.Map, fd protoreflect.FieldDescriptor) []interface{} {
+	if m.Len() == 0 {
+		return nil
+	}
+	entries := make([]interface{}, 0, m.Len())
+	m.Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+		entries = append(entries, map[string]interface{}{
+			"key":   h.fromValue(fd.MapKey(), k.Value()),
+			"value": h.fromValue(fd.MapValue(), v),
+		})
+		return true
+	})
+	sort.Slice(entries, func(i, j int) bool {
+		return fmt.Sprint(entries[i].(map[string]interface{})["key"]) < fmt.Sprint(entries[j].(map[string]interface{})["key"])
+	})
+	return entries
+}
+
+func (h *handler) fromValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) interface{} {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		if fd.Message().FullName() == anyMessage {
+			if isUnion(fd) {
+				return h.anyToObject(v.Message())
+			}
+			out, _ := h.anyToJSON(v.Message().Interface()) // nolint: errcheck
+			return out
+		}
+		if newMessage, ok := runtime.NewJSONMessage(fd.Message().FullName()); ok {
+			if m, err := toGenerated(v.Message().Interface(), newMessage); err == nil {
+				return m
+			}
+		}
+		if h.isScalarWrapper(fd.Message()) {
+			valueField := fd.Message().Fields().ByName("value")
+			return h.fromValue(valueField, v.Message().Get(valueField))
+		}
+		return h.messageToMap(v.Message())
+	case protoreflect.EnumKind:
+		return v.Enum()
+	case protoreflect.BytesKind:
+		return base64.StdEncoding.EncodeToString(v.Bytes())
+	default:
+		return v.Interface()
+	}
+}
+
+// toGenerated copies message between dynamic and generated types of the same descriptor
+func toGenerated(src, dst proto.Message) (proto.Message, error) {
+	buf, err := proto.Marshal(src)
+	if err != nil {
+		return nil, err
+	}
+	if err := proto.Unmarshal(buf, dst); err != nil {
+		return nil, err
+	}
+	return dst, nil
+}
+
+// anyResolver returns resolver of messages which google.protobuf.Any contains,
+// or nil to resolve them from types which are linked into the binary.
+func (h *handler) anyResolver() runtime.AnyResolver {
+	if h.messageTypes == nil {
+		return nil
+	}
+	return h.messageTypes
+}
+
+// anyToJSON converts Any as protojson does, which has "@type" key with the fields of contained message
+func (h *handler) anyToJSON(m proto.Message) (interface{}, error) {
+	buf, err := protojson.MarshalOptions{Resolver: h.anyResolver()}.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	var out interface{}
+	if err := json.Unmarshal(buf, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// anyToObject unpacks Any into the value of object type which "__typename" tells to resolve union.
+// Message which any_types option doesn't declare is typed by its full name, so that executor reports it.
+func (h *handler) anyToObject(a protoreflect.Message) interface{} {
+	fields := a.Descriptor().Fields()
+	url := a.Get(fields.ByName("type_url")).String()
+	out := map[string]interface{}{
+		"__typename": url[strings.LastIndex(url, "/")+1:],
+	}
+	resolver := h.anyResolver()
+	if resolver == nil {
+		return out
+	}
+	mt, err := resolver.FindMessageByURL(url)
+	if err != nil {
+		return out
+	}
+	m := mt.New()
+	if err := proto.Unmarshal(a.Get(fields.ByName("value")).Bytes(), m.Interface()); err != nil {
+		return out
+	}
+	if typeName, ok := h.types.AnyTypes[string(m.Descriptor().FullName())]; ok {
+		out = h.messageToMap(m)
+		out["__typename"] = typeName
+	}
+	return out
+}
+
+// isUnion reports whether Any field declares its possible messages by any_types option
+func isUnion(fd protoreflect.FieldDescriptor) bool {
+	if fd.Options() == nil {
+		return false
+	}
+	o, ok := proto.GetExtension(fd.Options(), graphqlv1.E_Field).(*graphqlv1.GraphqlField)
+	return ok && len(o.GetAnyTypes()) > 0
+}
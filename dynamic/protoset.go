@@ -0,0 +1,89 @@
+package dynamic
+
+import (
+	"fmt"
+	"os"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/nebucloud/nebucloud-gateway/runtime"
+)
+
+// NewHandlersFromProtoset loads FileDescriptorSet files, which are produced by `buf build -o` or `protoc --descriptor_set_out`,
+// and returns handlers of services declared in them. It is useful where backends disable server reflection.
+// When conn is nil, handlers connect to the hosts which graphql.service option declares, like generated handlers do.
+// The clients are opened once per host, and handlers implement io.Closer to close them after removed from ServeMux.
+// Build descriptor set with source info in order to have comments as descriptions of the schema.
+func NewHandlersFromProtoset(conn *grpc.ClientConn, paths []string, opts ...Option) ([]runtime.GraphqlHandler, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	descriptors, err := loadProtosets(paths)
+	if err != nil {
+		return nil, err
+	}
+	return buildHandlers(conn, serviceFiles(descriptors, nil), descriptors, &o)
+}
+
+// RegisterProtoset builds handlers from FileDescriptorSet files and adds them to mux
+func RegisterProtoset(mux *runtime.ServeMux, conn *grpc.ClientConn, paths []string, opts ...Option) error {
+	handlers, err := NewHandlersFromProtoset(conn, paths, opts...)
+	if err != nil {
+		return err
+	}
+	for _, h := range handlers {
+		if err := mux.AddHandler(h); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadProtosets reads file descriptors from files, the first one wins when the same file is contained in multiple sets.
+// Imports which are not contained, e.g. set is built without --include_imports,
+// are completed from files which are linked into the binary like graphql.proto and google.protobuf types.
+func loadProtosets(paths []string) ([]*descriptorpb.FileDescriptorProto, error) {
+	var descriptors []*descriptorpb.FileDescriptorProto
+	seen := make(map[string]struct{})
+	add := func(f *descriptorpb.FileDescriptorProto) {
+		if _, ok := seen[f.GetName()]; ok {
+			return
+		}
+		seen[f.GetName()] = struct{}{}
+		descriptors = append(descriptors, f)
+	}
+
+	for _, p := range paths {
+		buf, err := os.ReadFile(p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read protoset: %w", err)
+		}
+		var set descriptorpb.FileDescriptorSet
+		if err := proto.Unmarshal(buf, &set); err != nil {
+			return nil, fmt.Errorf("failed to decode protoset %s: %w", p, err)
+		}
+		for _, f := range set.GetFile() {
+			add(f)
+		}
+	}
+
+	for i := 0; i < len(descriptors); i++ {
+		for _, dep := range descriptors[i].GetDependency() {
+			if _, ok := seen[dep]; ok {
+				continue
+			}
+			fd, err := protoregistry.GlobalFiles.FindFileByPath(dep)
+			if err != nil {
+				return nil, fmt.Errorf("protosets don't contain %s which %s imports", dep, descriptors[i].GetName())
+			}
+			add(protodesc.ToFileDescriptorProto(fd))
+		}
+	}
+	return descriptors, nil
+}
@@ -0,0 +1,93 @@
+package dynamic
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/nebucloud/nebucloud-gateway/runtime"
+)
+
+// writeTestProtoset writes users.proto with source info, imports are not included
+func writeTestProtoset(t *testing.T) string {
+	return writeTestProtosetWithProto(t, testProto)
+}
+
+func writeTestProtosetWithProto(t *testing.T, text string) string {
+	var fdp descriptorpb.FileDescriptorProto
+	assert.NoError(t, prototext.Unmarshal([]byte(text+`
+source_code_info {
+  location { path: [6, 0, 2, 0] span: [0, 0, 0] leading_comments: " Returns the user\n" }
+}`), &fdp))
+
+	buf, err := proto.Marshal(&descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{&fdp},
+	})
+	assert.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "users.protoset")
+	assert.NoError(t, os.WriteFile(path, buf, 0o600))
+	return path
+}
+
+func TestNewHandlersFromProtoset(t *testing.T) {
+	conn := startTestServer(t)
+	path := writeTestProtoset(t)
+
+	mux := runtime.NewServeMux()
+	assert.NoError(t, RegisterProtoset(mux, conn, []string{path, path}))
+	assert.JSONEq(t, `{"data":{"user":{"name":"alice","posts":[{"title":"post of 1"}]}}}`,
+		serveTestQuery(mux, `{ user(id: 1) { name posts { title } } }`))
+	assert.JSONEq(t, `{"data":{"__type":{"fields":[{"name":"user","description":"Returns the user"}]}}}`,
+		serveTestQuery(mux, `{ __type(name: \"Query\") { fields { name description } } }`))
+
+	_, err := NewHandlersFromProtoset(conn, []string{filepath.Join(t.TempDir(), "missing.protoset")})
+	assert.Error(t, err)
+}
+
+func TestNewHandlersFromProtosetWithoutConnection(t *testing.T) {
+	path := writeTestProtosetWithProto(t, strings.Replace(testProto, `  name: "UserService"
+`, `  name: "UserService"
+  options { [graphql.v1.service] { host: "users:50051" insecure: true } }
+`, 1))
+
+	handlers, err := NewHandlersFromProtoset(nil, []string{path})
+	assert.NoError(t, err)
+	if !assert.Len(t, handlers, 1) {
+		return
+	}
+
+	// client of the declared host is opened at once and reused for each call
+	h := handlers[0].(*handler)
+	conn, closer, err := h.CreateConnection(context.Background())
+	assert.NoError(t, err)
+	closer()
+	assert.Equal(t, "users:50051", conn.Target())
+	assert.NotEqual(t, connectivity.Shutdown, conn.GetState())
+	same, _, _ := h.CreateConnection(context.Background()) // nolint: errcheck
+	assert.Same(t, conn, same)
+
+	assert.NoError(t, h.Close())
+	assert.Equal(t, connectivity.Shutdown, conn.GetState())
+	assert.NoError(t, h.Close())
+
+	// host is required when connection is not provided
+	_, err = NewHandlersFromProtoset(nil, []string{writeTestProtoset(t)})
+	assert.EqualError(t, err, "host of users.UserService is not declared by graphql.service option")
+}
+
+func TestNewHandlersFromProtosetWithServiceConn(t *testing.T) {
+	conn := startTestServer(t)
+
+	// RPCs of the service are called by its own connection, and host of graphql.service option is not required
+	mux := runtime.NewServeMux()
+	assert.NoError(t, RegisterProtoset(mux, nil, []string{writeTestProtoset(t)}, WithServiceConn("users.UserService", conn)))
+	assert.JSONEq(t, `{"data":{"user":{"name":"alice"}}}`, serveTestQuery(mux, `{ user(id: 1) { name } }`))
+}
@@ -0,0 +1,158 @@
+package dynamic
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/grpc"
+	reflectionv1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// reflectionClient pulls file descriptors through the gRPC server reflection service
+type reflectionClient struct {
+	stream reflectionv1.ServerReflection_ServerReflectionInfoClient
+	files  map[string]*descriptorpb.FileDescriptorProto
+}
+
+// fetchDescriptors returns services which the backend exposes
+// and file descriptors which define them, including transitive dependencies.
+func fetchDescriptors(ctx context.Context, conn *grpc.ClientConn) ([]string, []*descriptorpb.FileDescriptorProto, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := reflectionv1.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open reflection stream: %w", err)
+	}
+	defer stream.CloseSend() // nolint: errcheck
+
+	c := &reflectionClient{
+		stream: stream,
+		files:  make(map[string]*descriptorpb.FileDescriptorProto),
+	}
+	services, err := c.listServices()
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, s := range services {
+		if err := c.fileContainingSymbol(s); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := c.resolveDependencies(); err != nil {
+		return nil, nil, err
+	}
+
+	files := make([]*descriptorpb.FileDescriptorProto, 0, len(c.files))
+	for _, f := range c.files {
+		files = append(files, f)
+	}
+	// sort files so that schema is built in the same order every time
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].GetName() < files[j].GetName()
+	})
+	return services, files, nil
+}
+
+func (c *reflectionClient) send(req *reflectionv1.ServerReflectionRequest) (*reflectionv1.ServerReflectionResponse, error) {
+	if err := c.stream.Send(req); err != nil {
+		return nil, fmt.Errorf("failed to send reflection request: %w", err)
+	}
+	resp, err := c.stream.Recv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to receive reflection response: %w", err)
+	}
+	if e := resp.GetErrorResponse(); e != nil {
+		return nil, fmt.Errorf("reflection error: %s", e.GetErrorMessage())
+	}
+	return resp, nil
+}
+
+// listServices returns service names except reflection service itself
+func (c *reflectionClient) listServices() ([]string, error) {
+	resp, err := c.send(&reflectionv1.ServerReflectionRequest{
+		MessageRequest: &reflectionv1.ServerReflectionRequest_ListServices{},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var services []string
+	for _, s := range resp.GetListServicesResponse().GetService() {
+		if strings.HasPrefix(s.GetName(), "grpc.reflection.") {
+			continue
+		}
+		services = append(services, s.GetName())
+	}
+	return services, nil
+}
+
+func (c *reflectionClient) fileContainingSymbol(symbol string) error {
+	resp, err := c.send(&reflectionv1.ServerReflectionRequest{
+		MessageRequest: &reflectionv1.ServerReflectionRequest_FileContainingSymbol{
+			FileContainingSymbol: symbol,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return c.addFiles(resp)
+}
+
+func (c *reflectionClient) fileByFilename(name string) error {
+	resp, err := c.send(&reflectionv1.ServerReflectionRequest{
+		MessageRequest: &reflectionv1.ServerReflectionRequest_FileByFilename{
+			FileByFilename: name,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return c.addFiles(resp)
+}
+
+// addFiles decodes file descriptors in response.
+// graphql.proto options are decoded as extensions because they are registered in protobuf global registry.
+func (c *reflectionClient) addFiles(resp *reflectionv1.ServerReflectionResponse) error {
+	for _, b := range resp.GetFileDescriptorResponse().GetFileDescriptorProto() {
+		var f descriptorpb.FileDescriptorProto
+		if err := proto.Unmarshal(b, &f); err != nil {
+			return fmt.Errorf("failed to decode file descriptor: %w", err)
+		}
+		if _, ok := c.files[f.GetName()]; !ok {
+			c.files[f.GetName()] = &f
+		}
+	}
+	return nil
+}
+
+// resolveDependencies requests files which are imported but server didn't send yet
+func (c *reflectionClient) resolveDependencies() error {
+	for {
+		var missing []string
+		for _, f := range c.files {
+			for _, dep := range f.GetDependency() {
+				if _, ok := c.files[dep]; !ok {
+					missing = append(missing, dep)
+				}
+			}
+		}
+		if len(missing) == 0 {
+			return nil
+		}
+		for _, name := range missing {
+			if _, ok := c.files[name]; ok {
+				continue
+			}
+			if err := c.fileByFilename(name); err != nil {
+				return err
+			}
+			if _, ok := c.files[name]; !ok {
+				return fmt.Errorf("reflection service didn't return file %s", name)
+			}
+		}
+	}
+}
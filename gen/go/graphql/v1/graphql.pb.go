@@ -4,7 +4,7 @@
 // 	protoc        (unknown)
 // source: graphql/v1/graphql.proto
 
-package graphqlv1
+package v1
 
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
@@ -95,6 +95,9 @@ type GraphqlService struct {
 	Host string `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
 	// If true, automatic connection with insecure option.
 	Insecure bool `protobuf:"varint,2,opt,name=insecure,proto3" json:"insecure,omitempty"`
+	// If set, queries and mutations of this service are nested under the namespace field,
+	// e.g. namespace: "billing" exposes `query { billing { invoice(id: 1) { ... } } }`.
+	Namespace string `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
 }
 
 func (x *GraphqlService) Reset() {
@@ -143,6 +146,13 @@ func (x *GraphqlService) GetInsecure() bool {
 	return false
 }
 
+func (x *GraphqlService) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
 // Extend MethodOptions in order to define GraphQL Query or Mutation.
 // User can use this option as following:
 //
@@ -232,6 +242,8 @@ type GraphqlSchema struct {
 	Request *GraphqlRequest `protobuf:"bytes,3,opt,name=request,proto3" json:"request,omitempty"`
 	// Query response object configuration
 	Response *GraphqlResponse `protobuf:"bytes,4,opt,name=response,proto3" json:"response,omitempty"`
+	// Backend call policy of this RPC
+	Policy *GraphqlCallPolicy `protobuf:"bytes,5,opt,name=policy,proto3" json:"policy,omitempty"`
 }
 
 func (x *GraphqlSchema) Reset() {
@@ -294,6 +306,13 @@ func (x *GraphqlSchema) GetResponse() *GraphqlResponse {
 	return nil
 }
 
+func (x *GraphqlSchema) GetPolicy() *GraphqlCallPolicy {
+	if x != nil {
+		return x.Policy
+	}
+	return nil
+}
+
 // configuration option for request
 type GraphqlRequest struct {
 	state         protoimpl.MessageState
@@ -306,6 +325,16 @@ type GraphqlRequest struct {
 	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	// Define pluck message fields
 	Plucks []string `protobuf:"bytes,2,rep,name=plucks,proto3" json:"plucks,omitempty"`
+	// Name of google.protobuf.FieldMask field of query request, say "read_mask".
+	// The field is not exposed as argument, but set from fields which are selected on the query,
+	// e.g. { user(id: 1) { name posts { title } } } sends paths "name" and "posts.title".
+	FieldMask string `protobuf:"bytes,3,opt,name=field_mask,json=fieldMask,proto3" json:"field_mask,omitempty"`
+	// Name of google.protobuf.FieldMask field of mutation request for partial update, say "update_mask".
+	// The mask is set from input fields which the client provides, including fields set to null to clear them,
+	// e.g. { updateUser(user: { name: "foo", address: { city: null } }) } sends paths "name" and "address.city".
+	// Paths are relative to the resource field as AIP-134, which is the only message field of the request
+	// other than the mask, otherwise relative to the request. The mask which the client provides is sent as it is.
+	UpdateMask string `protobuf:"bytes,4,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
 }
 
 func (x *GraphqlRequest) Reset() {
@@ -354,6 +383,20 @@ func (x *GraphqlRequest) GetPlucks() []string {
 	return nil
 }
 
+func (x *GraphqlRequest) GetFieldMask() string {
+	if x != nil {
+		return x.FieldMask
+	}
+	return ""
+}
+
+func (x *GraphqlRequest) GetUpdateMask() string {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return ""
+}
+
 // configuration option for response
 type GraphqlResponse struct {
 	state         protoimpl.MessageState
@@ -367,6 +410,11 @@ type GraphqlResponse struct {
 	// Note that this field IS NOT repeated, just single string field.
 	// It means the response could only be single.
 	Pluck string `protobuf:"bytes,2,opt,name=pluck,proto3" json:"pluck,omitempty"`
+	// If true, the paginated List RPC which follows AIP-158 is exposed as Relay connection.
+	// The request must have page_size and page_token, and the response must have next_page_token
+	// and repeated items, which are the pluck field if declared or the first repeated field.
+	// first and after arguments are translated into page_size and page_token.
+	Connection bool `protobuf:"varint,3,opt,name=connection,proto3" json:"connection,omitempty"`
 }
 
 func (x *GraphqlResponse) Reset() {
@@ -415,6 +463,191 @@ func (x *GraphqlResponse) GetPluck() string {
 	return ""
 }
 
+func (x *GraphqlResponse) GetConnection() bool {
+	if x != nil {
+		return x.Connection
+	}
+	return false
+}
+
+// GraphqlCallPolicy defines how the gateway calls the backend RPC.
+// User can use this option as following:
+//
+//	service MemberService {
+//	   rpc GetMember(GetMemberRequest) returns (Member) {
+//	     option (graphql.schema) = {
+//	       type: QUERY
+//	       name: "member"
+//	       policy {
+//	         timeout: "2s"     // deadline of the call including retries
+//	         idempotent: true  // safe to call more than once
+//	         retry {
+//	           max_attempts: 3
+//	           initial_backoff: "100ms"
+//	           max_backoff: "1s"
+//	           backoff_multiplier: 2
+//	           retryable_status_codes: ["UNAVAILABLE", "DEADLINE_EXCEEDED"]
+//	         }
+//	       }
+//	     }
+//	   }
+//	}
+//
+// Note that the retry policy is applied only when the RPC is declared as idempotent,
+// otherwise the RPC is called exactly once.
+type GraphqlCallPolicy struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Deadline of the call including all retry attempts.
+	// Format is the same as Go's time.ParseDuration, say "1.5s" or "300ms".
+	Timeout string `protobuf:"bytes,1,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	// Retry policy on failure
+	Retry *GraphqlRetryPolicy `protobuf:"bytes,2,opt,name=retry,proto3" json:"retry,omitempty"`
+	// If true, the RPC is safe to be retried
+	Idempotent bool `protobuf:"varint,3,opt,name=idempotent,proto3" json:"idempotent,omitempty"`
+}
+
+func (x *GraphqlCallPolicy) Reset() {
+	*x = GraphqlCallPolicy{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_graphql_v1_graphql_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GraphqlCallPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GraphqlCallPolicy) ProtoMessage() {}
+
+func (x *GraphqlCallPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_graphql_v1_graphql_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GraphqlCallPolicy.ProtoReflect.Descriptor instead.
+func (*GraphqlCallPolicy) Descriptor() ([]byte, []int) {
+	return file_graphql_v1_graphql_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GraphqlCallPolicy) GetTimeout() string {
+	if x != nil {
+		return x.Timeout
+	}
+	return ""
+}
+
+func (x *GraphqlCallPolicy) GetRetry() *GraphqlRetryPolicy {
+	if x != nil {
+		return x.Retry
+	}
+	return nil
+}
+
+func (x *GraphqlCallPolicy) GetIdempotent() bool {
+	if x != nil {
+		return x.Idempotent
+	}
+	return false
+}
+
+// configuration option for retrying backend call
+type GraphqlRetryPolicy struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Maximum number of attempts including the original call
+	MaxAttempts uint32 `protobuf:"varint,1,opt,name=max_attempts,json=maxAttempts,proto3" json:"max_attempts,omitempty"`
+	// Backoff before the first retry, default is "100ms"
+	InitialBackoff string `protobuf:"bytes,2,opt,name=initial_backoff,json=initialBackoff,proto3" json:"initial_backoff,omitempty"`
+	// Upper limit of backoff, default is "1s"
+	MaxBackoff string `protobuf:"bytes,3,opt,name=max_backoff,json=maxBackoff,proto3" json:"max_backoff,omitempty"`
+	// Backoff growth factor applied on each retry, default is 2
+	BackoffMultiplier float64 `protobuf:"fixed64,4,opt,name=backoff_multiplier,json=backoffMultiplier,proto3" json:"backoff_multiplier,omitempty"`
+	// gRPC status code names to be retried, say "UNAVAILABLE".
+	// Default is UNAVAILABLE only.
+	RetryableStatusCodes []string `protobuf:"bytes,5,rep,name=retryable_status_codes,json=retryableStatusCodes,proto3" json:"retryable_status_codes,omitempty"`
+}
+
+func (x *GraphqlRetryPolicy) Reset() {
+	*x = GraphqlRetryPolicy{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_graphql_v1_graphql_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GraphqlRetryPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GraphqlRetryPolicy) ProtoMessage() {}
+
+func (x *GraphqlRetryPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_graphql_v1_graphql_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GraphqlRetryPolicy.ProtoReflect.Descriptor instead.
+func (*GraphqlRetryPolicy) Descriptor() ([]byte, []int) {
+	return file_graphql_v1_graphql_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GraphqlRetryPolicy) GetMaxAttempts() uint32 {
+	if x != nil {
+		return x.MaxAttempts
+	}
+	return 0
+}
+
+func (x *GraphqlRetryPolicy) GetInitialBackoff() string {
+	if x != nil {
+		return x.InitialBackoff
+	}
+	return ""
+}
+
+func (x *GraphqlRetryPolicy) GetMaxBackoff() string {
+	if x != nil {
+		return x.MaxBackoff
+	}
+	return ""
+}
+
+func (x *GraphqlRetryPolicy) GetBackoffMultiplier() float64 {
+	if x != nil {
+		return x.BackoffMultiplier
+	}
+	return 0
+}
+
+func (x *GraphqlRetryPolicy) GetRetryableStatusCodes() []string {
+	if x != nil {
+		return x.RetryableStatusCodes
+	}
+	return nil
+}
+
 // GraphqlField is FieldOptions in protobuf in order to define type field attribute.
 // User can use this option as following:
 //
@@ -444,12 +677,16 @@ type GraphqlField struct {
 	Omit bool `protobuf:"varint,4,opt,name=omit,proto3" json:"omit,omitempty"`
 	// Resolve this field by nested query with additional RPC
 	Resolver string `protobuf:"bytes,5,opt,name=resolver,proto3" json:"resolver,omitempty"`
+	// Full names of messages which google.protobuf.Any field may contain, say "example.Cat".
+	// The field is declared as union of their object types and resolved by type URL of the value,
+	// otherwise Any is exposed as JSON scalar with "@type" key.
+	AnyTypes []string `protobuf:"bytes,6,rep,name=any_types,json=anyTypes,proto3" json:"any_types,omitempty"`
 }
 
 func (x *GraphqlField) Reset() {
 	*x = GraphqlField{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_graphql_v1_graphql_proto_msgTypes[4]
+		mi := &file_graphql_v1_graphql_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -462,7 +699,7 @@ func (x *GraphqlField) String() string {
 func (*GraphqlField) ProtoMessage() {}
 
 func (x *GraphqlField) ProtoReflect() protoreflect.Message {
-	mi := &file_graphql_v1_graphql_proto_msgTypes[4]
+	mi := &file_graphql_v1_graphql_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -475,7 +712,7 @@ func (x *GraphqlField) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GraphqlField.ProtoReflect.Descriptor instead.
 func (*GraphqlField) Descriptor() ([]byte, []int) {
-	return file_graphql_v1_graphql_proto_rawDescGZIP(), []int{4}
+	return file_graphql_v1_graphql_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *GraphqlField) GetRequired() bool {
@@ -513,6 +750,178 @@ func (x *GraphqlField) GetResolver() string {
 	return ""
 }
 
+func (x *GraphqlField) GetAnyTypes() []string {
+	if x != nil {
+		return x.AnyTypes
+	}
+	return nil
+}
+
+// GraphqlEntity is MessageOptions in order to declare the message as Apollo Federation entity.
+// User can use this option as following:
+//
+//	message User {
+//	  option (graphql.entity) = {
+//	    keys: ["id"]      // equivalent to type User @key(fields: "id") in the subgraph schema
+//	    resolver: "user"  // query which fetches the entity by key fields
+//	  };
+//	  int64 id = 1;
+//	  string name = 2;
+//	}
+//
+//	service UserService {
+//	   rpc GetUser(GetUserRequest) returns (User) {
+//	     option (graphql.schema) = {
+//	       type: RESOLVER
+//	       name: "user"
+//	     };
+//	   }
+//	}
+//
+// Key fields of the representation which the router sends are set to the request message of resolver
+// by field name, so that GetUserRequest should have "id" field.
+// Federation is enabled by ServeMux.EnableFederation.
+type GraphqlEntity struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Field sets which identify the entity, say "id" or "organization_id user_id".
+	// This field is required.
+	Keys []string `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	// Name of query or resolver which fetches the entity. This field is required.
+	Resolver string `protobuf:"bytes,2,opt,name=resolver,proto3" json:"resolver,omitempty"`
+}
+
+func (x *GraphqlEntity) Reset() {
+	*x = GraphqlEntity{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_graphql_v1_graphql_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GraphqlEntity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GraphqlEntity) ProtoMessage() {}
+
+func (x *GraphqlEntity) ProtoReflect() protoreflect.Message {
+	mi := &file_graphql_v1_graphql_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GraphqlEntity.ProtoReflect.Descriptor instead.
+func (*GraphqlEntity) Descriptor() ([]byte, []int) {
+	return file_graphql_v1_graphql_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GraphqlEntity) GetKeys() []string {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+func (x *GraphqlEntity) GetResolver() string {
+	if x != nil {
+		return x.Resolver
+	}
+	return ""
+}
+
+// GraphqlNode is MessageOptions in order to declare the message as Relay Node.
+// User can use this option as following:
+//
+//	message User {
+//	  option (graphql.node) = {
+//	    id: "id"          // field which holds backend ID
+//	    resolver: "user"  // query which fetches the object by ID
+//	  };
+//	  int64 id = 1;
+//	  string name = 2;
+//	}
+//
+//	service UserService {
+//	   rpc GetUser(GetUserRequest) returns (User) {
+//	     option (graphql.schema) = {
+//	       type: RESOLVER
+//	       name: "user"
+//	     };
+//	   }
+//	}
+//
+// Then the type implements Node interface, and its id field is exposed as opaque global ID
+// which encodes the type name and backend ID. `node(id: ID!)` and `nodes(ids: [ID!]!)` queries
+// decode the global ID and set backend ID to the request field of resolver which has the same name,
+// so that GetUserRequest should have "id" field.
+type GraphqlNode struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Field which holds backend ID, default is "id".
+	// If the field is not "id", global ID is exposed as additional id field.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Name of query or resolver which fetches the object. This field is required.
+	Resolver string `protobuf:"bytes,2,opt,name=resolver,proto3" json:"resolver,omitempty"`
+}
+
+func (x *GraphqlNode) Reset() {
+	*x = GraphqlNode{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_graphql_v1_graphql_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GraphqlNode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GraphqlNode) ProtoMessage() {}
+
+func (x *GraphqlNode) ProtoReflect() protoreflect.Message {
+	mi := &file_graphql_v1_graphql_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GraphqlNode.ProtoReflect.Descriptor instead.
+func (*GraphqlNode) Descriptor() ([]byte, []int) {
+	return file_graphql_v1_graphql_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GraphqlNode) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GraphqlNode) GetResolver() string {
+	if x != nil {
+		return x.Resolver
+	}
+	return ""
+}
+
 var file_graphql_v1_graphql_proto_extTypes = []protoimpl.ExtensionInfo{
 	{
 		ExtendedType:  (*descriptorpb.ServiceOptions)(nil),
@@ -538,6 +947,22 @@ var file_graphql_v1_graphql_proto_extTypes = []protoimpl.ExtensionInfo{
 		Tag:           "bytes,1080,opt,name=schema",
 		Filename:      "graphql/v1/graphql.proto",
 	},
+	{
+		ExtendedType:  (*descriptorpb.MessageOptions)(nil),
+		ExtensionType: (*GraphqlEntity)(nil),
+		Field:         1080,
+		Name:          "graphql.v1.entity",
+		Tag:           "bytes,1080,opt,name=entity",
+		Filename:      "graphql/v1/graphql.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.MessageOptions)(nil),
+		ExtensionType: (*GraphqlNode)(nil),
+		Field:         1081,
+		Name:          "graphql.v1.node",
+		Tag:           "bytes,1081,opt,name=node",
+		Filename:      "graphql/v1/graphql.proto",
+	},
 }
 
 // Extension fields to descriptorpb.ServiceOptions.
@@ -558,6 +983,14 @@ var (
 	E_Schema = &file_graphql_v1_graphql_proto_extTypes[2]
 )
 
+// Extension fields to descriptorpb.MessageOptions.
+var (
+	// optional graphql.v1.GraphqlEntity entity = 1080;
+	E_Entity = &file_graphql_v1_graphql_proto_extTypes[3]
+	// optional graphql.v1.GraphqlNode node = 1081;
+	E_Node = &file_graphql_v1_graphql_proto_extTypes[4]
+)
+
 var File_graphql_v1_graphql_proto protoreflect.FileDescriptor
 
 var file_graphql_v1_graphql_proto_rawDesc = []byte{
@@ -565,74 +998,121 @@ var file_graphql_v1_graphql_proto_rawDesc = []byte{
 	0x70, 0x68, 0x71, 0x6c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0a, 0x67, 0x72, 0x61, 0x70,
 	0x68, 0x71, 0x6c, 0x2e, 0x76, 0x31, 0x1a, 0x20, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70,
 	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74,
-	0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x40, 0x0a, 0x0e, 0x47, 0x72, 0x61, 0x70,
+	0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x5e, 0x0a, 0x0e, 0x47, 0x72, 0x61, 0x70,
 	0x68, 0x71, 0x6c, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x6f,
 	0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x12, 0x1a,
 	0x0a, 0x08, 0x69, 0x6e, 0x73, 0x65, 0x63, 0x75, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08,
-	0x52, 0x08, 0x69, 0x6e, 0x73, 0x65, 0x63, 0x75, 0x72, 0x65, 0x22, 0xbf, 0x01, 0x0a, 0x0d, 0x47,
-	0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x12, 0x2b, 0x0a, 0x04,
-	0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x67, 0x72, 0x61,
-	0x70, 0x68, 0x71, 0x6c, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x54,
-	0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
-	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x34, 0x0a,
-	0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
-	0x2e, 0x67, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x72, 0x61, 0x70,
-	0x68, 0x71, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x07, 0x72, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x12, 0x37, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18,
-	0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x67, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x2e,
-	0x76, 0x31, 0x2e, 0x47, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x52, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x3c, 0x0a, 0x0e,
-	0x47, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12,
-	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
-	0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x6c, 0x75, 0x63, 0x6b, 0x73, 0x18, 0x02, 0x20, 0x03,
-	0x28, 0x09, 0x52, 0x06, 0x70, 0x6c, 0x75, 0x63, 0x6b, 0x73, 0x22, 0x43, 0x0a, 0x0f, 0x47, 0x72,
-	0x61, 0x70, 0x68, 0x71, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1a, 0x0a,
-	0x08, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52,
-	0x08, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x6c, 0x75,
-	0x63, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x70, 0x6c, 0x75, 0x63, 0x6b, 0x22,
-	0x88, 0x01, 0x0a, 0x0c, 0x47, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x46, 0x69, 0x65, 0x6c, 0x64,
-	0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x08, 0x52, 0x08, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x12, 0x12, 0x0a, 0x04,
-	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
-	0x12, 0x18, 0x0a, 0x07, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x07, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6f, 0x6d,
-	0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x6f, 0x6d, 0x69, 0x74, 0x12, 0x1a,
-	0x0a, 0x08, 0x72, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x08, 0x72, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x72, 0x2a, 0x67, 0x0a, 0x0b, 0x47, 0x72,
-	0x61, 0x70, 0x68, 0x71, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x12, 0x22, 0x0a, 0x1e, 0x47, 0x52, 0x41,
-	0x50, 0x48, 0x51, 0x4c, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x51, 0x55, 0x45, 0x52, 0x59, 0x5f,
-	0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x19, 0x0a,
-	0x15, 0x47, 0x52, 0x41, 0x50, 0x48, 0x51, 0x4c, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x4d, 0x55,
-	0x54, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x10, 0x01, 0x12, 0x19, 0x0a, 0x15, 0x47, 0x52, 0x41, 0x50,
-	0x48, 0x51, 0x4c, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x52, 0x45, 0x53, 0x4f, 0x4c, 0x56, 0x45,
-	0x52, 0x10, 0x02, 0x3a, 0x56, 0x0a, 0x07, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x1f,
-	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
-	0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18,
-	0xb8, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c,
-	0x2e, 0x76, 0x31, 0x2e, 0x47, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x53, 0x65, 0x72, 0x76, 0x69,
-	0x63, 0x65, 0x52, 0x07, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x3a, 0x4e, 0x0a, 0x05, 0x66,
-	0x69, 0x65, 0x6c, 0x64, 0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69,
-	0x6f, 0x6e, 0x73, 0x18, 0xb8, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x67, 0x72, 0x61,
-	0x70, 0x68, 0x71, 0x6c, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x46,
-	0x69, 0x65, 0x6c, 0x64, 0x52, 0x05, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x3a, 0x52, 0x0a, 0x06, 0x73,
-	0x63, 0x68, 0x65, 0x6d, 0x61, 0x12, 0x1e, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x4f, 0x70,
-	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xb8, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67,
+	0x52, 0x08, 0x69, 0x6e, 0x73, 0x65, 0x63, 0x75, 0x72, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x61,
+	0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6e,
+	0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x22, 0xf6, 0x01, 0x0a, 0x0d, 0x47, 0x72, 0x61,
+	0x70, 0x68, 0x71, 0x6c, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x12, 0x2b, 0x0a, 0x04, 0x74, 0x79,
+	0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x67, 0x72, 0x61, 0x70, 0x68,
+	0x71, 0x6c, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x54, 0x79, 0x70,
+	0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x34, 0x0a, 0x07, 0x72,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
 	0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x72, 0x61, 0x70, 0x68, 0x71,
-	0x6c, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x52, 0x06, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x42,
-	0xa8, 0x01, 0x0a, 0x0e, 0x63, 0x6f, 0x6d, 0x2e, 0x67, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x2e,
-	0x76, 0x31, 0x42, 0x0c, 0x47, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x50, 0x72, 0x6f, 0x74, 0x6f,
-	0x50, 0x01, 0x5a, 0x3f, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6e,
+	0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x37, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x67, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x2e, 0x76, 0x31,
+	0x2e, 0x47, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x52, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x35, 0x0a, 0x06, 0x70, 0x6f,
+	0x6c, 0x69, 0x63, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x67, 0x72, 0x61,
+	0x70, 0x68, 0x71, 0x6c, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x43,
+	0x61, 0x6c, 0x6c, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x06, 0x70, 0x6f, 0x6c, 0x69, 0x63,
+	0x79, 0x22, 0x7c, 0x0a, 0x0e, 0x47, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x6c, 0x75, 0x63, 0x6b,
+	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x70, 0x6c, 0x75, 0x63, 0x6b, 0x73, 0x12,
+	0x1d, 0x0a, 0x0a, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x5f, 0x6d, 0x61, 0x73, 0x6b, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x09, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x4d, 0x61, 0x73, 0x6b, 0x12, 0x1f,
+	0x0a, 0x0b, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x6d, 0x61, 0x73, 0x6b, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0a, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4d, 0x61, 0x73, 0x6b, 0x22,
+	0x63, 0x0a, 0x0f, 0x47, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x12, 0x14,
+	0x0a, 0x05, 0x70, 0x6c, 0x75, 0x63, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x70,
+	0x6c, 0x75, 0x63, 0x6b, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x22, 0x83, 0x01, 0x0a, 0x11, 0x47, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c,
+	0x43, 0x61, 0x6c, 0x6c, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x74, 0x69,
+	0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x74, 0x69, 0x6d,
+	0x65, 0x6f, 0x75, 0x74, 0x12, 0x34, 0x0a, 0x05, 0x72, 0x65, 0x74, 0x72, 0x79, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x67, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x2e, 0x76, 0x31,
+	0x2e, 0x47, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x52, 0x65, 0x74, 0x72, 0x79, 0x50, 0x6f, 0x6c,
+	0x69, 0x63, 0x79, 0x52, 0x05, 0x72, 0x65, 0x74, 0x72, 0x79, 0x12, 0x1e, 0x0a, 0x0a, 0x69, 0x64,
+	0x65, 0x6d, 0x70, 0x6f, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a,
+	0x69, 0x64, 0x65, 0x6d, 0x70, 0x6f, 0x74, 0x65, 0x6e, 0x74, 0x22, 0xe6, 0x01, 0x0a, 0x12, 0x47,
+	0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x52, 0x65, 0x74, 0x72, 0x79, 0x50, 0x6f, 0x6c, 0x69, 0x63,
+	0x79, 0x12, 0x21, 0x0a, 0x0c, 0x6d, 0x61, 0x78, 0x5f, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74,
+	0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x6d, 0x61, 0x78, 0x41, 0x74, 0x74, 0x65,
+	0x6d, 0x70, 0x74, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x5f,
+	0x62, 0x61, 0x63, 0x6b, 0x6f, 0x66, 0x66, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x69,
+	0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x42, 0x61, 0x63, 0x6b, 0x6f, 0x66, 0x66, 0x12, 0x1f, 0x0a,
+	0x0b, 0x6d, 0x61, 0x78, 0x5f, 0x62, 0x61, 0x63, 0x6b, 0x6f, 0x66, 0x66, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0a, 0x6d, 0x61, 0x78, 0x42, 0x61, 0x63, 0x6b, 0x6f, 0x66, 0x66, 0x12, 0x2d,
+	0x0a, 0x12, 0x62, 0x61, 0x63, 0x6b, 0x6f, 0x66, 0x66, 0x5f, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70,
+	0x6c, 0x69, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x11, 0x62, 0x61, 0x63, 0x6b,
+	0x6f, 0x66, 0x66, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x6c, 0x69, 0x65, 0x72, 0x12, 0x34, 0x0a,
+	0x16, 0x72, 0x65, 0x74, 0x72, 0x79, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x14, 0x72,
+	0x65, 0x74, 0x72, 0x79, 0x61, 0x62, 0x6c, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x43, 0x6f,
+	0x64, 0x65, 0x73, 0x22, 0xa5, 0x01, 0x0a, 0x0c, 0x47, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x46,
+	0x69, 0x65, 0x6c, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64,
+	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x12, 0x12,
+	0x0a, 0x04, 0x6f, 0x6d, 0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x6f, 0x6d,
+	0x69, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x72, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x72, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x72, 0x12, 0x1b,
+	0x0a, 0x09, 0x61, 0x6e, 0x79, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x08, 0x61, 0x6e, 0x79, 0x54, 0x79, 0x70, 0x65, 0x73, 0x22, 0x3f, 0x0a, 0x0d, 0x47,
+	0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x12, 0x0a, 0x04,
+	0x6b, 0x65, 0x79, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x6b, 0x65, 0x79, 0x73,
+	0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x72, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x72, 0x22, 0x39, 0x0a, 0x0b,
+	0x47, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x4e, 0x6f, 0x64, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x72,
+	0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x72,
+	0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x72, 0x2a, 0x67, 0x0a, 0x0b, 0x47, 0x72, 0x61, 0x70, 0x68,
+	0x71, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x12, 0x22, 0x0a, 0x1e, 0x47, 0x52, 0x41, 0x50, 0x48, 0x51,
+	0x4c, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x51, 0x55, 0x45, 0x52, 0x59, 0x5f, 0x55, 0x4e, 0x53,
+	0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x19, 0x0a, 0x15, 0x47, 0x52,
+	0x41, 0x50, 0x48, 0x51, 0x4c, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x4d, 0x55, 0x54, 0x41, 0x54,
+	0x49, 0x4f, 0x4e, 0x10, 0x01, 0x12, 0x19, 0x0a, 0x15, 0x47, 0x52, 0x41, 0x50, 0x48, 0x51, 0x4c,
+	0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x52, 0x45, 0x53, 0x4f, 0x4c, 0x56, 0x45, 0x52, 0x10, 0x02,
+	0x3a, 0x56, 0x0a, 0x07, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x1f, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xb8, 0x08, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x2e, 0x76, 0x31,
+	0x2e, 0x47, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x52,
+	0x07, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x3a, 0x4e, 0x0a, 0x05, 0x66, 0x69, 0x65, 0x6c,
+	0x64, 0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x18, 0xb8, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x67, 0x72, 0x61, 0x70, 0x68, 0x71,
+	0x6c, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x46, 0x69, 0x65, 0x6c,
+	0x64, 0x52, 0x05, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x3a, 0x52, 0x0a, 0x06, 0x73, 0x63, 0x68, 0x65,
+	0x6d, 0x61, 0x12, 0x1e, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x18, 0xb8, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x72, 0x61, 0x70,
+	0x68, 0x71, 0x6c, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x53, 0x63,
+	0x68, 0x65, 0x6d, 0x61, 0x52, 0x06, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x3a, 0x53, 0x0a, 0x06,
+	0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x1f, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xb8, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19,
+	0x2e, 0x67, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x72, 0x61, 0x70,
+	0x68, 0x71, 0x6c, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x06, 0x65, 0x6e, 0x74, 0x69, 0x74,
+	0x79, 0x3a, 0x4d, 0x0a, 0x04, 0x6e, 0x6f, 0x64, 0x65, 0x12, 0x1f, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xb9, 0x08, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x17, 0x2e, 0x67, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x2e, 0x76, 0x31, 0x2e, 0x47,
+	0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x04, 0x6e, 0x6f, 0x64, 0x65,
+	0x42, 0x39, 0x5a, 0x37, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6e,
 	0x65, 0x62, 0x75, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x2f, 0x6e, 0x65, 0x62, 0x75, 0x63, 0x6c, 0x6f,
-	0x75, 0x64, 0x2d, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2f, 0x67, 0x65, 0x6e, 0x2f, 0x67,
-	0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x2f, 0x76, 0x31, 0x3b, 0x67, 0x72, 0x61, 0x70, 0x68, 0x71,
-	0x6c, 0x76, 0x31, 0xa2, 0x02, 0x03, 0x47, 0x58, 0x58, 0xaa, 0x02, 0x0a, 0x47, 0x72, 0x61, 0x70,
-	0x68, 0x71, 0x6c, 0x2e, 0x56, 0x31, 0xca, 0x02, 0x0a, 0x47, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c,
-	0x5c, 0x56, 0x31, 0xe2, 0x02, 0x16, 0x47, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x5c, 0x56, 0x31,
-	0x5c, 0x47, 0x50, 0x42, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0xea, 0x02, 0x0b, 0x47,
-	0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x3a, 0x3a, 0x56, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x33,
+	0x75, 0x64, 0x2d, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2f, 0x67, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x2f, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
 }
 
 var (
@@ -648,33 +1128,44 @@ func file_graphql_v1_graphql_proto_rawDescGZIP() []byte {
 }
 
 var file_graphql_v1_graphql_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_graphql_v1_graphql_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_graphql_v1_graphql_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
 var file_graphql_v1_graphql_proto_goTypes = []any{
 	(GraphqlType)(0),                    // 0: graphql.v1.GraphqlType
 	(*GraphqlService)(nil),              // 1: graphql.v1.GraphqlService
 	(*GraphqlSchema)(nil),               // 2: graphql.v1.GraphqlSchema
 	(*GraphqlRequest)(nil),              // 3: graphql.v1.GraphqlRequest
 	(*GraphqlResponse)(nil),             // 4: graphql.v1.GraphqlResponse
-	(*GraphqlField)(nil),                // 5: graphql.v1.GraphqlField
-	(*descriptorpb.ServiceOptions)(nil), // 6: google.protobuf.ServiceOptions
-	(*descriptorpb.FieldOptions)(nil),   // 7: google.protobuf.FieldOptions
-	(*descriptorpb.MethodOptions)(nil),  // 8: google.protobuf.MethodOptions
+	(*GraphqlCallPolicy)(nil),           // 5: graphql.v1.GraphqlCallPolicy
+	(*GraphqlRetryPolicy)(nil),          // 6: graphql.v1.GraphqlRetryPolicy
+	(*GraphqlField)(nil),                // 7: graphql.v1.GraphqlField
+	(*GraphqlEntity)(nil),               // 8: graphql.v1.GraphqlEntity
+	(*GraphqlNode)(nil),                 // 9: graphql.v1.GraphqlNode
+	(*descriptorpb.ServiceOptions)(nil), // 10: google.protobuf.ServiceOptions
+	(*descriptorpb.FieldOptions)(nil),   // 11: google.protobuf.FieldOptions
+	(*descriptorpb.MethodOptions)(nil),  // 12: google.protobuf.MethodOptions
+	(*descriptorpb.MessageOptions)(nil), // 13: google.protobuf.MessageOptions
 }
 var file_graphql_v1_graphql_proto_depIdxs = []int32{
-	0, // 0: graphql.v1.GraphqlSchema.type:type_name -> graphql.v1.GraphqlType
-	3, // 1: graphql.v1.GraphqlSchema.request:type_name -> graphql.v1.GraphqlRequest
-	4, // 2: graphql.v1.GraphqlSchema.response:type_name -> graphql.v1.GraphqlResponse
-	6, // 3: graphql.v1.service:extendee -> google.protobuf.ServiceOptions
-	7, // 4: graphql.v1.field:extendee -> google.protobuf.FieldOptions
-	8, // 5: graphql.v1.schema:extendee -> google.protobuf.MethodOptions
-	1, // 6: graphql.v1.service:type_name -> graphql.v1.GraphqlService
-	5, // 7: graphql.v1.field:type_name -> graphql.v1.GraphqlField
-	2, // 8: graphql.v1.schema:type_name -> graphql.v1.GraphqlSchema
-	9, // [9:9] is the sub-list for method output_type
-	9, // [9:9] is the sub-list for method input_type
-	6, // [6:9] is the sub-list for extension type_name
-	3, // [3:6] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	0,  // 0: graphql.v1.GraphqlSchema.type:type_name -> graphql.v1.GraphqlType
+	3,  // 1: graphql.v1.GraphqlSchema.request:type_name -> graphql.v1.GraphqlRequest
+	4,  // 2: graphql.v1.GraphqlSchema.response:type_name -> graphql.v1.GraphqlResponse
+	5,  // 3: graphql.v1.GraphqlSchema.policy:type_name -> graphql.v1.GraphqlCallPolicy
+	6,  // 4: graphql.v1.GraphqlCallPolicy.retry:type_name -> graphql.v1.GraphqlRetryPolicy
+	10, // 5: graphql.v1.service:extendee -> google.protobuf.ServiceOptions
+	11, // 6: graphql.v1.field:extendee -> google.protobuf.FieldOptions
+	12, // 7: graphql.v1.schema:extendee -> google.protobuf.MethodOptions
+	13, // 8: graphql.v1.entity:extendee -> google.protobuf.MessageOptions
+	13, // 9: graphql.v1.node:extendee -> google.protobuf.MessageOptions
+	1,  // 10: graphql.v1.service:type_name -> graphql.v1.GraphqlService
+	7,  // 11: graphql.v1.field:type_name -> graphql.v1.GraphqlField
+	2,  // 12: graphql.v1.schema:type_name -> graphql.v1.GraphqlSchema
+	8,  // 13: graphql.v1.entity:type_name -> graphql.v1.GraphqlEntity
+	9,  // 14: graphql.v1.node:type_name -> graphql.v1.GraphqlNode
+	15, // [15:15] is the sub-list for method output_type
+	15, // [15:15] is the sub-list for method input_type
+	10, // [10:15] is the sub-list for extension type_name
+	5,  // [5:10] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
 }
 
 func init() { file_graphql_v1_graphql_proto_init() }
@@ -732,6 +1223,30 @@ func file_graphql_v1_graphql_proto_init() {
 			}
 		}
 		file_graphql_v1_graphql_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*GraphqlCallPolicy); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_graphql_v1_graphql_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*GraphqlRetryPolicy); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_graphql_v1_graphql_proto_msgTypes[6].Exporter = func(v any, i int) any {
 			switch v := v.(*GraphqlField); i {
 			case 0:
 				return &v.state
@@ -743,6 +1258,30 @@ func file_graphql_v1_graphql_proto_init() {
 				return nil
 			}
 		}
+		file_graphql_v1_graphql_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*GraphqlEntity); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_graphql_v1_graphql_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*GraphqlNode); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -750,8 +1289,8 @@ func file_graphql_v1_graphql_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_graphql_v1_graphql_proto_rawDesc,
 			NumEnums:      1,
-			NumMessages:   5,
-			NumExtensions: 3,
+			NumMessages:   9,
+			NumExtensions: 5,
 			NumServices:   0,
 		},
 		GoTypes:           file_graphql_v1_graphql_proto_goTypes,
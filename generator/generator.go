@@ -20,13 +20,26 @@ import (
 
 type Template struct {
 	RootPackage *spec.Package
-
-	Packages   []*spec.Package
-	Types      []*spec.Message
-	Interfaces []*spec.Message
-	Enums      []*spec.Enum
-	Inputs     []*spec.Message
-	Services   []*spec.Service
+	Imports     []string
+
+	Packages []*spec.Package
+	Types    []*spec.Message
+	Enums    []*spec.Enum
+	Inputs   []*spec.Message
+	Services []*spec.Service
+
+	// Types and enums defined in other packages which this package refers
+	ExternalTypes []*spec.Message
+	ExternalEnums []*spec.Enum
+
+	// Definitions of Relay connection types which paginated queries return
+	Connections []string
+	// Definitions of custom scalars which fields refer, e.g. Int64
+	Scalars []string
+	// Definitions of unions which google.protobuf.Any fields are declared as
+	Unions []string
+	// Messages which google.protobuf.Any fields may contain, they are resolved as object types of unions
+	AnyTypes []*spec.Message
 }
 
 // Generator is struct for analyzing protobuf definition
@@ -67,12 +80,31 @@ func New(files []*spec.File, args *spec.Params) *Generator {
 }
 
 func (g *Generator) Generate(tmpl string, fs []string) ([]*pluginpb.CodeGeneratorResponse_File, error) {
-	services, err := g.analyzeServices()
+	templates, err := g.Analyze(fs)
 	if err != nil {
 		return nil, err
 	}
 
 	var outFiles []*pluginpb.CodeGeneratorResponse_File
+	for _, t := range templates {
+		file, err := g.generateFile(t, tmpl)
+		if err != nil {
+			return nil, err
+		}
+		outFiles = append(outFiles, file)
+	}
+	return outFiles, nil
+}
+
+// Analyze resolves dependencies of types in specified files and returns template data for each file.
+// The result is rendered as Go code by Generate, or used to build handlers at runtime without code generation.
+func (g *Generator) Analyze(fs []string) ([]*Template, error) {
+	services, err := g.analyzeServices()
+	if err != nil {
+		return nil, err
+	}
+
+	var templates []*Template
 	for _, f := range g.files {
 		for _, v := range fs {
 			if f.Filename() != v {
@@ -89,109 +121,94 @@ func (g *Generator) Generate(tmpl string, fs []string) ([]*pluginpb.CodeGenerato
 			if err := g.analyzeMessage(f); err != nil {
 				return nil, err
 			}
+			if err := g.validateMessages(f, s); err != nil {
+				return nil, err
+			}
 
-			file, err := g.generateFile(f, tmpl, s)
+			t, err := g.buildTemplate(f, s)
 			if err != nil {
 				return nil, err
 			}
-			outFiles = append(outFiles, file)
+			templates = append(templates, t)
 		}
 	}
-	return outFiles, nil
+	return templates, nil
 }
 
-func isDependedGoogleEmptyMessage(m *spec.Message, pkg string) bool {
-	return m.IsDepended(spec.DependTypeMessage, pkg) && spec.IsGooglePackage(m) && m.Name() == "Empty"
+func isGoogleEmptyMessage(m *spec.Message) bool {
+	return spec.IsGooglePackage(m) && m.Name() == "Empty"
 }
 
 // nolint: gocognit, funlen, gocyclo
-func (g *Generator) generateFile(file *spec.File, tmpl string, services []*spec.Service) (
-	*pluginpb.CodeGeneratorResponse_File,
-	error,
-) {
+func (g *Generator) buildTemplate(file *spec.File, services []*spec.Service) (*Template, error) {
 
-	var types, inputs, interfaces []*spec.Message
+	var types, inputs []*spec.Message
 	var enums []*spec.Enum
 	var packages []*spec.Package
 
 	for _, m := range g.messages {
-		// skip empty field message, otherwise graphql-go raise error
-		if len(m.Fields()) == 0 {
-			// although true, Google's empty message has no fields,
-			// ptypes package define a dummy field, that way no errors raised
-			if isDependedGoogleEmptyMessage(m, file.Package()) {
-				packages = append(packages, spec.NewGooglePackage(m))
-			}
+		// skip empty field message, otherwise schema raises error
+		if len(m.Fields()) == 0 || m.Package() != file.Package() {
 			continue
 		}
 		if m.IsDepended(spec.DependTypeMessage, file.Package()) {
-			switch {
-			case file.Package() == m.Package():
-				types = append(types, m)
-			case spec.IsGooglePackage(m):
-				packages = append(packages, spec.NewGooglePackage(m))
-			default:
-				packages = append(packages, spec.NewPackage(m))
-			}
+			types = append(types, m)
 		}
 		if m.IsDepended(spec.DependTypeInput, file.Package()) {
-			if !spec.IsGooglePackage(m) {
-				inputs = append(inputs, m)
-			}
+			inputs = append(inputs, m)
 		}
-		if m.IsDepended(spec.DependTypeInterface, file.Package()) {
-			interfaces = append(interfaces, m)
+	}
+
+	for _, e := range g.enums {
+		// skip empty values enum, otherwise schema raises error
+		if len(e.Values()) == 0 {
+			continue
+		}
+		if e.IsDepended(spec.DependTypeEnum, file.Package()) && file.Package() == e.Package() {
+			enums = append(enums, e)
+		}
+	}
+
+	externalTypes, externalEnums, err := g.collectExternals(file, services)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range externalTypes {
+		if spec.IsGooglePackage(m) {
+			packages = append(packages, spec.NewGooglePackage(m))
+		} else {
+			packages = append(packages, spec.NewPackage(m))
 		}
 	}
+	for _, e := range externalEnums {
+		packages = append(packages, spec.NewPackage(e))
+	}
 
+	// request and response structs of other packages are referred in resolvers
 	for _, s := range services {
+		var messages []*spec.Message
 		for _, q := range s.Queries {
-			input, output := q.Input, q.Output
-			if input.Package() != file.Package() {
-				if spec.IsGooglePackage(input) {
-					packages = append(packages, spec.NewGooglePackage(input))
-				} else {
-					packages = append(packages, spec.NewPackage(input))
-				}
-			}
-			if output.Package() != file.Package() {
-				if spec.IsGooglePackage(output) {
-					packages = append(packages, spec.NewGooglePackage(output))
-				} else {
-					packages = append(packages, spec.NewPackage(output))
-				}
-			}
+			messages = append(messages, q.Input, q.Output)
 		}
 		for _, m := range s.Mutations {
-			input, output := m.Input, m.Output
-			if input.Package() != file.Package() {
-				if spec.IsGooglePackage(input) {
-					packages = append(packages, spec.NewGooglePackage(input))
-				} else {
-					packages = append(packages, spec.NewPackage(input))
-				}
-			}
-			if output.Package() != file.Package() {
-				if spec.IsGooglePackage(output) {
-					packages = append(packages, spec.NewGooglePackage(output))
-				} else {
-					packages = append(packages, spec.NewPackage(output))
-				}
+			messages = append(messages, m.Input, m.Output)
+		}
+		for _, m := range messages {
+			switch {
+			case m.Package() == file.Package():
+			case spec.IsGooglePackage(m):
+				packages = append(packages, spec.NewGooglePackage(m))
+			default:
+				packages = append(packages, spec.NewPackage(m))
 			}
 		}
 	}
 
-	for _, e := range g.enums {
-		// skip empty values enum, otherwise graphql-go raise error
-		if len(e.Values()) == 0 {
-			continue
-		}
-		if e.IsDepended(spec.DependTypeEnum, file.Package()) {
-			if file.Package() == e.Package() || spec.IsGooglePackage(e) {
-				enums = append(enums, e)
-			} else {
-				packages = append(packages, spec.NewPackage(e))
-			}
+	// collect additional imports which generated call policies refer
+	var imports []string
+	for _, s := range services {
+		for _, m := range s.CallPolicies() {
+			imports = append(imports, m.CallPolicyImports()...)
 		}
 	}
 
@@ -206,10 +223,22 @@ func (g *Generator) generateFile(file *spec.File, tmpl string, services []*spec.
 		stack[p.Path] = struct{}{}
 	}
 
+	// drop duplicate imports
+	uniqueImports := make([]string, 0)
+	importStack := make(map[string]struct{})
+	for _, v := range imports {
+		if _, ok := importStack[v]; ok {
+			continue
+		}
+		uniqueImports = append(uniqueImports, v)
+		importStack[v] = struct{}{}
+	}
+
 	// Sort by name to avoid to appear some diff on each generation
 	sort.Slice(uniquePackages, func(i, j int) bool {
 		return uniquePackages[i].Name > uniquePackages[j].Name
 	})
+	sort.Strings(uniqueImports)
 	sort.Slice(types, func(i, j int) bool {
 		return types[i].Name() > types[j].Name()
 	})
@@ -219,24 +248,112 @@ func (g *Generator) generateFile(file *spec.File, tmpl string, services []*spec.
 	sort.Slice(inputs, func(i, j int) bool {
 		return inputs[i].Name() > inputs[j].Name()
 	})
-	sort.Slice(interfaces, func(i, j int) bool {
-		return interfaces[i].Name() > interfaces[j].Name()
+	sort.Slice(externalTypes, func(i, j int) bool {
+		return externalTypes[i].FullPath() > externalTypes[j].FullPath()
+	})
+	sort.Slice(externalEnums, func(i, j int) bool {
+		return externalEnums[i].FullPath() > externalEnums[j].FullPath()
 	})
 	sort.Slice(services, func(i, j int) bool {
 		return services[i].Name() > services[j].Name()
 	})
 
+	// connection types may be shared between queries
+	var connections []string
+	connectionStack := make(map[string]struct{})
+	for _, s := range services {
+		for _, q := range s.Queries {
+			for _, d := range q.ConnectionDefinitions() {
+				if _, ok := connectionStack[d]; ok {
+					continue
+				}
+				connections = append(connections, d)
+				connectionStack[d] = struct{}{}
+			}
+		}
+	}
+
+	// custom scalars are declared once even if many fields refer them
+	messages := append(append(append([]*spec.Message{}, types...), inputs...), externalTypes...)
+	for _, s := range services {
+		for _, q := range s.Queries {
+			messages = append(messages, q.Input, q.Output)
+		}
+		for _, m := range s.Mutations {
+			messages = append(messages, m.Input, m.Output)
+		}
+	}
+	var scalars []string
+	scalarStack := make(map[string]struct{})
+	for _, m := range messages {
+		// query may return the message which is mapped to scalar as it is
+		if d := m.ScalarDefinition(); d != "" {
+			if _, ok := scalarStack[d]; !ok {
+				scalars = append(scalars, d)
+				scalarStack[d] = struct{}{}
+			}
+		}
+		// google's ptypes have their own definitions
+		if spec.IsGooglePackage(m) {
+			continue
+		}
+		for _, f := range m.Fields() {
+			d := f.ScalarDefinition()
+			if _, ok := scalarStack[d]; ok || d == "" {
+				continue
+			}
+			scalars = append(scalars, d)
+			scalarStack[d] = struct{}{}
+		}
+	}
+
+	// unions of Any fields and their possible messages
+	var unions []string
+	var anyTypes []*spec.Message
+	unionStack := make(map[string]struct{})
+	anyTypeStack := make(map[*spec.Message]struct{})
+	for _, m := range append(append([]*spec.Message{}, types...), externalTypes...) {
+		for _, f := range m.Fields() {
+			if !f.IsUnion() {
+				continue
+			}
+			if _, ok := unionStack[f.UnionName()]; !ok {
+				unions = append(unions, f.UnionDefinition())
+				unionStack[f.UnionName()] = struct{}{}
+			}
+			for _, u := range f.UnionTypes {
+				if _, ok := anyTypeStack[u]; !ok {
+					anyTypes = append(anyTypes, u)
+					anyTypeStack[u] = struct{}{}
+				}
+			}
+		}
+	}
+	sort.Slice(anyTypes, func(i, j int) bool {
+		return anyTypes[i].FullPath() < anyTypes[j].FullPath()
+	})
+
 	root := spec.NewPackage(file)
 	t := &Template{
-		RootPackage: root,
-		Packages:    uniquePackages,
-		Types:       types,
-		Enums:       enums,
-		Inputs:      inputs,
-		Interfaces:  interfaces,
-		Services:    services,
+		RootPackage:   root,
+		Imports:       uniqueImports,
+		Packages:      uniquePackages,
+		Types:         types,
+		Enums:         enums,
+		Inputs:        inputs,
+		Services:      services,
+		ExternalTypes: externalTypes,
+		ExternalEnums: externalEnums,
+		Connections:   connections,
+		Scalars:       scalars,
+		Unions:        unions,
+		AnyTypes:      anyTypes,
 	}
+	return t, nil
+}
 
+func (g *Generator) generateFile(t *Template, tmpl string) (*pluginpb.CodeGeneratorResponse_File, error) {
+	root := t.RootPackage
 	buf := new(bytes.Buffer)
 	if tmpl, err := template.New("go").Parse(tmpl); err != nil {
 		return nil, err
@@ -264,6 +381,83 @@ func (g *Generator) generateFile(file *spec.File, tmpl string, services []*spec.
 	}, nil
 }
 
+// collectExternals collects messages and enums in other packages which are referred from this package, including transitive ones.
+// Their definitions have to be provided together because schema is built from definitions of each handler.
+func (g *Generator) collectExternals(file *spec.File, services []*spec.Service) ([]*spec.Message, []*spec.Enum, error) {
+	var messages []*spec.Message
+	var enums []*spec.Enum
+	visited := make(map[*spec.Message]struct{})
+	visitedEnums := make(map[*spec.Enum]struct{})
+
+	var walk func(m *spec.Message) error
+	walk = func(m *spec.Message) error {
+		if _, ok := visited[m]; ok {
+			return nil
+		}
+		visited[m] = struct{}{}
+		// message which is mapped to scalar is declared as custom scalar
+		if m.ScalarName() != "" {
+			return nil
+		}
+		if m.Package() != file.Package() && (len(m.Fields()) > 0 || isGoogleEmptyMessage(m)) {
+			messages = append(messages, m)
+		}
+		// Google's ptypes are defined with fixed definitions
+		if spec.IsGooglePackage(m) {
+			return nil
+		}
+		for _, f := range m.Fields() {
+			switch f.Type() {
+			case descriptorpb.FieldDescriptorProto_TYPE_MESSAGE:
+				dep := g.getMessage(f.TypeName())
+				if dep == nil {
+					return errors.New("failed to resolve field message type: " + f.TypeName())
+				}
+				if err := walk(dep); err != nil {
+					return err
+				}
+				for _, u := range f.UnionTypes {
+					if err := walk(u); err != nil {
+						return err
+					}
+				}
+			case descriptorpb.FieldDescriptorProto_TYPE_ENUM:
+				e := g.getEnum(f.TypeName())
+				if e == nil {
+					return errors.New("failed to resolve field enum name: " + f.TypeName())
+				}
+				if _, ok := visitedEnums[e]; ok || e.Package() == file.Package() || len(e.Values()) == 0 {
+					continue
+				}
+				visitedEnums[e] = struct{}{}
+				enums = append(enums, e)
+			}
+		}
+		return nil
+	}
+
+	roots := make([]*spec.Message, 0)
+	for _, m := range g.messages {
+		if m.Package() == file.Package() {
+			roots = append(roots, m)
+		}
+	}
+	for _, s := range services {
+		for _, q := range s.Queries {
+			roots = append(roots, q.Input, q.Output)
+		}
+		for _, m := range s.Mutations {
+			roots = append(roots, m.Input, m.Output)
+		}
+	}
+	for _, m := range roots {
+		if err := walk(m); err != nil {
+			return nil, nil, err
+		}
+	}
+	return messages, enums, nil
+}
+
 func (g *Generator) getMessage(name string) *spec.Message {
 	if v, ok := g.messages[name]; ok {
 		return v
@@ -297,6 +491,22 @@ func (g *Generator) analyzeMessage(file *spec.File) error {
 	return nil
 }
 
+// validateMessages checks federation entities and Relay nodes in the package, whose resolvers must be defined in the same package
+func (g *Generator) validateMessages(file *spec.File, services []*spec.Service) error {
+	for _, m := range g.messages {
+		if m.Package() != file.Package() {
+			continue
+		}
+		if err := m.ValidateEntity(services); err != nil {
+			return err
+		}
+		if err := m.ValidateNode(services); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // nolint: interfacer
 func (g *Generator) analyzeEnum(file *spec.File) {
 	for _, e := range g.enums {
@@ -326,10 +536,16 @@ func (g *Generator) analyzeServices() (map[string][]*spec.Service, error) {
 }
 
 func (g *Generator) analyzeService(f *spec.File, s *spec.Service) error {
+	if err := s.ValidateNamespace(); err != nil {
+		return err
+	}
 	for _, m := range s.Methods() {
 		if m.Schema == nil {
 			continue
 		}
+		if err := m.ValidateCallPolicy(); err != nil {
+			return err
+		}
 		var input, output *spec.Message
 
 		if input = g.getMessage(m.Input()); input == nil {
@@ -342,12 +558,21 @@ func (g *Generator) analyzeService(f *spec.File, s *spec.Service) error {
 		switch m.Schema.GetType() {
 		case graphqlv1.GraphqlType_GRAPHQL_TYPE_QUERY_UNSPECIFIED, graphqlv1.GraphqlType_GRAPHQL_TYPE_RESOLVER:
 			q := spec.NewQuery(m, input, output, g.args.FieldCamelCase)
+			if err := q.ValidateConnection(); err != nil {
+				return err
+			}
+			if err := q.ValidateFieldMask(); err != nil {
+				return err
+			}
 			if err := g.analyzeQuery(f, q); err != nil {
 				return err
 			}
 			s.Queries = append(s.Queries, q)
 		case graphqlv1.GraphqlType_GRAPHQL_TYPE_MUTATION:
 			mu := spec.NewMutation(m, input, output, g.args.FieldCamelCase)
+			if err := mu.ValidateUpdateMask(); err != nil {
+				return err
+			}
 			if err := g.analyzeMutation(f, mu); err != nil {
 				return err
 			}
@@ -393,6 +618,19 @@ func (g *Generator) analyzeFields(
 	asInput,
 	recursive bool,
 ) error {
+	return g.analyzeFieldsOnce(rootPkg, orig, fields, asInput, recursive, map[*spec.Message]struct{}{orig: {}})
+}
+
+// analyzeFieldsOnce analyzes fields with messages which have been visited from the root,
+// so that mutually recursive messages like google.protobuf.Struct and Value don't loop infinitely.
+func (g *Generator) analyzeFieldsOnce(
+	rootPkg string,
+	orig *spec.Message,
+	fields []*spec.Field,
+	asInput,
+	recursive bool,
+	visited map[*spec.Message]struct{},
+) error {
 
 	for _, f := range fields {
 		switch f.Type() {
@@ -402,6 +640,9 @@ func (g *Generator) analyzeFields(
 				return errors.New("failed to resolve field message type: " + f.TypeName())
 			}
 			f.DependType = m
+			if err := g.analyzeUnion(rootPkg, f); err != nil {
+				return err
+			}
 			if asInput {
 				g.logger.Write("package %s depends on input %s", rootPkg, m.FullPath())
 				m.Depend(spec.DependTypeInput, rootPkg)
@@ -410,8 +651,6 @@ func (g *Generator) analyzeFields(
 				switch {
 				case m == orig:
 					g.logger.Write("%s has cyclic dependencies of field %s\n", m.Name(), f.Name())
-					f.IsCyclic = true
-					m.Depend(spec.DependTypeInterface, rootPkg)
 				case !recursive:
 					m.Depend(spec.DependTypeMessage, rootPkg)
 				default:
@@ -419,11 +658,14 @@ func (g *Generator) analyzeFields(
 				}
 			}
 
-			// Guard from recursive with infinite loop
-			if m != orig {
-				if err := g.analyzeFields(rootPkg, m, m.Fields(), asInput, true); err != nil {
-					return err
-				}
+			// Guard from recursive with infinite loop.
+			// Messages which are mapped to scalars and google's ptypes don't need their fields.
+			if _, ok := visited[m]; ok || m.ScalarName() != "" || spec.IsGooglePackage(m) {
+				continue
+			}
+			visited[m] = struct{}{}
+			if err := g.analyzeFieldsOnce(rootPkg, m, m.Fields(), asInput, true, visited); err != nil {
+				return err
 			}
 		case descriptorpb.FieldDescriptorProto_TYPE_ENUM:
 			e := g.getEnum(f.TypeName())
@@ -437,3 +679,35 @@ func (g *Generator) analyzeFields(
 	}
 	return nil
 }
+
+// analyzeUnion resolves messages which any_types option of google.protobuf.Any field declares.
+// They are depended as object types because union is output only.
+func (g *Generator) analyzeUnion(rootPkg string, f *spec.Field) error {
+	if len(f.AnyTypes()) == 0 {
+		return nil
+	}
+	if f.TypeName() != "google.protobuf.Any" {
+		return fmt.Errorf("any_types option is only available on google.protobuf.Any field, but %s is %s", f.Name(), f.TypeName())
+	}
+	if f.UnionTypes == nil {
+		for _, name := range f.AnyTypes() {
+			m := g.getMessage(name)
+			if m == nil {
+				return errors.New("failed to resolve any_types message: " + name)
+			}
+			f.UnionTypes = append(f.UnionTypes, m)
+		}
+	}
+	for _, m := range f.UnionTypes {
+		// Guard from recursive with infinite loop
+		if m.IsDepended(spec.DependTypeMessage, rootPkg) {
+			continue
+		}
+		g.logger.Write("package %s depends on union member %s", rootPkg, m.FullPath())
+		m.Depend(spec.DependTypeMessage, rootPkg)
+		if err := g.analyzeFields(rootPkg, m, m.Fields(), false, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
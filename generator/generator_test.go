@@ -0,0 +1,80 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	graphqlv1 "github.com/nebucloud/nebucloud-gateway/gen/go/graphql/v1"
+	"github.com/nebucloud/nebucloud-gateway/spec"
+)
+
+// structProto declares mutation whose input refers google.protobuf.Struct,
+// which is mutually recursive with google.protobuf.Value and ListValue
+const structProto = `
+name: "settings/settings.proto"
+package: "settings"
+dependency: ["graphql/v1/graphql.proto", "google/protobuf/struct.proto"]
+options { go_package: "example.com/settings;settings" }
+service {
+  name: "SettingService"
+  method {
+    name: "UpdateSetting" input_type: ".settings.UpdateSettingRequest" output_type: ".settings.Setting"
+    options { [graphql.v1.schema] { type: GRAPHQL_TYPE_MUTATION name: "updateSetting" request { name: "input" } } }
+  }
+}
+message_type {
+  name: "Setting"
+  field { name: "name" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING }
+  field { name: "attributes" number: 2 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".google.protobuf.Struct" }
+}
+message_type {
+  name: "UpdateSettingRequest"
+  field { name: "name" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING }
+  field { name: "attributes" number: 2 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".google.protobuf.Struct" }
+  field { name: "value" number: 3 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".google.protobuf.Value" }
+  field { name: "values" number: 4 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".google.protobuf.ListValue" }
+}
+`
+
+func analyzeTestProto(t *testing.T, text string) ([]*Template, error) {
+	var fdp descriptorpb.FileDescriptorProto
+	assert.NoError(t, prototext.Unmarshal([]byte(text), &fdp))
+
+	params := &spec.Params{}
+	var files []*spec.File
+	for _, d := range []*descriptorpb.FileDescriptorProto{
+		protodesc.ToFileDescriptorProto(graphqlv1.File_graphql_v1_graphql_proto),
+		protodesc.ToFileDescriptorProto(structpb.File_google_protobuf_struct_proto),
+		protodesc.ToFileDescriptorProto(fieldmaskpb.File_google_protobuf_field_mask_proto),
+		&fdp,
+	} {
+		files = append(files, spec.NewFile(d, nil, params))
+	}
+	return New(files, params).Analyze([]string{fdp.GetName()})
+}
+
+func TestAnalyzeRecursiveGoogleMessagesInMutationInput(t *testing.T) {
+	templates, err := analyzeTestProto(t, structProto)
+	assert.NoError(t, err)
+	assert.Len(t, templates, 1)
+	assert.Len(t, templates[0].Services[0].Mutations, 1)
+}
+
+func TestAnalyzeRejectsAmbiguousUpdateMask(t *testing.T) {
+	text := strings.NewReplacer(
+		`"google/protobuf/struct.proto"]`, `"google/protobuf/struct.proto", "google/protobuf/field_mask.proto"]`,
+		`request { name: "input" }`, `request { name: "input" update_mask: "update_mask" }`,
+		`  field { name: "values" number: 4`, `  field { name: "update_mask" number: 5 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".google.protobuf.FieldMask" }
+  field { name: "values" number: 4`,
+	).Replace(structProto)
+
+	_, err := analyzeTestProto(t, text)
+	assert.EqualError(t, err, "update mask update_mask of settings.UpdateSettingRequest is ambiguous because the request has multiple message fields: attributes, value, values")
+}
@@ -0,0 +1,9 @@
+package anypb
+
+import (
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// Expose Google defined ptypes as this package types.
+// Any is represented as JSON scalar, or union of messages which any_types field option declares.
+type Any = anypb.Any
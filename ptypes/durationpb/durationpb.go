@@ -0,0 +1,9 @@
+package durationpb
+
+import (
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// Expose Google defined ptypes as this package types.
+// Duration is represented as Duration scalar of string like "1.5s" or "PT1.5S", not as object type.
+type Duration = durationpb.Duration
@@ -0,0 +1,9 @@
+package fieldmaskpb
+
+import (
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// Expose Google defined ptypes as this package types.
+// FieldMask is represented as FieldMask scalar of comma separated paths, not as object type.
+type FieldMask = fieldmaskpb.FieldMask
@@ -0,0 +1,24 @@
+// Package ptypes provides GraphQL definitions of google.protobuf messages which are exposed as object types.
+// Generated code refers them from packages of each ptype, and handlers built at runtime look them up by full name.
+package ptypes
+
+import (
+	gql_ptypes_emptypb "github.com/nebucloud/nebucloud-gateway/ptypes/emptypb"
+	gql_ptypes_timestamppb "github.com/nebucloud/nebucloud-gateway/ptypes/timestamppb"
+	gql_ptypes_wrapperspb "github.com/nebucloud/nebucloud-gateway/ptypes/wrapperspb"
+)
+
+// Definitions are functions which return object and input type definitions of google.protobuf messages, keyed by full name.
+// Duration, Struct, Any and FieldMask are always mapped to scalars, so that they have no definitions.
+var Definitions = map[string][2]func() string{
+	"google.protobuf.Timestamp":   {gql_ptypes_timestamppb.Gql__type_Timestamp, gql_ptypes_timestamppb.Gql__input_Timestamp},
+	"google.protobuf.Empty":       {gql_ptypes_emptypb.Gql__type_Empty, gql_ptypes_emptypb.Gql__input_Empty},
+	"google.protobuf.DoubleValue": {gql_ptypes_wrapperspb.Gql__type_DoubleValue, gql_ptypes_wrapperspb.Gql__input_DoubleValue},
+	"google.protobuf.FloatValue":  {gql_ptypes_wrapperspb.Gql__type_FloatValue, gql_ptypes_wrapperspb.Gql__input_FloatValue},
+	"google.protobuf.Int64Value":  {gql_ptypes_wrapperspb.Gql__type_Int64Value, gql_ptypes_wrapperspb.Gql__input_Int64Value},
+	"google.protobuf.UInt64Value": {gql_ptypes_wrapperspb.Gql__type_UInt64Value, gql_ptypes_wrapperspb.Gql__input_UInt64Value},
+	"google.protobuf.Int32Value":  {gql_ptypes_wrapperspb.Gql__type_Int32Value, gql_ptypes_wrapperspb.Gql__input_Int32Value},
+	"google.protobuf.UInt32Value": {gql_ptypes_wrapperspb.Gql__type_UInt32Value, gql_ptypes_wrapperspb.Gql__input_UInt32Value},
+	"google.protobuf.BoolValue":   {gql_ptypes_wrapperspb.Gql__type_BoolValue, gql_ptypes_wrapperspb.Gql__input_BoolValue},
+	"google.protobuf.StringValue": {gql_ptypes_wrapperspb.Gql__type_StringValue, gql_ptypes_wrapperspb.Gql__input_StringValue},
+}
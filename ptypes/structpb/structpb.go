@@ -0,0 +1,13 @@
+package structpb
+
+import (
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Expose Google defined ptypes as this package types.
+// Struct, Value and ListValue are represented as JSON scalar, not as object types.
+type (
+	Struct    = structpb.Struct
+	Value     = structpb.Value
+	ListValue = structpb.ListValue
+)
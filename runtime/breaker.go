@@ -0,0 +1,298 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultBreakerWindow           = 10 * time.Second
+	defaultBreakerBuckets          = 10
+	defaultBreakerMinRequests      = 20
+	defaultBreakerFailureRatio     = 0.5
+	defaultBreakerOpenTimeout      = 5 * time.Second
+	defaultBreakerHalfOpenRequests = 1
+)
+
+// CircuitState is a state of circuit breaker
+type CircuitState int
+
+const (
+	// CircuitClosed lets all calls through and records their results
+	CircuitClosed CircuitState = iota
+	// CircuitOpen fails all calls fast until OpenTimeout elapsed
+	CircuitOpen
+	// CircuitHalfOpen lets a limited number of trial calls through
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// CircuitBreakerConfig configures circuit breaker of each backend host.
+// Zero value fields are filled with defaults.
+type CircuitBreakerConfig struct {
+	// Rolling window to compute failure rate, default is 10s
+	Window time.Duration
+	// Number of buckets which window is divided, default is 10
+	Buckets int
+	// Minimum number of calls in window before breaker can open, default is 20
+	MinRequests int
+	// Failure rate to open breaker, default is 0.5
+	FailureRatio float64
+	// Duration of open state before trying half-open, default is 5s
+	OpenTimeout time.Duration
+	// Number of successful trial calls in half-open state to close breaker, default is 1
+	HalfOpenRequests int
+	// Status codes counted as backend failure.
+	// Default is UNAVAILABLE, DEADLINE_EXCEEDED, INTERNAL and UNKNOWN.
+	FailureCodes []codes.Code
+}
+
+func (c CircuitBreakerConfig) withDefaults() CircuitBreakerConfig {
+	if c.Window <= 0 {
+		c.Window = defaultBreakerWindow
+	}
+	if c.Buckets <= 0 {
+		c.Buckets = defaultBreakerBuckets
+	}
+	if c.MinRequests <= 0 {
+		c.MinRequests = defaultBreakerMinRequests
+	}
+	if c.FailureRatio <= 0 {
+		c.FailureRatio = defaultBreakerFailureRatio
+	}
+	if c.OpenTimeout <= 0 {
+		c.OpenTimeout = defaultBreakerOpenTimeout
+	}
+	if c.HalfOpenRequests <= 0 {
+		c.HalfOpenRequests = defaultBreakerHalfOpenRequests
+	}
+	if len(c.FailureCodes) == 0 {
+		c.FailureCodes = []codes.Code{codes.Unavailable, codes.DeadlineExceeded, codes.Internal, codes.Unknown}
+	}
+	return c
+}
+
+// CircuitBreakers holds circuit breaker per backend host
+type CircuitBreakers struct {
+	config   CircuitBreakerConfig
+	mu       sync.Mutex
+	breakers map[string]*CircuitBreaker
+}
+
+// NewCircuitBreakers creates registry of circuit breakers which share the config
+func NewCircuitBreakers(c CircuitBreakerConfig) *CircuitBreakers {
+	return &CircuitBreakers{
+		config:   c.withDefaults(),
+		breakers: make(map[string]*CircuitBreaker),
+	}
+}
+
+// Get returns circuit breaker of the host, it is created on first access
+func (cb *CircuitBreakers) Get(host string) *CircuitBreaker {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	b, ok := cb.breakers[host]
+	if !ok {
+		b = newCircuitBreaker(host, cb.config)
+		cb.breakers[host] = b
+	}
+	return b
+}
+
+// States returns current state of each backend host
+func (cb *CircuitBreakers) States() map[string]CircuitState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	states := make(map[string]CircuitState, len(cb.breakers))
+	for host, b := range cb.breakers {
+		states[host] = b.State()
+	}
+	return states
+}
+
+type breakerBucket struct {
+	start     time.Time
+	successes int
+	failures  int
+}
+
+// CircuitBreaker tracks failure rate of a backend host over a rolling window
+type CircuitBreaker struct {
+	host   string
+	config CircuitBreakerConfig
+
+	mu               sync.Mutex
+	state            CircuitState
+	openedAt         time.Time
+	buckets          []breakerBucket
+	halfOpenInFlight int
+	halfOpenSuccess  int
+
+	now func() time.Time
+}
+
+func newCircuitBreaker(host string, c CircuitBreakerConfig) *CircuitBreaker {
+	return &CircuitBreaker{
+		host:    host,
+		config:  c,
+		buckets: make([]breakerBucket, c.Buckets),
+		now:     time.Now,
+	}
+}
+
+// State returns current state of circuit breaker
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refreshState()
+	return b.state
+}
+
+// allow reports whether a call can be made.
+// When allowed, returned function must be called with the call result.
+func (b *CircuitBreaker) allow() (func(error), error) {
+	if b == nil {
+		return func(error) {}, nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refreshState()
+	switch b.state {
+	case CircuitOpen:
+		return nil, status.Errorf(codes.Unavailable, "circuit breaker is open for backend %s", b.host)
+	case CircuitHalfOpen:
+		if b.halfOpenInFlight >= b.config.HalfOpenRequests {
+			return nil, status.Errorf(codes.Unavailable, "circuit breaker is half-open for backend %s", b.host)
+		}
+		b.halfOpenInFlight++
+	}
+	state := b.state
+	return func(err error) {
+		b.record(state, err)
+	}, nil
+}
+
+// refreshState moves open breaker to half-open after timeout
+func (b *CircuitBreaker) refreshState() {
+	if b.state == CircuitOpen && b.now().Sub(b.openedAt) >= b.config.OpenTimeout {
+		b.state = CircuitHalfOpen
+		b.halfOpenInFlight = 0
+		b.halfOpenSuccess = 0
+	}
+}
+
+func (b *CircuitBreaker) record(state CircuitState, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	failed := b.isFailure(err)
+	if state == CircuitHalfOpen {
+		// The breaker may have been transitioned by other trial call
+		if b.state != CircuitHalfOpen {
+			return
+		}
+		b.halfOpenInFlight--
+		if failed {
+			b.open()
+			return
+		}
+		b.halfOpenSuccess++
+		if b.halfOpenSuccess >= b.config.HalfOpenRequests {
+			b.close()
+		}
+		return
+	}
+
+	if b.state != CircuitClosed {
+		return
+	}
+	bucket := b.currentBucket()
+	if failed {
+		bucket.failures++
+	} else {
+		bucket.successes++
+	}
+
+	var successes, failures int
+	for _, v := range b.buckets {
+		if b.now().Sub(v.start) < b.config.Window {
+			successes += v.successes
+			failures += v.failures
+		}
+	}
+	total := successes + failures
+	if total >= b.config.MinRequests && float64(failures)/float64(total) >= b.config.FailureRatio {
+		b.open()
+	}
+}
+
+func (b *CircuitBreaker) currentBucket() *breakerBucket {
+	size := b.config.Window / time.Duration(b.config.Buckets)
+	now := b.now()
+	start := now.Truncate(size)
+	bucket := &b.buckets[int(now.UnixNano()/int64(size))%len(b.buckets)]
+	if !bucket.start.Equal(start) {
+		*bucket = breakerBucket{start: start}
+	}
+	return bucket
+}
+
+func (b *CircuitBreaker) open() {
+	b.state = CircuitOpen
+	b.openedAt = b.now()
+}
+
+func (b *CircuitBreaker) close() {
+	b.state = CircuitClosed
+	b.buckets = make([]breakerBucket, b.config.Buckets)
+}
+
+func (b *CircuitBreaker) isFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	code := status.Code(err)
+	if code == codes.Unknown && errors.Is(err, context.DeadlineExceeded) {
+		code = codes.DeadlineExceeded
+	}
+	for _, c := range b.config.FailureCodes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+type circuitBreakersKey struct{}
+
+func withCircuitBreakers(ctx context.Context, cb *CircuitBreakers) context.Context {
+	return context.WithValue(ctx, circuitBreakersKey{}, cb)
+}
+
+func circuitBreakerFromContext(ctx context.Context, host string) *CircuitBreaker {
+	cb, ok := ctx.Value(circuitBreakersKey{}).(*CircuitBreakers)
+	if !ok || cb == nil || host == "" {
+		return nil
+	}
+	return cb.Get(host)
+}
@@ -0,0 +1,152 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time {
+	return c.t
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.t = c.t.Add(d)
+}
+
+func newTestBreakers(clock *fakeClock) *CircuitBreakers {
+	cb := NewCircuitBreakers(CircuitBreakerConfig{
+		MinRequests:  4,
+		FailureRatio: 0.5,
+		OpenTimeout:  time.Second,
+	})
+	b := cb.Get("localhost:50051")
+	b.now = clock.now
+	return cb
+}
+
+func callBreaker(b *CircuitBreaker, err error) error {
+	return invokeWithBreaker(context.Background(), b, func(ctx context.Context) error {
+		return err
+	})
+}
+
+func TestCircuitBreakerOpensOnFailureRate(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(1000, 0)}
+	b := newTestBreakers(clock).Get("localhost:50051")
+
+	unavailable := status.Error(codes.Unavailable, "backend down")
+	assert.NoError(t, callBreaker(b, nil))
+	assert.Error(t, callBreaker(b, unavailable))
+	assert.Error(t, callBreaker(b, unavailable))
+	assert.Equal(t, CircuitClosed, b.State())
+
+	assert.Error(t, callBreaker(b, unavailable))
+	assert.Equal(t, CircuitOpen, b.State())
+
+	var called bool
+	err := invokeWithBreaker(context.Background(), b, func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+	assert.False(t, called)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func TestCircuitBreakerIgnoresNonFailureCodes(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(1000, 0)}
+	b := newTestBreakers(clock).Get("localhost:50051")
+
+	for i := 0; i < 10; i++ {
+		assert.Error(t, callBreaker(b, status.Error(codes.NotFound, "not found")))
+	}
+	assert.Equal(t, CircuitClosed, b.State())
+}
+
+func TestCircuitBreakerForgetsOldFailures(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(1000, 0)}
+	b := newTestBreakers(clock).Get("localhost:50051")
+
+	unavailable := status.Error(codes.Unavailable, "backend down")
+	for i := 0; i < 3; i++ {
+		callBreaker(b, unavailable) // nolint: errcheck
+	}
+	clock.advance(11 * time.Second)
+	callBreaker(b, unavailable) // nolint: errcheck
+	assert.Equal(t, CircuitClosed, b.State())
+}
+
+func TestCircuitBreakerHalfOpen(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(1000, 0)}
+	b := newTestBreakers(clock).Get("localhost:50051")
+
+	unavailable := status.Error(codes.Unavailable, "backend down")
+	for i := 0; i < 4; i++ {
+		callBreaker(b, unavailable) // nolint: errcheck
+	}
+	assert.Equal(t, CircuitOpen, b.State())
+
+	// Failed trial call opens breaker again
+	clock.advance(time.Second)
+	assert.Equal(t, CircuitHalfOpen, b.State())
+	assert.Equal(t, codes.Unavailable, status.Code(callBreaker(b, unavailable)))
+	assert.Equal(t, CircuitOpen, b.State())
+
+	// Successful trial call closes breaker
+	clock.advance(time.Second)
+	assert.Equal(t, CircuitHalfOpen, b.State())
+	assert.NoError(t, callBreaker(b, nil))
+	assert.Equal(t, CircuitClosed, b.State())
+}
+
+func TestCircuitBreakerLimitsHalfOpenCalls(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(1000, 0)}
+	b := newTestBreakers(clock).Get("localhost:50051")
+
+	unavailable := status.Error(codes.Unavailable, "backend down")
+	for i := 0; i < 4; i++ {
+		callBreaker(b, unavailable) // nolint: errcheck
+	}
+	clock.advance(time.Second)
+
+	done, err := b.allow()
+	assert.NoError(t, err)
+	_, err = b.allow()
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	done(nil)
+	assert.Equal(t, CircuitClosed, b.State())
+}
+
+func TestInvokeFailsFastPerHost(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(1000, 0)}
+	cb := newTestBreakers(clock)
+	ctx := withCircuitBreakers(context.Background(), cb)
+
+	var calls int
+	for i := 0; i < 4; i++ {
+		Invoke(ctx, "localhost:50051", nil, failingCall(&calls, 10, codes.Unavailable)) // nolint: errcheck
+	}
+	assert.Equal(t, 4, calls)
+
+	err := Invoke(ctx, "localhost:50051", nil, failingCall(&calls, 10, codes.Unavailable))
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Equal(t, 4, calls)
+
+	calls = 0
+	err = Invoke(ctx, "localhost:50052", nil, failingCall(&calls, 0, codes.Unavailable))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, calls)
+
+	assert.Equal(t, map[string]CircuitState{
+		"localhost:50051": CircuitOpen,
+		"localhost:50052": CircuitClosed,
+	}, cb.States())
+}
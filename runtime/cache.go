@@ -0,0 +1,120 @@
+package runtime
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"sync"
+	"sync/atomic"
+
+	"github.com/wundergraph/graphql-go-tools/pkg/ast"
+)
+
+// DocumentCache keeps normalized and validated operations of recently requested queries,
+// so that the same query text is parsed, normalized and validated only once per schema version.
+// Least recently used operation is evicted when the cache is full.
+type DocumentCache struct {
+	size int
+
+	mu      sync.Mutex
+	entries map[documentKey]*list.Element
+	recency *list.List
+
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	evictions atomic.Uint64
+}
+
+// documentKey identifies operation by query hash, operation name and the version of schema it's validated against
+type documentKey struct {
+	hash          [sha256.Size]byte
+	operationName string
+	version       uint64
+}
+
+type documentEntry struct {
+	key       documentKey
+	operation *ast.Document
+}
+
+// DocumentCacheStats is a snapshot of cache metrics
+type DocumentCacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	Entries   int
+}
+
+// NewDocumentCache creates cache which holds operations up to size
+func NewDocumentCache(size int) *DocumentCache {
+	if size < 1 {
+		size = 1
+	}
+	return &DocumentCache{
+		size:    size,
+		entries: make(map[documentKey]*list.Element, size),
+		recency: list.New(),
+	}
+}
+
+// Stats returns hit/miss counts since the cache is created and current number of entries
+func (c *DocumentCache) Stats() DocumentCacheStats {
+	c.mu.Lock()
+	entries := c.recency.Len()
+	c.mu.Unlock()
+
+	return DocumentCacheStats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Evictions: c.evictions.Load(),
+		Entries:   entries,
+	}
+}
+
+// Purge removes all entries, metrics are kept
+func (c *DocumentCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[documentKey]*list.Element, c.size)
+	c.recency.Init()
+}
+
+func newDocumentKey(query, operationName string, version uint64) documentKey {
+	return documentKey{
+		hash:          sha256.Sum256([]byte(query)),
+		operationName: operationName,
+		version:       version,
+	}
+}
+
+// get returns cached operation. The operation is shared by requests and must not be modified.
+func (c *DocumentCache) get(key documentKey) (*ast.Document, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		c.misses.Add(1)
+		return nil, false
+	}
+	c.hits.Add(1)
+	c.recency.MoveToFront(e)
+	return e.Value.(*documentEntry).operation, true // nolint: errcheck
+}
+
+func (c *DocumentCache) add(key documentKey, operation *ast.Document) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.entries[key]; ok {
+		c.recency.MoveToFront(e)
+		return
+	}
+	c.entries[key] = c.recency.PushFront(&documentEntry{key: key, operation: operation})
+	for c.recency.Len() > c.size {
+		oldest := c.recency.Back()
+		c.recency.Remove(oldest)
+		delete(c.entries, oldest.Value.(*documentEntry).key) // nolint: errcheck
+		c.evictions.Add(1)
+	}
+}
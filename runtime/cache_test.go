@@ -0,0 +1,62 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wundergraph/graphql-go-tools/pkg/ast"
+)
+
+func TestDocumentCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewDocumentCache(2)
+	a, b, c := newDocumentKey("{ a }", "", 1), newDocumentKey("{ b }", "", 1), newDocumentKey("{ c }", "", 1)
+	cache.add(a, &ast.Document{})
+	cache.add(b, &ast.Document{})
+	_, ok := cache.get(a)
+	assert.True(t, ok)
+
+	cache.add(c, &ast.Document{})
+	_, ok = cache.get(b)
+	assert.False(t, ok)
+	_, ok = cache.get(a)
+	assert.True(t, ok)
+	_, ok = cache.get(newDocumentKey("{ a }", "", 2))
+	assert.False(t, ok)
+
+	assert.Equal(t, DocumentCacheStats{Hits: 2, Misses: 2, Evictions: 1, Entries: 2}, cache.Stats())
+}
+
+func TestServeMuxDocumentCache(t *testing.T) {
+	mux := NewServeMux()
+	mux.DocumentCache = NewDocumentCache(10)
+	assert.NoError(t, mux.AddHandler(newVersionHandler("v1")))
+
+	assert.Equal(t, `{"data":{"version":"v1"}}`, serveTestQuery(mux, "{ version }"))
+	assert.Equal(t, `{"data":{"version":"v1"}}`, serveTestQuery(mux, "{ version }"))
+	assert.Equal(t, DocumentCacheStats{Hits: 1, Misses: 1, Entries: 1}, mux.DocumentCache.Stats())
+
+	// invalid operation is not cached
+	serveTestQuery(mux, "{ unknown }")
+	assert.Equal(t, 1, mux.DocumentCache.Stats().Entries)
+
+	// reload invalidates operations which are validated against previous schema
+	assert.NoError(t, mux.ReplaceHandlers(newVersionHandler("v2")))
+	assert.Equal(t, 0, mux.DocumentCache.Stats().Entries)
+	assert.Equal(t, `{"data":{"version":"v2"}}`, serveTestQuery(mux, "{ version }"))
+
+	assert.NoError(t, mux.DisableValidationRules("FieldSelections"))
+	assert.Equal(t, 0, mux.DocumentCache.Stats().Entries)
+}
+
+func TestServeMuxDocumentCacheSkipsReplacedState(t *testing.T) {
+	mux := NewServeMux()
+	mux.DocumentCache = NewDocumentCache(10)
+	assert.NoError(t, mux.AddHandler(newVersionHandler("v1")))
+
+	// the state is replaced while the operation is validated against it
+	state := mux.state.Load()
+	assert.NoError(t, mux.ReplaceHandlers(newVersionHandler("v2")))
+	_, errs := mux.prepareOperation(state, &GraphqlRequest{Query: "{ version }"})
+	assert.Empty(t, errs)
+	assert.Equal(t, 0, mux.DocumentCache.Stats().Entries)
+}
@@ -0,0 +1,748 @@
+package runtime
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/wundergraph/graphql-go-tools/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/pkg/graphqlerrors"
+)
+
+// coerceVariables validates variable values against variable definitions of the operation and applies default values.
+// All invalid variables are reported with location of their definitions.
+func (e *executor) coerceVariables(operation int, variables map[string]interface{}) (map[string]interface{}, []GraphqlError) {
+	op := e.operation
+	coerced := make(map[string]interface{})
+	var errs []GraphqlError
+	for _, ref := range op.OperationDefinitions[operation].VariableDefinitions.Refs {
+		definition := op.VariableDefinitions[ref]
+		name := op.VariableDefinitionNameString(ref)
+
+		v, ok, err := e.coerceVariable(definition, name, variables)
+		if err != nil {
+			pos := definition.VariableValue.Position
+			errs = append(errs, GraphqlError{
+				Message: err.Error(),
+				Locations: []graphqlerrors.Location{{
+					Line:   pos.LineStart,
+					Column: pos.CharStart,
+				}},
+			})
+			continue
+		}
+		if ok {
+			coerced[name] = v
+		}
+	}
+	return coerced, errs
+}
+
+// coerceVariable coerces a variable value, or default value of the definition when it's not provided.
+// It also returns whether the variable has a value, which is distinguished from explicit null.
+func (e *executor) coerceVariable(definition ast.VariableDefinition, name string, variables map[string]interface{}) (interface{}, bool, error) {
+	op := e.operation
+	if !e.isInputType(op.ResolveTypeNameString(definition.Type)) {
+		return nil, false, fmt.Errorf("Variable \"$%s\" expected value of type \"%s\" which cannot be used as an input type", name, typeString(op, definition.Type))
+	}
+	value, ok := variables[name]
+	if !ok {
+		if definition.DefaultValue.IsDefined {
+			v, err := e.coerceLiteral(op, definition.Type, definition.DefaultValue.Value)
+			if err != nil {
+				return nil, false, fmt.Errorf("Variable \"$%s\" has invalid default value%s", name, inputErrorString(name, err))
+			}
+			return v, true, nil
+		}
+		if op.TypeIsNonNull(definition.Type) {
+			return nil, false, fmt.Errorf("Variable \"$%s\" of required type \"%s\" was not provided", name, typeString(op, definition.Type))
+		}
+		return nil, false, nil
+	}
+	if value == nil && op.TypeIsNonNull(definition.Type) {
+		return nil, false, fmt.Errorf("Variable \"$%s\" of non-null type \"%s\" must not be null", name, typeString(op, definition.Type))
+	}
+
+	v, err := e.coerceInput(op, definition.Type, value)
+	if err != nil {
+		return nil, false, fmt.Errorf("Variable \"$%s\" got invalid value%s", name, inputErrorString(name, err))
+	}
+	return v, true, nil
+}
+
+// isInputType reports whether named type is a scalar, enum or input object type of the schema
+func (e *executor) isInputType(typeName string) bool {
+	node, ok := e.schema.Document.Index.FirstNodeByNameStr(typeName)
+	if !ok {
+		return false
+	}
+	switch node.Kind {
+	case ast.NodeKindScalarTypeDefinition, ast.NodeKindEnumTypeDefinition, ast.NodeKindInputObjectTypeDefinition:
+		return true
+	default:
+		return false
+	}
+}
+
+// coerceArguments builds argument values of the field from literals and variables in the operation
+func (e *executor) coerceArguments(fieldDefinition, field int) (map[string]interface{}, error) {
+	op, definition := e.operation, e.schema.Document
+	args := make(map[string]interface{})
+	for _, ref := range definition.FieldDefinitions[fieldDefinition].ArgumentsDefinition.Refs {
+		name := definition.InputValueDefinitionNameString(ref)
+		argType := definition.InputValueDefinitionType(ref)
+		defaultValue := definition.InputValueDefinitions[ref].DefaultValue
+
+		var value ast.Value
+		argument, ok := op.FieldArgument(field, []byte(name))
+		if ok {
+			value = op.ArgumentValue(argument)
+			if value.Kind == ast.ValueKindVariable {
+				if _, provided := e.variables[op.VariableValueNameString(value.Ref)]; !provided {
+					ok = false
+				}
+			}
+		}
+
+		if !ok {
+			if defaultValue.IsDefined {
+				v, err := e.coerceLiteral(definition, argType, defaultValue.Value)
+				if err != nil {
+					return nil, fmt.Errorf("Argument \"%s\" has invalid default value%s", name, inputErrorString(name, err))
+				}
+				args[name] = v
+				continue
+			}
+			if definition.TypeIsNonNull(argType) {
+				return nil, fmt.Errorf("Argument \"%s\" of required type \"%s\" was not provided", name, typeString(definition, argType))
+			}
+			continue
+		}
+
+		v, err := e.coerceLiteralTo(definition, argType, op, value)
+		if err != nil {
+			return nil, fmt.Errorf("Argument \"%s\" has invalid value%s", name, inputErrorString(name, err))
+		}
+		args[name] = v
+	}
+	return args, nil
+}
+
+// providedFields collects dotted names of input fields which are provided under the argument path of the field.
+// Nested input objects are expanded to their fields, and fields which refer variables not provided are excluded.
+func (e *executor) providedFields(fieldDefinition, field int, argument string) []string {
+	op, definition := e.operation, e.schema.Document
+	refs := definition.FieldDefinitions[fieldDefinition].ArgumentsDefinition.Refs
+	values := make(map[string]interface{})
+	for _, ref := range refs {
+		name := definition.InputValueDefinitionNameString(ref)
+		if argument, ok := op.FieldArgument(field, []byte(name)); ok {
+			if v, ok := e.providedValue(op, op.ArgumentValue(argument)); ok {
+				values[name] = v
+			}
+		}
+	}
+
+	if argument != "" {
+		for _, name := range strings.Split(argument, ".") {
+			ref, ok := inputValueDefinitionByName(definition, refs, name)
+			if !ok {
+				return nil
+			}
+			v, ok := values[name].(map[string]interface{})
+			if !ok {
+				return nil
+			}
+			if refs, ok = e.inputFieldRefs(definition.InputValueDefinitionType(ref)); !ok {
+				return nil
+			}
+			values = v
+		}
+	}
+
+	var paths []string
+	var walk func(refs []int, values map[string]interface{}, prefix string)
+	walk = func(refs []int, values map[string]interface{}, prefix string) {
+		for _, ref := range refs {
+			name := definition.InputValueDefinitionNameString(ref)
+			v, ok := values[name]
+			if !ok {
+				continue
+			}
+			if object, ok := v.(map[string]interface{}); ok {
+				if fieldRefs, ok := e.inputFieldRefs(definition.InputValueDefinitionType(ref)); ok {
+					walk(fieldRefs, object, prefix+name+".")
+					continue
+				}
+			}
+			paths = append(paths, prefix+name)
+		}
+	}
+	walk(refs, values, "")
+	return paths
+}
+
+// providedValue converts value literal to Go value like literalValue,
+// but object fields which refer variables not provided are omitted and variables keep values which the client sends.
+// It returns false if the value itself is not provided.
+func (e *executor) providedValue(document *ast.Document, value ast.Value) (interface{}, bool) {
+	switch value.Kind {
+	case ast.ValueKindVariable:
+		name := document.VariableValueNameString(value.Ref)
+		if v, ok := e.rawVariables[name]; ok {
+			return v, true
+		}
+		// default value of the variable is given in the operation
+		v, ok := e.variables[name]
+		return v, ok
+	case ast.ValueKindObject:
+		object := make(map[string]interface{})
+		for _, ref := range document.ObjectValues[value.Ref].Refs {
+			if v, ok := e.providedValue(document, document.ObjectFieldValue(ref)); ok {
+				object[document.ObjectFieldNameString(ref)] = v
+			}
+		}
+		return object, true
+	default:
+		v, err := e.literalValue(document, value)
+		return v, err == nil
+	}
+}
+
+// inputFieldRefs returns input value definitions of the input object type, or false for other types and lists
+func (e *executor) inputFieldRefs(typeRef int) ([]int, bool) {
+	definition := e.schema.Document
+	if definition.TypeIsNonNull(typeRef) {
+		typeRef = definition.Types[typeRef].OfType
+	}
+	if definition.Types[typeRef].TypeKind != ast.TypeKindNamed {
+		return nil, false
+	}
+	node, ok := definition.Index.FirstNodeByNameStr(definition.TypeNameString(typeRef))
+	if !ok || node.Kind != ast.NodeKindInputObjectTypeDefinition {
+		return nil, false
+	}
+	return definition.InputObjectTypeDefinitions[node.Ref].InputFieldsDefinition.Refs, true
+}
+
+// inputValueDefinitionByName finds argument or input field by name
+func inputValueDefinitionByName(definition *ast.Document, refs []int, name string) (int, bool) {
+	for _, ref := range refs {
+		if definition.InputValueDefinitionNameString(ref) == name {
+			return ref, true
+		}
+	}
+	return 0, false
+}
+
+// coerceLiteral coerces value literal which is written in the same document as its type
+func (e *executor) coerceLiteral(document *ast.Document, typeRef int, value ast.Value) (interface{}, error) {
+	return e.coerceLiteralTo(document, typeRef, document, value)
+}
+
+// coerceLiteralTo coerces value literal in valueDocument to the type in typeDocument
+func (e *executor) coerceLiteralTo(typeDocument *ast.Document, typeRef int, valueDocument *ast.Document, value ast.Value) (interface{}, error) {
+	t := typeDocument.Types[typeRef]
+
+	if value.Kind == ast.ValueKindVariable {
+		v := e.variables[valueDocument.VariableValueNameString(value.Ref)]
+		if v == nil && t.TypeKind == ast.TypeKindNonNull {
+			return nil, fmt.Errorf("Expected non-null value of type \"%s\"", typeString(typeDocument, typeRef))
+		}
+		return v, nil
+	}
+
+	switch t.TypeKind {
+	case ast.TypeKindNonNull:
+		if value.Kind == ast.ValueKindNull {
+			return nil, fmt.Errorf("Expected non-null value of type \"%s\"", typeString(typeDocument, typeRef))
+		}
+		return e.coerceLiteralTo(typeDocument, t.OfType, valueDocument, value)
+	case ast.TypeKindList:
+		if value.Kind == ast.ValueKindNull {
+			return nil, nil
+		}
+		if value.Kind != ast.ValueKindList {
+			// A single value is treated as a list of one item
+			v, err := e.coerceLiteralTo(typeDocument, t.OfType, valueDocument, value)
+			if err != nil {
+				return nil, err
+			}
+			return []interface{}{v}, nil
+		}
+		refs := valueDocument.ListValues[value.Ref].Refs
+		list := make([]interface{}, len(refs))
+		for i, ref := range refs {
+			v, err := e.coerceLiteralTo(typeDocument, t.OfType, valueDocument, valueDocument.Value(ref))
+			if err != nil {
+				return nil, withInputPath(err, i)
+			}
+			list[i] = v
+		}
+		return list, nil
+	}
+
+	if value.Kind == ast.ValueKindNull {
+		return nil, nil
+	}
+
+	typeName := typeDocument.TypeNameString(typeRef)
+	node, ok := e.schema.Document.Index.FirstNodeByNameStr(typeName)
+	if !ok {
+		return nil, fmt.Errorf("Unknown type \"%s\"", typeName)
+	}
+
+	switch node.Kind {
+	case ast.NodeKindEnumTypeDefinition:
+		if value.Kind != ast.ValueKindEnum {
+			return nil, fmt.Errorf("Enum \"%s\" cannot represent non-enum value", typeName)
+		}
+		return e.parseEnum(node, valueDocument.EnumValueNameString(value.Ref))
+	case ast.NodeKindInputObjectTypeDefinition:
+		if value.Kind != ast.ValueKindObject {
+			return nil, fmt.Errorf("Expected value of type \"%s\" to be an object", typeName)
+		}
+		fields := make(map[string]ast.Value)
+		for _, ref := range valueDocument.ObjectValues[value.Ref].Refs {
+			fields[valueDocument.ObjectFieldNameString(ref)] = valueDocument.ObjectFieldValue(ref)
+		}
+		return e.coerceInputObject(node, func(name string) (interface{}, bool, error) {
+			v, ok := fields[name]
+			if !ok {
+				return nil, false, nil
+			}
+			delete(fields, name)
+			if v.Kind == ast.ValueKindVariable {
+				if _, provided := e.variables[valueDocument.VariableValueNameString(v.Ref)]; !provided {
+					return nil, false, nil
+				}
+			}
+			ref := e.schema.Document.InputObjectTypeDefinitionInputValueDefinitionByName(node.Ref, []byte(name))
+			coerced, err := e.coerceLiteralTo(e.schema.Document, e.schema.Document.InputValueDefinitionType(ref), valueDocument, v)
+			return coerced, true, err
+		}, func() []string {
+			names := make([]string, 0, len(fields))
+			for name := range fields {
+				names = append(names, name)
+			}
+			return names
+		})
+	case ast.NodeKindScalarTypeDefinition:
+		var v interface{}
+		switch value.Kind {
+		case ast.ValueKindString:
+			v = valueDocument.StringValueContentString(value.Ref)
+		case ast.ValueKindBoolean:
+			v = bool(valueDocument.BooleanValue(value.Ref))
+		case ast.ValueKindInteger:
+			v = valueDocument.IntValueAsInt(value.Ref)
+		case ast.ValueKindFloat:
+			f, err := strconv.ParseFloat(string(valueDocument.FloatValueRaw(value.Ref)), 64)
+			if err != nil {
+				return nil, err
+			}
+			v = f
+		default:
+			if isBuiltInScalar(typeName) {
+				return nil, fmt.Errorf("%s cannot represent non-scalar value", typeName)
+			}
+			// Custom scalars like _Any of federation accept object and list literals
+			var err error
+			if v, err = e.literalValue(valueDocument, value); err != nil {
+				return nil, err
+			}
+		}
+		return parseScalar(typeName, v)
+	default:
+		return nil, fmt.Errorf("Type \"%s\" is not an input type", typeName)
+	}
+}
+
+// coerceInput coerces JSON value of variable to the type in document
+func (e *executor) coerceInput(document *ast.Document, typeRef int, value interface{}) (interface{}, error) {
+	t := document.Types[typeRef]
+
+	switch t.TypeKind {
+	case ast.TypeKindNonNull:
+		if value == nil {
+			return nil, fmt.Errorf("Expected non-null value of type \"%s\"", typeString(document, typeRef))
+		}
+		return e.coerceInput(document, t.OfType, value)
+	case ast.TypeKindList:
+		if value == nil {
+			return nil, nil
+		}
+		items, ok := value.([]interface{})
+		if !ok {
+			v, err := e.coerceInput(document, t.OfType, value)
+			if err != nil {
+				return nil, err
+			}
+			return []interface{}{v}, nil
+		}
+		list := make([]interface{}, len(items))
+		for i, item := range items {
+			v, err := e.coerceInput(document, t.OfType, item)
+			if err != nil {
+				return nil, withInputPath(err, i)
+			}
+			list[i] = v
+		}
+		return list, nil
+	}
+
+	if value == nil {
+		return nil, nil
+	}
+
+	typeName := document.TypeNameString(typeRef)
+	node, ok := e.schema.Document.Index.FirstNodeByNameStr(typeName)
+	if !ok {
+		return nil, fmt.Errorf("Unknown type \"%s\"", typeName)
+	}
+
+	switch node.Kind {
+	case ast.NodeKindEnumTypeDefinition:
+		name, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("Enum \"%s\" cannot represent non-string value: %v", typeName, value)
+		}
+		return e.parseEnum(node, name)
+	case ast.NodeKindInputObjectTypeDefinition:
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Expected value of type \"%s\" to be an object", typeName)
+		}
+		visited := make(map[string]struct{})
+		return e.coerceInputObject(node, func(name string) (interface{}, bool, error) {
+			v, ok := fields[name]
+			if !ok {
+				return nil, false, nil
+			}
+			visited[name] = struct{}{}
+			ref := e.schema.Document.InputObjectTypeDefinitionInputValueDefinitionByName(node.Ref, []byte(name))
+			coerced, err := e.coerceInput(e.schema.Document, e.schema.Document.InputValueDefinitionType(ref), v)
+			return coerced, true, err
+		}, func() []string {
+			var names []string
+			for name := range fields {
+				if _, ok := visited[name]; !ok {
+					names = append(names, name)
+				}
+			}
+			return names
+		})
+	case ast.NodeKindScalarTypeDefinition:
+		return parseScalar(typeName, value)
+	default:
+		return nil, fmt.Errorf("Type \"%s\" is not an input type", typeName)
+	}
+}
+
+// coerceInputObject builds input object value.
+// lookup returns coerced field value and whether it's provided, and unknown returns names of fields which are not defined in the type.
+func (e *executor) coerceInputObject(
+	node ast.Node,
+	lookup func(name string) (interface{}, bool, error),
+	unknown func() []string,
+) (map[string]interface{}, error) {
+	definition := e.schema.Document
+	typeName := definition.NodeNameString(node)
+	result := make(map[string]interface{})
+	for _, ref := range definition.InputObjectTypeDefinitions[node.Ref].InputFieldsDefinition.Refs {
+		name := definition.InputValueDefinitionNameString(ref)
+		fieldType := definition.InputValueDefinitionType(ref)
+
+		v, ok, err := lookup(name)
+		if err != nil {
+			return nil, withInputPath(err, name)
+		}
+		if ok {
+			result[name] = v
+			continue
+		}
+		if defaultValue := definition.InputValueDefinitions[ref].DefaultValue; defaultValue.IsDefined {
+			if result[name], err = e.coerceLiteral(definition, fieldType, defaultValue.Value); err != nil {
+				return nil, withInputPath(err, name)
+			}
+			continue
+		}
+		if definition.TypeIsNonNull(fieldType) {
+			return nil, fmt.Errorf("Field \"%s.%s\" of required type \"%s\" was not provided", typeName, name, typeString(definition, fieldType))
+		}
+	}
+	if names := unknown(); len(names) > 0 {
+		return nil, fmt.Errorf("Field \"%s\" is not defined by type \"%s\"", names[0], typeName)
+	}
+	return result, nil
+}
+
+// parseEnum converts enum value name to internal value which is registered to the schema
+func (e *executor) parseEnum(node ast.Node, name string) (interface{}, error) {
+	definition := e.schema.Document
+	typeName := definition.NodeNameString(node)
+	if !definition.EnumTypeDefinitionContainsEnumValue(node.Ref, []byte(name)) {
+		return nil, fmt.Errorf("Value \"%s\" does not exist in \"%s\" enum", name, typeName)
+	}
+	if enum, ok := e.schema.enums[typeName]; ok {
+		if v, ok := enum.Values[name]; ok {
+			return v, nil
+		}
+	}
+	return name, nil
+}
+
+// literalValue converts value literal to Go value without type, variables in it are replaced with their values
+func (e *executor) literalValue(document *ast.Document, value ast.Value) (interface{}, error) {
+	switch value.Kind {
+	case ast.ValueKindNull:
+		return nil, nil
+	case ast.ValueKindVariable:
+		return e.variables[document.VariableValueNameString(value.Ref)], nil
+	case ast.ValueKindString:
+		return document.StringValueContentString(value.Ref), nil
+	case ast.ValueKindBoolean:
+		return bool(document.BooleanValue(value.Ref)), nil
+	case ast.ValueKindInteger:
+		return document.IntValueAsInt(value.Ref), nil
+	case ast.ValueKindFloat:
+		return strconv.ParseFloat(string(document.FloatValueRaw(value.Ref)), 64)
+	case ast.ValueKindEnum:
+		return document.EnumValueNameString(value.Ref), nil
+	case ast.ValueKindList:
+		refs := document.ListValues[value.Ref].Refs
+		list := make([]interface{}, len(refs))
+		for i, ref := range refs {
+			v, err := e.literalValue(document, document.Value(ref))
+			if err != nil {
+				return nil, err
+			}
+			list[i] = v
+		}
+		return list, nil
+	case ast.ValueKindObject:
+		object := make(map[string]interface{})
+		for _, ref := range document.ObjectValues[value.Ref].Refs {
+			v, err := e.literalValue(document, document.ObjectFieldValue(ref))
+			if err != nil {
+				return nil, err
+			}
+			object[document.ObjectFieldNameString(ref)] = v
+		}
+		return object, nil
+	default:
+		return nil, fmt.Errorf("Unsupported value literal kind %s", value.Kind)
+	}
+}
+
+// inputPathError is an error of nested input value at path, which consists of field names and list indices
+type inputPathError struct {
+	path []interface{}
+	err  error
+}
+
+func (e *inputPathError) Error() string {
+	return e.err.Error()
+}
+
+// withInputPath prepends field name or list index to path of the error
+func withInputPath(err error, key interface{}) error {
+	var pe *inputPathError
+	if errors.As(err, &pe) {
+		pe.path = append([]interface{}{key}, pe.path...)
+		return pe
+	}
+	return &inputPathError{path: []interface{}{key}, err: err}
+}
+
+// inputErrorString describes the error of input value with its path from name, e.g. ` at "input.items[1]"; reason`
+func inputErrorString(name string, err error) string {
+	var pe *inputPathError
+	if !errors.As(err, &pe) {
+		return "; " + err.Error()
+	}
+	b := new(strings.Builder)
+	b.WriteString(name)
+	for _, key := range pe.path {
+		if i, ok := key.(int); ok {
+			fmt.Fprintf(b, "[%d]", i)
+		} else {
+			fmt.Fprintf(b, ".%s", key)
+		}
+	}
+	return fmt.Sprintf(" at \"%s\"; %s", b, pe.err)
+}
+
+func isBuiltInScalar(typeName string) bool {
+	switch typeName {
+	case "Int", "Float", "String", "Boolean", "ID":
+		return true
+	default:
+		return false
+	}
+}
+
+// parseScalar coerces input value of built-in and well-known scalar types.
+// Other custom scalars are passed through as they are.
+func parseScalar(typeName string, value interface{}) (interface{}, error) {
+	switch typeName {
+	case "Int":
+		n, ok := toFloat(value)
+		if !ok || n != math.Trunc(n) || n > math.MaxInt32 || n < math.MinInt32 {
+			return nil, fmt.Errorf("Int cannot represent non-integer value: %v", value)
+		}
+		return int(n), nil
+	case "Float":
+		n, ok := toFloat(value)
+		if !ok {
+			return nil, fmt.Errorf("Float cannot represent non numeric value: %v", value)
+		}
+		return n, nil
+	case "String":
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("String cannot represent a non string value: %v", value)
+		}
+		return s, nil
+	case "Boolean":
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("Boolean cannot represent a non boolean value: %v", value)
+		}
+		return b, nil
+	case "ID":
+		switch v := value.(type) {
+		case string:
+			return v, nil
+		case int64:
+			return strconv.FormatInt(v, 10), nil
+		}
+		if n, ok := toFloat(value); ok && n == math.Trunc(n) {
+			return strconv.FormatInt(int64(n), 10), nil
+		}
+		return nil, fmt.Errorf("ID cannot represent value: %v", value)
+	default:
+		if c, ok := wellKnownScalars[typeName]; ok {
+			return c.parse(value)
+		}
+		return value, nil
+	}
+}
+
+// serializeScalar converts resolved value to response value of built-in and well-known scalar types.
+// Other custom scalars are passed through as they are.
+func serializeScalar(typeName string, value interface{}) (interface{}, error) {
+	value = unwrapValue(value)
+	v := derefValue(reflect.ValueOf(value))
+	switch typeName {
+	case "Int":
+		// Int is 32-bit, e.g. uint32 field beyond 2^31-1 raises field error instead of exceeding it
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if n := v.Int(); n >= math.MinInt32 && n <= math.MaxInt32 {
+				return n, nil
+			}
+			return nil, fmt.Errorf("Int cannot represent non 32-bit signed integer value: %v", value)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if n := v.Uint(); n <= math.MaxInt32 {
+				return int64(n), nil
+			}
+			return nil, fmt.Errorf("Int cannot represent non 32-bit signed integer value: %v", value)
+		case reflect.Bool:
+			if v.Bool() {
+				return 1, nil
+			}
+			return 0, nil
+		}
+		if f, ok := toFloat(v.Interface()); ok && f == math.Trunc(f) {
+			if f >= math.MinInt32 && f <= math.MaxInt32 {
+				return int64(f), nil
+			}
+			return nil, fmt.Errorf("Int cannot represent non 32-bit signed integer value: %v", value)
+		}
+		return nil, fmt.Errorf("Int cannot represent non-integer value: %v", value)
+	case "Float":
+		if n, ok := toFloat(v.Interface()); ok {
+			return n, nil
+		}
+		return nil, fmt.Errorf("Float cannot represent non numeric value: %v", value)
+	case "String", "ID":
+		switch v.Kind() {
+		case reflect.String:
+			return v.String(), nil
+		case reflect.Slice:
+			if b, ok := v.Interface().([]byte); ok {
+				return string(b), nil
+			}
+		case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			return fmt.Sprint(v.Interface()), nil
+		}
+		return nil, fmt.Errorf("%s cannot represent value: %v", typeName, value)
+	case "Boolean":
+		if v.Kind() == reflect.Bool {
+			return v.Bool(), nil
+		}
+		return nil, fmt.Errorf("Boolean cannot represent a non boolean value: %v", value)
+	default:
+		if c, ok := wellKnownScalars[typeName]; ok {
+			return c.serialize(value)
+		}
+		return value, nil
+	}
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+// enumValueEqual compares internal enum values.
+// Numbers are compared as int64 in order to accept both generated enum types and plain integers.
+func enumValueEqual(a, b interface{}) bool {
+	if a == b {
+		return true
+	}
+	ra, rb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if isInteger(ra) && isInteger(rb) {
+		return ra.Int() == rb.Int()
+	}
+	return false
+}
+
+func isInteger(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	default:
+		return false
+	}
+}
+
+// typeString prints type reference like "[String!]!"
+func typeString(document *ast.Document, typeRef int) string {
+	b, err := document.PrintTypeBytes(typeRef, nil)
+	if err != nil {
+		return document.ResolveTypeNameString(typeRef)
+	}
+	return string(b)
+}
@@ -0,0 +1,105 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func executeCoerceTestQuery(t *testing.T, query string, variables map[string]interface{}) (interface{}, []GraphqlError) {
+	var args interface{}
+	schema, err := buildSchema([]GraphqlHandler{&testHandler{
+		types: Types{
+			Definitions: []string{
+				"input Filter {\n  colors: [Color!]\n  names: [String!]!\n  page: Page\n}",
+				"input Page {\n  size: Int = 10\n  token: String\n}",
+				"enum Color {\n  RED\n  BLUE\n}",
+			},
+			Enums: []*Enum{
+				{Name: "Color", Definition: "enum Color {\n  RED\n  BLUE\n}", Values: map[string]interface{}{"RED": 1, "BLUE": 2}},
+			},
+		},
+		queries: Fields{
+			"search": &Field{
+				Args: "filter: Filter!",
+				Type: "Boolean",
+				Resolve: func(p ResolveParams) (interface{}, error) {
+					args = p.Args["filter"]
+					return true, nil
+				},
+			},
+		},
+	}}, false)
+	assert.NoError(t, err)
+	operation, errs := parseOperation(query, schema.Document)
+	assert.Empty(t, errs)
+	_, errs = ExecuteGraphQL(context.Background(), schema, operation, "", variables)
+	return args, errs
+}
+
+func TestCoerceVariables(t *testing.T) {
+	args, errs := executeCoerceTestQuery(t, `query ($filter: Filter!) { search(filter: $filter) }`, map[string]interface{}{
+		"filter": map[string]interface{}{
+			"colors": []interface{}{"BLUE"},
+			"names":  "alice",
+			"page":   map[string]interface{}{},
+		},
+	})
+	assert.Empty(t, errs)
+	assert.Equal(t, map[string]interface{}{
+		"colors": []interface{}{2},
+		"names":  []interface{}{"alice"},
+		"page":   map[string]interface{}{"size": 10},
+	}, args)
+
+	args, errs = executeCoerceTestQuery(t, `query ($size: Int = 5) { search(filter: {names: [], page: {size: $size}}) }`, nil)
+	assert.Empty(t, errs)
+	assert.Equal(t, map[string]interface{}{"names": []interface{}{}, "page": map[string]interface{}{"size": 5}}, args)
+}
+
+func TestCoerceVariablesReportsPath(t *testing.T) {
+	_, errs := executeCoerceTestQuery(t, `query ($filter: Filter!, $color: Color!) { search(filter: $filter) }`, map[string]interface{}{
+		"filter": map[string]interface{}{
+			"colors": []interface{}{"RED", "GREEN"},
+			"names":  []interface{}{"alice"},
+		},
+		"color": nil,
+	})
+	assert.Equal(t, []GraphqlError{
+		{
+			Message:   `Variable "$filter" got invalid value at "filter.colors[1]"; Value "GREEN" does not exist in "Color" enum`,
+			Locations: errs[0].Locations,
+		},
+		{
+			Message:   `Variable "$color" of non-null type "Color!" must not be null`,
+			Locations: errs[1].Locations,
+		},
+	}, errs)
+	assert.Equal(t, 1, int(errs[0].Locations[0].Line))
+	assert.Equal(t, 8, int(errs[0].Locations[0].Column))
+
+	_, errs = executeCoerceTestQuery(t, `query ($filter: Filter!) { search(filter: $filter) }`, map[string]interface{}{
+		"filter": map[string]interface{}{"names": []interface{}{"alice"}, "page": map[string]interface{}{"size": "ten"}},
+	})
+	assert.Len(t, errs, 1)
+	assert.Equal(t, `Variable "$filter" got invalid value at "filter.page.size"; Int cannot represent non-integer value: ten`, errs[0].Message)
+
+	_, errs = executeCoerceTestQuery(t, `query ($filter: Filter!) { search(filter: $filter) }`, map[string]interface{}{
+		"filter": map[string]interface{}{"names": []interface{}{"alice"}, "unknown": 1},
+	})
+	assert.Len(t, errs, 1)
+	assert.Equal(t, `Variable "$filter" got invalid value; Field "unknown" is not defined by type "Filter"`, errs[0].Message)
+
+	_, errs = executeCoerceTestQuery(t, `query ($filter: Filter!) { search(filter: $filter) }`, map[string]interface{}{
+		"filter": map[string]interface{}{"page": map[string]interface{}{}},
+	})
+	assert.Len(t, errs, 1)
+	assert.Equal(t, `Variable "$filter" got invalid value; Field "Filter.names" of required type "[String!]!" was not provided`, errs[0].Message)
+}
+
+func TestCoerceArgumentsReportsPath(t *testing.T) {
+	_, errs := executeCoerceTestQuery(t, `{ search(filter: {names: ["alice", 1]}) }`, nil)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, `Argument "filter" has invalid value at "filter.names[1]"; String cannot represent a non string value: 1`, errs[0].Message)
+}
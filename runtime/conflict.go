@@ -0,0 +1,356 @@
+package runtime
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/wundergraph/graphql-go-tools/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/pkg/astparser"
+	"github.com/wundergraph/graphql-go-tools/pkg/astprinter"
+)
+
+// handlerName returns the name of handler which is used in conflict errors.
+// Generated handlers implement Name() which returns full name of the gRPC service,
+// otherwise Go type of the handler is used.
+func handlerName(handlers []GraphqlHandler, index int) string {
+	h := handlers[index]
+	if named, ok := h.(interface{ Name() string }); ok {
+		return fmt.Sprintf("handler #%d (%s)", index, named.Name())
+	}
+	return fmt.Sprintf("handler #%d (%T)", index, h)
+}
+
+// typeOwner records which handler defined a named type first
+type typeOwner struct {
+	handler    int
+	definition string
+	signature  string
+}
+
+// schemaMerger detects conflicts between handlers while their definitions are merged.
+// Identical definitions which are provided by multiple handlers, e.g. google.protobuf types,
+// are not conflicts, but different definitions with the same name are.
+type schemaMerger struct {
+	handlers []GraphqlHandler
+	types    map[string]*typeOwner
+	enums    map[string]int
+	entities map[string]int
+	// handler which resolves the field, keyed by "Type.field"
+	resolvers map[string]int
+	// handler which declared the node first, and which fetches the node
+	nodes         map[string]int
+	nodeResolvers map[string]int
+	roots         map[string]map[string]int
+	// handler which declared the namespace first, keyed by operation and namespace
+	namespaces map[string]map[string]int
+	// handler which mapped the message of google.protobuf.Any first, keyed by full name of message
+	anyTypes map[string]int
+}
+
+func newSchemaMerger(handlers []GraphqlHandler) *schemaMerger {
+	return &schemaMerger{
+		handlers:      handlers,
+		types:         make(map[string]*typeOwner),
+		enums:         make(map[string]int),
+		entities:      make(map[string]int),
+		resolvers:     make(map[string]int),
+		nodes:         make(map[string]int),
+		nodeResolvers: make(map[string]int),
+		roots: map[string]map[string]int{
+			"query":    make(map[string]int),
+			"mutation": make(map[string]int),
+		},
+		namespaces: map[string]map[string]int{
+			"query":    make(map[string]int),
+			"mutation": make(map[string]int),
+		},
+		anyTypes: make(map[string]int),
+	}
+}
+
+// addDefinition registers types in definition and returns definitions of the types which are new.
+// When definition contains types which are already registered, only new types are printed
+// so that the schema doesn't contain the same type twice.
+func (m *schemaMerger) addDefinition(index int, definition string) (string, error) {
+	document, report := astparser.ParseGraphqlDocumentString(definition)
+	if report.HasErrors() {
+		return "", fmt.Errorf("schema validation error: %s provides invalid definition: %s", handlerName(m.handlers, index), report.Error())
+	}
+
+	nodes := make([]ast.Node, 0, len(document.RootNodes))
+	for _, node := range document.RootNodes {
+		name := document.NodeNameString(node)
+		if name == "" {
+			nodes = append(nodes, node)
+			continue
+		}
+		signature := definitionSignature(&document, node)
+		if owner, ok := m.types[name]; ok {
+			if owner.signature != signature {
+				return "", fmt.Errorf(
+					"schema conflict: type %q is defined differently by %s and %s:\n%s\n\n%s",
+					name, handlerName(m.handlers, owner.handler), handlerName(m.handlers, index), owner.definition, printDefinitions(&document, node),
+				)
+			}
+			continue
+		}
+		m.types[name] = &typeOwner{handler: index, definition: printDefinitions(&document, node), signature: signature}
+		nodes = append(nodes, node)
+	}
+
+	switch len(nodes) {
+	case len(document.RootNodes):
+		return definition, nil
+	case 0:
+		return "", nil
+	default:
+		return printDefinitions(&document, nodes...), nil
+	}
+}
+
+// printDefinitions prints SDL of the root nodes in document
+func printDefinitions(document *ast.Document, nodes ...ast.Node) string {
+	rootNodes := document.RootNodes
+	defer func() {
+		document.RootNodes = rootNodes
+	}()
+	document.RootNodes = nodes
+	out, err := astprinter.PrintStringIndent(document, nil, " ")
+	if err != nil {
+		return ""
+	}
+	return out
+}
+
+// addResolver checks that field of object type is resolved by only one handler
+func (m *schemaMerger) addResolver(index int, typeName, fieldName string) error {
+	key := typeName + "." + fieldName
+	if owner, ok := m.resolvers[key]; ok {
+		return fmt.Errorf(
+			"schema conflict: field %q is resolved by both %s and %s",
+			key, handlerName(m.handlers, owner), handlerName(m.handlers, index),
+		)
+	}
+	m.resolvers[key] = index
+	return nil
+}
+
+// addEnum checks that enum which is provided by multiple handlers has the same internal values.
+func (m *schemaMerger) addEnum(index int, e *Enum, registered map[string]*Enum) error {
+	if prev, ok := registered[e.Name]; ok && !reflect.DeepEqual(prev.Values, e.Values) {
+		return fmt.Errorf(
+			"schema conflict: enum %q has different values in %s (%s) and %s (%s)",
+			e.Name, handlerName(m.handlers, m.enums[e.Name]), enumValuesString(prev), handlerName(m.handlers, index), enumValuesString(e),
+		)
+	}
+	if _, ok := m.enums[e.Name]; !ok {
+		m.enums[e.Name] = index
+	}
+	return nil
+}
+
+// addEntity checks that entity is an object type which has been defined, and is fetched by only one handler.
+func (m *schemaMerger) addEntity(index int, e *Entity) error {
+	if owner, ok := m.entities[e.Name]; ok {
+		return fmt.Errorf(
+			"schema conflict: entity %q is resolved by both %s and %s",
+			e.Name, handlerName(m.handlers, owner), handlerName(m.handlers, index),
+		)
+	}
+	if !m.isObjectType(e.Name) {
+		return fmt.Errorf("schema validation error: entity %q of %s is not a defined object type", e.Name, handlerName(m.handlers, index))
+	}
+	m.entities[e.Name] = index
+	return nil
+}
+
+// addNode checks that node is an object type which has been defined with the same id field by handlers,
+// and is fetched by only one handler.
+func (m *schemaMerger) addNode(index int, n *Node, registered map[string]*Node) error {
+	if !m.isObjectType(n.Name) {
+		return fmt.Errorf("schema validation error: node %q of %s is not a defined object type", n.Name, handlerName(m.handlers, index))
+	}
+	if prev, ok := registered[n.Name]; ok && prev.IDField != n.IDField {
+		return fmt.Errorf(
+			"schema conflict: node %q has different id fields in %s (%s) and %s (%s)",
+			n.Name, handlerName(m.handlers, m.nodes[n.Name]), prev.IDField, handlerName(m.handlers, index), n.IDField,
+		)
+	}
+	if _, ok := m.nodes[n.Name]; !ok {
+		m.nodes[n.Name] = index
+	}
+	if n.Resolve == nil {
+		return nil
+	}
+	if owner, ok := m.nodeResolvers[n.Name]; ok {
+		return fmt.Errorf(
+			"schema conflict: node %q is fetched by both %s and %s",
+			n.Name, handlerName(m.handlers, owner), handlerName(m.handlers, index),
+		)
+	}
+	m.nodeResolvers[n.Name] = index
+	return nil
+}
+
+// addAnyType checks that message which google.protobuf.Any contains is mapped to a defined object type,
+// and handlers which provide the same message map it to the same type.
+func (m *schemaMerger) addAnyType(index int, name, typeName string, registered map[string]string) error {
+	if !m.isObjectType(typeName) {
+		return fmt.Errorf("schema validation error: type %q of %s for %s is not a defined object type", typeName, handlerName(m.handlers, index), name)
+	}
+	if prev, ok := registered[name]; ok && prev != typeName {
+		return fmt.Errorf(
+			"schema conflict: message %q of google.protobuf.Any is mapped to %q by %s and %q by %s",
+			name, prev, handlerName(m.handlers, m.anyTypes[name]), typeName, handlerName(m.handlers, index),
+		)
+	}
+	if _, ok := m.anyTypes[name]; !ok {
+		m.anyTypes[name] = index
+	}
+	return nil
+}
+
+func (m *schemaMerger) isObjectType(name string) bool {
+	owner, ok := m.types[name]
+	return ok && strings.HasPrefix(owner.signature, ast.NodeKindObjectTypeDefinition.String())
+}
+
+// reserveRootFields checks that handlers don't define root fields which the schema adds for the feature
+func (m *schemaMerger) reserveRootFields(operation, feature string, names ...string) error {
+	for _, name := range names {
+		if owner, ok := m.roots[operation][name]; ok {
+			return fmt.Errorf("schema conflict: %s field %q of %s is reserved for %s", operation, name, handlerName(m.handlers, owner), feature)
+		}
+	}
+	return nil
+}
+
+// addRootFields merges root fields of handler into roots, or into namespaces when handler has namespace.
+func (m *schemaMerger) addRootFields(index int, operation, namespace string, fields, roots Fields, namespaces map[string]Fields) error {
+	target, prefix := roots, ""
+	if namespace != "" && len(fields) > 0 {
+		if err := validateNamespace(namespace); err != nil {
+			return fmt.Errorf("schema validation error: %s: %s", handlerName(m.handlers, index), err)
+		}
+		if owner, ok := m.roots[operation][namespace]; ok {
+			return fmt.Errorf(
+				"schema conflict: namespace %q of %s conflicts with %s field defined by %s",
+				namespace, handlerName(m.handlers, index), operation, handlerName(m.handlers, owner),
+			)
+		}
+		if _, ok := namespaces[namespace]; !ok {
+			namespaces[namespace] = make(Fields)
+			m.namespaces[operation][namespace] = index
+		}
+		target, prefix = namespaces[namespace], namespace+"."
+	}
+
+	for name, f := range fields {
+		if owner, ok := m.namespaces[operation][name]; ok && namespace == "" {
+			return fmt.Errorf(
+				"schema conflict: %s field %q of %s conflicts with namespace defined by %s",
+				operation, name, handlerName(m.handlers, index), handlerName(m.handlers, owner),
+			)
+		}
+		if err := m.addRootField(index, operation, prefix+name, target[name], f); err != nil {
+			return err
+		}
+		target[name] = f
+	}
+	return nil
+}
+
+// addNamespaceDefinition registers object type of namespace as a type of the handler which declared the namespace first,
+// so that the type which handlers define with the same name is reported as conflict.
+func (m *schemaMerger) addNamespaceDefinition(operation, namespace, typeName, definition string) (string, error) {
+	index := m.namespaces[operation][namespace]
+	if owner, ok := m.types[typeName]; ok {
+		return "", fmt.Errorf(
+			"schema conflict: type %q of namespace %q of %s is already defined by %s",
+			typeName, namespace, handlerName(m.handlers, index), handlerName(m.handlers, owner.handler),
+		)
+	}
+	return m.addDefinition(index, definition)
+}
+
+// addRootField checks that root field is provided by only one handler.
+func (m *schemaMerger) addRootField(index int, operation, name string, prev, field *Field) error {
+	owners := m.roots[operation]
+	if owner, ok := owners[name]; ok {
+		return fmt.Errorf(
+			"schema conflict: %s field %q is defined by both %s as `%s` and %s as `%s`",
+			operation, name, handlerName(m.handlers, owner), fieldSignature(name, prev), handlerName(m.handlers, index), fieldSignature(name, field),
+		)
+	}
+	owners[name] = index
+	return nil
+}
+
+func fieldSignature(name string, f *Field) string {
+	if f.Args == "" {
+		return name + ": " + f.Type
+	}
+	return name + "(" + f.Args + "): " + f.Type
+}
+
+func enumValuesString(e *Enum) string {
+	values := make([]string, 0, len(e.Values))
+	for name, v := range e.Values {
+		values = append(values, fmt.Sprintf("%s=%v", name, v))
+	}
+	sort.Strings(values)
+	return strings.Join(values, ", ")
+}
+
+// definitionSignature returns the shape of a type definition.
+// Descriptions and directives are ignored because they don't affect compatibility.
+func definitionSignature(document *ast.Document, node ast.Node) string {
+	b := new(strings.Builder)
+	b.WriteString(node.Kind.String())
+
+	switch node.Kind {
+	case ast.NodeKindObjectTypeDefinition:
+		for _, ref := range document.ObjectTypeDefinitions[node.Ref].ImplementsInterfaces.Refs {
+			b.WriteString(" &" + document.TypeNameString(ref))
+		}
+	case ast.NodeKindEnumTypeDefinition:
+		for _, ref := range document.EnumTypeDefinitions[node.Ref].EnumValuesDefinition.Refs {
+			b.WriteString(" " + document.EnumValueDefinitionNameString(ref))
+		}
+	case ast.NodeKindUnionTypeDefinition:
+		for _, ref := range document.UnionTypeDefinitions[node.Ref].UnionMemberTypes.Refs {
+			b.WriteString(" |" + document.TypeNameString(ref))
+		}
+	}
+
+	for _, ref := range document.NodeFieldDefinitions(node) {
+		b.WriteString(" " + document.FieldDefinitionNameString(ref))
+		if args := document.FieldDefinitions[ref].ArgumentsDefinition.Refs; len(args) > 0 {
+			b.WriteString("(")
+			for _, arg := range args {
+				writeInputValueSignature(b, document, arg)
+			}
+			b.WriteString(")")
+		}
+		b.WriteString(":" + printType(document, document.FieldDefinitionType(ref)))
+	}
+	for _, ref := range document.NodeInputFieldDefinitions(node) {
+		writeInputValueSignature(b, document, ref)
+	}
+	return b.String()
+}
+
+func writeInputValueSignature(b *strings.Builder, document *ast.Document, ref int) {
+	b.WriteString(" " + document.InputValueDefinitionNameString(ref))
+	b.WriteString(":" + printType(document, document.InputValueDefinitionType(ref)))
+}
+
+func printType(document *ast.Document, ref int) string {
+	out, err := document.PrintTypeBytes(ref, nil)
+	if err != nil {
+		return document.ResolveTypeNameString(ref)
+	}
+	return string(out)
+}
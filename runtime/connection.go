@@ -0,0 +1,170 @@
+package runtime
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Connection is a Relay connection which is built from a page of AIP-158 List RPC
+type Connection struct {
+	Edges    []*Edge   `json:"edges"`
+	PageInfo *PageInfo `json:"pageInfo"`
+}
+
+// Edge is an item of connection with its cursor
+type Edge struct {
+	Node   interface{} `json:"node"`
+	Cursor string      `json:"cursor"`
+}
+
+// PageInfo tells whether more items can be fetched with cursors of the page
+type PageInfo struct {
+	HasNextPage     bool    `json:"hasNextPage"`
+	HasPreviousPage bool    `json:"hasPreviousPage"`
+	StartCursor     *string `json:"startCursor"`
+	EndCursor       *string `json:"endCursor"`
+}
+
+// Pagination translates first and after arguments of connection to page_size and page_token of List request.
+//
+// Cursor points an item by the page token of the page which contains it and offset in the page,
+// so that the next page starts just after the item even if it's not the last one of the page.
+type Pagination struct {
+	first     int
+	after     string
+	pageToken string
+	offset    int
+}
+
+// NewPagination reads first and after arguments
+func NewPagination(args map[string]interface{}) (*Pagination, error) {
+	p := &Pagination{
+		first: -1,
+	}
+	if v, ok := args["first"]; ok && v != nil {
+		first, ok := paginationSize(v)
+		if !ok || first < 0 {
+			return nil, fmt.Errorf("Argument \"first\" must be a non-negative integer: %v", v)
+		}
+		p.first = first
+	}
+	if v, ok := args["after"].(string); ok && v != "" {
+		token, offset, err := decodeCursor(v)
+		if err != nil {
+			return nil, err
+		}
+		p.after, p.pageToken, p.offset = v, token, offset
+	}
+	return p, nil
+}
+
+// paginationSize reads integer of first argument which is coerced to int, or decoded as int64 or json.Number
+func paginationSize(v interface{}) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case int32:
+		return int(n), true
+	case int64:
+		if n < math.MinInt32 || n > math.MaxInt32 {
+			return 0, false
+		}
+		return int(n), true
+	case json.Number:
+		i, err := strconv.ParseInt(string(n), 10, 32)
+		return int(i), err == nil
+	}
+	return 0, false
+}
+
+// Empty returns an empty connection when first is zero without cursor,
+// so that resolvers respond it without calling the backend.
+func (p *Pagination) Empty() (*Connection, bool) {
+	if p.first != 0 || p.after != "" {
+		return nil, false
+	}
+	return &Connection{
+		Edges:    []*Edge{},
+		PageInfo: &PageInfo{},
+	}, true
+}
+
+// Request returns page_size and page_token fields of List request.
+// page_size is omitted to use default size of the backend when first is not provided.
+func (p *Pagination) Request() map[string]interface{} {
+	req := map[string]interface{}{
+		"page_token": p.pageToken,
+	}
+	if p.first >= 0 {
+		req["page_size"] = p.offset + p.first
+	}
+	return req
+}
+
+// Connection builds connection from items and next_page_token of List response.
+// Items before the cursor are skipped, and the page is truncated to first items.
+func (p *Pagination) Connection(items interface{}, nextPageToken string) (*Connection, error) {
+	values, ok := listValues(items)
+	if !ok && !isNil(items) {
+		return nil, errors.New("Items of connection must be a list")
+	}
+	size := len(values)
+	if p.offset < len(values) {
+		values = values[p.offset:]
+	} else {
+		values = nil
+	}
+	if p.first >= 0 && len(values) > p.first {
+		values = values[:p.first]
+	}
+
+	c := &Connection{
+		Edges: make([]*Edge, len(values)),
+		PageInfo: &PageInfo{
+			HasNextPage:     nextPageToken != "" || p.offset+len(values) < size,
+			HasPreviousPage: p.after != "",
+		},
+	}
+	for i, v := range values {
+		position := p.offset + i + 1
+		cursor := encodeCursor(p.pageToken, position)
+		// The last item of the page points the next page in order not to grow offset
+		if position == size && nextPageToken != "" {
+			cursor = encodeCursor(nextPageToken, 0)
+		}
+		c.Edges[i] = &Edge{
+			Node:   v,
+			Cursor: cursor,
+		}
+	}
+	if len(c.Edges) > 0 {
+		c.PageInfo.StartCursor = &c.Edges[0].Cursor
+		c.PageInfo.EndCursor = &c.Edges[len(c.Edges)-1].Cursor
+	}
+	return c, nil
+}
+
+func encodeCursor(pageToken string, offset int) string {
+	return base64.StdEncoding.EncodeToString([]byte(strconv.Itoa(offset) + ":" + pageToken))
+}
+
+func decodeCursor(cursor string) (string, int, error) {
+	buf, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", 0, fmt.Errorf("Invalid cursor \"%s\"", cursor)
+	}
+	offset, token, ok := strings.Cut(string(buf), ":")
+	if !ok {
+		return "", 0, fmt.Errorf("Invalid cursor \"%s\"", cursor)
+	}
+	n, err := strconv.Atoi(offset)
+	if err != nil || n < 0 {
+		return "", 0, fmt.Errorf("Invalid cursor \"%s\"", cursor)
+	}
+	return token, n, nil
+}
@@ -0,0 +1,137 @@
+package runtime
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPagination(t *testing.T) {
+	p, err := NewPagination(map[string]interface{}{"first": 2})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"page_size": 2, "page_token": ""}, p.Request())
+
+	c, err := p.Connection([]string{"a", "b"}, "t2")
+	assert.NoError(t, err)
+	assert.Equal(t, []*Edge{
+		{Node: "a", Cursor: encodeCursor("", 1)},
+		{Node: "b", Cursor: encodeCursor("t2", 0)},
+	}, c.Edges)
+	assert.True(t, c.PageInfo.HasNextPage)
+	assert.False(t, c.PageInfo.HasPreviousPage)
+	assert.Equal(t, encodeCursor("", 1), *c.PageInfo.StartCursor)
+	assert.Equal(t, encodeCursor("t2", 0), *c.PageInfo.EndCursor)
+
+	// after cursor in the middle of the page fetches the page again and skips items before it
+	p, err = NewPagination(map[string]interface{}{"first": 1, "after": encodeCursor("", 1)})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"page_size": 2, "page_token": ""}, p.Request())
+
+	c, err = p.Connection([]string{"a", "b"}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []*Edge{{Node: "b", Cursor: encodeCursor("", 2)}}, c.Edges)
+	assert.False(t, c.PageInfo.HasNextPage)
+	assert.True(t, c.PageInfo.HasPreviousPage)
+
+	// page_size is left to the backend without first
+	p, err = NewPagination(map[string]interface{}{"after": encodeCursor("t2", 0)})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"page_token": "t2"}, p.Request())
+
+	c, err = p.Connection([]string{"c", "d", "e"}, "t3")
+	assert.NoError(t, err)
+	assert.Len(t, c.Edges, 3)
+	assert.True(t, c.PageInfo.HasNextPage)
+
+	c, err = p.Connection(nil, "")
+	assert.NoError(t, err)
+	assert.Empty(t, c.Edges)
+	assert.Nil(t, c.PageInfo.StartCursor)
+	assert.Nil(t, c.PageInfo.EndCursor)
+}
+
+func TestPaginationTruncatesPage(t *testing.T) {
+	p, err := NewPagination(map[string]interface{}{"first": 1})
+	assert.NoError(t, err)
+
+	// backend may return more items than page_size
+	c, err := p.Connection([]string{"a", "b"}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []*Edge{{Node: "a", Cursor: encodeCursor("", 1)}}, c.Edges)
+	assert.True(t, c.PageInfo.HasNextPage)
+}
+
+func TestPaginationEmpty(t *testing.T) {
+	p, err := NewPagination(map[string]interface{}{"first": 0})
+	assert.NoError(t, err)
+	c, ok := p.Empty()
+	assert.True(t, ok)
+	assert.Empty(t, c.Edges)
+	assert.False(t, c.PageInfo.HasNextPage)
+
+	p, err = NewPagination(map[string]interface{}{"first": 0, "after": encodeCursor("t2", 0)})
+	assert.NoError(t, err)
+	_, ok = p.Empty()
+	assert.False(t, ok)
+
+	p, err = NewPagination(map[string]interface{}{})
+	assert.NoError(t, err)
+	_, ok = p.Empty()
+	assert.False(t, ok)
+}
+
+func TestPaginationIntegerArguments(t *testing.T) {
+	for _, first := range []interface{}{int64(2), json.Number("2"), int32(2)} {
+		p, err := NewPagination(map[string]interface{}{"first": first})
+		assert.NoError(t, err, first)
+		assert.Equal(t, map[string]interface{}{"page_size": 2, "page_token": ""}, p.Request(), first)
+	}
+
+	_, err := NewPagination(map[string]interface{}{"first": json.Number("1.5")})
+	assert.EqualError(t, err, `Argument "first" must be a non-negative integer: 1.5`)
+}
+
+func TestPaginationInvalidArguments(t *testing.T) {
+	_, err := NewPagination(map[string]interface{}{"first": -1})
+	assert.EqualError(t, err, `Argument "first" must be a non-negative integer: -1`)
+
+	_, err = NewPagination(map[string]interface{}{"after": "invalid"})
+	assert.EqualError(t, err, `Invalid cursor "invalid"`)
+
+	p, err := NewPagination(map[string]interface{}{})
+	assert.NoError(t, err)
+	_, err = p.Connection("a", "")
+	assert.EqualError(t, err, "Items of connection must be a list")
+}
+
+func TestServeMuxConnection(t *testing.T) {
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(&testHandler{
+		types: Types{
+			Definitions: []string{
+				"type StringConnection {\n  edges: [StringEdge!]!\n  pageInfo: PageInfo!\n}",
+				"type StringEdge {\n  node: String\n  cursor: String!\n}",
+				"type PageInfo {\n  hasNextPage: Boolean!\n  hasPreviousPage: Boolean!\n  startCursor: String\n  endCursor: String\n}",
+			},
+		},
+		queries: Fields{
+			"names": &Field{
+				Args: "first: Int, after: String",
+				Type: "StringConnection",
+				Resolve: func(p ResolveParams) (interface{}, error) {
+					pagination, err := NewPagination(p.Args)
+					if err != nil {
+						return nil, err
+					}
+					return pagination.Connection([]string{"alice", "bob"}, "next")
+				},
+			},
+		},
+	}))
+
+	assert.JSONEq(t, `{"data": {"names": {
+		"edges": [{"node": "alice", "cursor": "`+encodeCursor("", 1)+`"}],
+		"pageInfo": {"hasNextPage": true, "hasPreviousPage": false, "endCursor": "`+encodeCursor("", 1)+`"}
+	}}}`, serveTestQuery(mux, "{ names(first: 1) { edges { node cursor } pageInfo { hasNextPage hasPreviousPage endCursor } } }"))
+}
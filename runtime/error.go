@@ -1,13 +1,11 @@
 package runtime
 
 import (
-	"context"
 	"encoding/json"
 	"regexp"
 	"strings"
 
 	"github.com/wundergraph/graphql-go-tools/pkg/ast"
-	"github.com/wundergraph/graphql-go-tools/pkg/execution"
 	"github.com/wundergraph/graphql-go-tools/pkg/graphqlerrors"
 	"github.com/wundergraph/graphql-go-tools/pkg/operationreport"
 )
@@ -25,24 +23,57 @@ type GraphqlError struct {
 	Locations  []graphqlerrors.Location `json:"locations,omitempty"`
 	Path       []interface{}            `json:"path,omitempty"`
 	Extensions map[string]interface{}   `json:"extensions,omitempty"`
+
+	// original error which is returned from resolver
+	err error
+}
+
+// NewGraphqlError creates GraphqlError from resolver error with keeping it as original error
+func NewGraphqlError(err error) GraphqlError {
+	return GraphqlError{
+		Message: err.Error(),
+		err:     err,
+	}
+}
+
+// OriginalError returns the error which GraphqlError is created from, or nil
+func (e GraphqlError) OriginalError() error {
+	return e.err
 }
 
 // GraphqlErrorHandler is a function type for custom error handling
 type GraphqlErrorHandler func(errs []GraphqlError)
 
-// defaultGraphqlErrorHandler adds error extension code from gRPC error message
+// defaultGraphqlErrorHandler adds error extensions from gRPC status.
+// The status is looked up through wrapped original error,
+// and falls back to parse error message when original error is not available.
 func defaultGraphqlErrorHandler(errs []GraphqlError) {
 	for i := range errs {
+		if st, ok := grpcStatusFromError(errs[i].err); ok {
+			errs[i].Message = st.Message()
+			errs[i].Extensions = mergeExtensions(errs[i].Extensions, StatusExtensions(st))
+			continue
+		}
+
 		m := grpcBackendErrorMatcher.FindStringSubmatch(errs[i].Message)
 		if m == nil {
 			continue
 		}
 		errs[i].Message = strings.TrimSpace(m[2])
-		if errs[i].Extensions == nil {
-			errs[i].Extensions = make(map[string]interface{})
-		}
-		errs[i].Extensions["code"] = strings.ToUpper(m[1])
+		errs[i].Extensions = mergeExtensions(errs[i].Extensions, map[string]interface{}{
+			"code": canonicalCodeName(m[1]),
+		})
+	}
+}
+
+func mergeExtensions(dst, src map[string]interface{}) map[string]interface{} {
+	if dst == nil {
+		dst = make(map[string]interface{}, len(src))
+	}
+	for k, v := range src {
+		dst[k] = v
 	}
+	return dst
 }
 
 // ConvertToGraphQLErrors converts operationreport.ExternalErrors to GraphqlErrors
@@ -96,32 +127,3 @@ func (e *GraphqlError) marshalPath() json.RawMessage {
 	}
 	return pathJSON
 }
-
-// ExecuteGraphQL executes a GraphQL operation
-func ExecuteGraphQL(ctx context.Context, schema *ast.Document, operation *ast.OperationDefinition, variables map[string]interface{}) ([]byte, []GraphqlError) {
-	executor := execution.NewExecutor(nil)
-
-	rootNode := &CustomRootNode{
-		schema:        schema,
-		operationType: operation.OperationType,
-	}
-
-	var buf strings.Builder
-	report := &operationreport.Report{}
-	executionContext := execution.Context{
-		Context:   ctx,
-		Variables: convertVariables(variables),
-	}
-
-	err := executor.Execute(executionContext, rootNode, &buf)
-
-	if err != nil {
-		return nil, []GraphqlError{{Message: err.Error()}}
-	}
-
-	if report.HasErrors() {
-		return nil, ConvertToGraphQLErrors(report.ExternalErrors)
-	}
-
-	return []byte(buf.String()), nil
-}
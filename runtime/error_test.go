@@ -1,9 +1,15 @@
 package runtime
 
 import (
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
 func TestDefaultErrorHandler(t *testing.T) {
@@ -21,5 +27,67 @@ func TestDefaultErrorHandler(t *testing.T) {
 	assert.NotNil(t, err.Extensions)
 	ext, ok := err.Extensions["code"]
 	assert.True(t, ok)
-	assert.Equal(t, "NOTFOUND", ext)
+	assert.Equal(t, "NOT_FOUND", ext)
+}
+
+func TestDefaultErrorHandlerWithWrappedStatus(t *testing.T) {
+	st := status.New(codes.NotFound, "Example Message")
+	errs := []GraphqlError{
+		NewGraphqlError(fmt.Errorf("Failed to call RPC GetUser: %w", st.Err())),
+	}
+
+	defaultGraphqlErrorHandler(errs)
+
+	assert.Equal(t, "Example Message", errs[0].Message)
+	assert.Equal(t, map[string]interface{}{"code": "NOT_FOUND"}, errs[0].Extensions)
+}
+
+func TestDefaultErrorHandlerWithStatusDetails(t *testing.T) {
+	st, err := status.New(codes.InvalidArgument, "Invalid request").WithDetails(
+		&errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{
+				{Field: "email", Description: "must not be empty"},
+			},
+		},
+		&errdetails.ErrorInfo{
+			Reason:   "EMAIL_REQUIRED",
+			Domain:   "example.com",
+			Metadata: map[string]string{"field": "email"},
+		},
+		&errdetails.RetryInfo{
+			RetryDelay: durationpb.New(1500 * time.Millisecond),
+		},
+		&errdetails.QuotaFailure{
+			Violations: []*errdetails.QuotaFailure_Violation{
+				{Subject: "project:example", Description: "daily limit exceeded"},
+			},
+		},
+	)
+	assert.NoError(t, err)
+	errs := []GraphqlError{NewGraphqlError(st.Err())}
+
+	defaultGraphqlErrorHandler(errs)
+
+	assert.Equal(t, "Invalid request", errs[0].Message)
+	assert.Equal(t, map[string]interface{}{
+		"code": "INVALID_ARGUMENT",
+		"fieldViolations": []map[string]interface{}{
+			{"field": "email", "description": "must not be empty"},
+		},
+		"reason":     "EMAIL_REQUIRED",
+		"domain":     "example.com",
+		"metadata":   map[string]string{"field": "email"},
+		"retryDelay": "1.5s",
+		"quotaViolations": []map[string]interface{}{
+			{"subject": "project:example", "description": "daily limit exceeded"},
+		},
+	}, errs[0].Extensions)
+}
+
+func TestGraphqlErrorHidesOriginalError(t *testing.T) {
+	e := NewGraphqlError(status.Error(codes.Internal, "secret"))
+	b, err := e.MarshalJSON()
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"message":"rpc error: code = Internal desc = secret"}`, string(b))
+	assert.Error(t, e.OriginalError())
 }
@@ -0,0 +1,700 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+	"github.com/wundergraph/graphql-go-tools/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/pkg/graphqlerrors"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// ExecuteGraphQL executes an operation in the document against the schema.
+// Returned data is JSON of the result which may be partial, and field errors are returned together.
+// When the operation could not be executed at all, data is nil.
+func ExecuteGraphQL(
+	ctx context.Context,
+	schema *Schema,
+	operation *ast.Document,
+	operationName string,
+	variables map[string]interface{},
+) ([]byte, []GraphqlError) {
+
+	ref, err := findOperation(operation, operationName)
+	if err != nil {
+		return nil, []GraphqlError{{Message: err.Error()}}
+	}
+
+	e := &executor{
+		ctx:          ctx,
+		schema:       schema,
+		operation:    operation,
+		rawVariables: variables,
+	}
+
+	var rootType string
+	switch operation.OperationDefinitions[ref].OperationType {
+	case ast.OperationTypeQuery:
+		rootType = string(schema.Document.Index.QueryTypeName)
+	case ast.OperationTypeMutation:
+		rootType = string(schema.Document.Index.MutationTypeName)
+	default:
+		return nil, []GraphqlError{{Message: "Subscription operation is not supported"}}
+	}
+	if _, ok := schema.Document.Index.FirstNodeByNameStr(rootType); rootType == "" || !ok {
+		return nil, []GraphqlError{{Message: "Schema is not configured for this operation type"}}
+	}
+
+	var errs []GraphqlError
+	if e.variables, errs = e.coerceVariables(ref, variables); len(errs) > 0 {
+		return nil, errs
+	}
+
+	fields := newFieldGroups()
+	e.collectFields(rootType, operation.OperationDefinitions[ref].SelectionSet, fields, make(map[string]struct{}))
+	data, ok := e.executeFields(rootType, nil, fields, nil)
+
+	var buf []byte
+	if ok {
+		if buf, err = json.Marshal(data); err != nil {
+			return nil, []GraphqlError{{Message: err.Error()}}
+		}
+	} else {
+		buf = []byte("null")
+	}
+	return buf, e.errors
+}
+
+// findOperation finds operation definition by name.
+// Name can be omitted if the document contains only one operation.
+func findOperation(document *ast.Document, name string) (int, error) {
+	found := -1
+	for _, node := range document.RootNodes {
+		if node.Kind != ast.NodeKindOperationDefinition {
+			continue
+		}
+		if name == "" {
+			if found != -1 {
+				return -1, errors.New("Must provide operation name if query contains multiple operations")
+			}
+			found = node.Ref
+			continue
+		}
+		if document.OperationDefinitionNameString(node.Ref) == name {
+			return node.Ref, nil
+		}
+	}
+	if found == -1 {
+		if name != "" {
+			return -1, fmt.Errorf("Unknown operation named \"%s\"", name)
+		}
+		return -1, errors.New("No operation found in the query")
+	}
+	return found, nil
+}
+
+type executor struct {
+	ctx       context.Context
+	schema    *Schema
+	operation *ast.Document
+	variables map[string]interface{}
+	// rawVariables are values which the client sends before default values of input fields are applied
+	rawVariables map[string]interface{}
+	errors       []GraphqlError
+}
+
+// fieldGroups holds fields in selection set grouped by response key with keeping order
+type fieldGroups struct {
+	keys   []string
+	fields map[string][]int
+}
+
+func newFieldGroups() *fieldGroups {
+	return &fieldGroups{
+		fields: make(map[string][]int),
+	}
+}
+
+func (g *fieldGroups) add(key string, ref int) {
+	if _, ok := g.fields[key]; !ok {
+		g.keys = append(g.keys, key)
+	}
+	g.fields[key] = append(g.fields[key], ref)
+}
+
+// resultObject is an object in response which keeps order of fields as requested
+type resultObject struct {
+	keys   []string
+	values []interface{}
+}
+
+func (o *resultObject) set(key string, value interface{}) {
+	o.keys = append(o.keys, key)
+	o.values = append(o.values, value)
+}
+
+func (o *resultObject) MarshalJSON() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(o.values[i])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// collectFields collects fields in selection set which are applied to the object type,
+// following fragments and @skip/@include directives.
+func (e *executor) collectFields(typeName string, set int, groups *fieldGroups, visited map[string]struct{}) {
+	op := e.operation
+	for _, ref := range op.SelectionSets[set].SelectionRefs {
+		selection := op.Selections[ref]
+		switch selection.Kind {
+		case ast.SelectionKindField:
+			if !e.shouldInclude(op.Fields[selection.Ref].Directives.Refs) {
+				continue
+			}
+			groups.add(op.FieldAliasOrNameString(selection.Ref), selection.Ref)
+		case ast.SelectionKindInlineFragment:
+			fragment := op.InlineFragments[selection.Ref]
+			if !e.shouldInclude(fragment.Directives.Refs) {
+				continue
+			}
+			if fragment.TypeCondition.Type != -1 && !e.doesFragmentApply(typeName, op.InlineFragmentTypeConditionNameString(selection.Ref)) {
+				continue
+			}
+			e.collectFields(typeName, fragment.SelectionSet, groups, visited)
+		case ast.SelectionKindFragmentSpread:
+			spread := op.FragmentSpreads[selection.Ref]
+			if !e.shouldInclude(spread.Directives.Refs) {
+				continue
+			}
+			name := op.FragmentSpreadNameString(selection.Ref)
+			if _, ok := visited[name]; ok {
+				continue
+			}
+			visited[name] = struct{}{}
+			fragmentRef, ok := op.FragmentDefinitionRef([]byte(name))
+			if !ok || !e.doesFragmentApply(typeName, string(op.FragmentDefinitionTypeName(fragmentRef))) {
+				continue
+			}
+			e.collectFields(typeName, op.FragmentDefinitions[fragmentRef].SelectionSet, groups, visited)
+		}
+	}
+}
+
+// selectedFields collects dotted names of leaf fields which are selected under fields of the type.
+// Abstract type collects fields of all possible types, and the same field of aliases is reported once.
+// Fields of union type are reported by their name because their subfields belong to the message in Any.
+func (e *executor) selectedFields(typeName string, fields []int) []string {
+	op, definition := e.operation, e.schema.Document
+	var paths []string
+	seen := make(map[string]struct{})
+
+	var walk func(typeName string, fields []int, prefix string)
+	walk = func(typeName string, fields []int, prefix string) {
+		node, ok := definition.Index.FirstNodeByNameStr(typeName)
+		if !ok {
+			return
+		}
+		typeNames := []string{typeName}
+		if node.Kind == ast.NodeKindInterfaceTypeDefinition || node.Kind == ast.NodeKindUnionTypeDefinition {
+			typeNames = e.possibleTypes(node)
+		}
+		for _, t := range typeNames {
+			object, _ := definition.Index.FirstNodeByNameStr(t) // nolint: errcheck
+			groups := newFieldGroups()
+			visited := make(map[string]struct{})
+			for _, ref := range fields {
+				if op.Fields[ref].HasSelections {
+					e.collectFields(t, op.Fields[ref].SelectionSet, groups, visited)
+				}
+			}
+			for _, key := range groups.keys {
+				refs := groups.fields[key]
+				name := op.FieldNameString(refs[0])
+				fieldDefinition, ok := definition.NodeFieldDefinitionByName(object, []byte(name))
+				if name == "__typename" || !ok {
+					continue
+				}
+				fieldType := definition.ResolveTypeNameString(definition.FieldDefinitions[fieldDefinition].Type)
+				// Union is mapped from google.protobuf.Any, whose contained message can't be addressed by paths
+				if op.Fields[refs[0]].HasSelections && !e.isUnion(fieldType) {
+					walk(fieldType, refs, prefix+name+".")
+					continue
+				}
+				if _, ok := seen[prefix+name]; !ok {
+					paths = append(paths, prefix+name)
+					seen[prefix+name] = struct{}{}
+				}
+			}
+		}
+	}
+	walk(typeName, fields, "")
+	return paths
+}
+
+func (e *executor) isUnion(typeName string) bool {
+	node, ok := e.schema.Document.Index.FirstNodeByNameStr(typeName)
+	return ok && node.Kind == ast.NodeKindUnionTypeDefinition
+}
+
+// shouldInclude evaluates @skip and @include directives
+func (e *executor) shouldInclude(directives []int) bool {
+	op := e.operation
+	for _, ref := range directives {
+		name := op.DirectiveNameString(ref)
+		if name != "skip" && name != "include" {
+			continue
+		}
+		value, ok := op.DirectiveArgumentValueByName(ref, []byte("if"))
+		if !ok {
+			continue
+		}
+		var cond bool
+		switch value.Kind {
+		case ast.ValueKindBoolean:
+			cond = bool(op.BooleanValue(value.Ref))
+		case ast.ValueKindVariable:
+			cond, _ = e.variables[op.VariableValueNameString(value.Ref)].(bool) // nolint: errcheck
+		}
+		if name == "skip" && cond {
+			return false
+		}
+		if name == "include" && !cond {
+			return false
+		}
+	}
+	return true
+}
+
+func (e *executor) doesFragmentApply(typeName, condition string) bool {
+	if typeName == condition {
+		return true
+	}
+	definition := e.schema.Document
+	object, ok := definition.Index.FirstNodeByNameStr(typeName)
+	if !ok {
+		return false
+	}
+	node, ok := definition.Index.FirstNodeByNameStr(condition)
+	if !ok {
+		return false
+	}
+	switch node.Kind {
+	case ast.NodeKindInterfaceTypeDefinition:
+		return definition.NodeImplementsInterface(object, node)
+	case ast.NodeKindUnionTypeDefinition:
+		return definition.NodeIsUnionMember(object, node)
+	default:
+		return false
+	}
+}
+
+// executeFields executes grouped fields of the object.
+// It returns false when a non-null field is resolved as null, then the object itself must be null.
+func (e *executor) executeFields(typeName string, source interface{}, groups *fieldGroups, path []interface{}) (*resultObject, bool) {
+	result := &resultObject{}
+	for _, key := range groups.keys {
+		value, ok := e.executeField(typeName, source, groups.fields[key], appendPath(path, key))
+		if !ok {
+			return nil, false
+		}
+		result.set(key, value)
+	}
+	return result, true
+}
+
+// executeField resolves the field and completes its value.
+// It returns false when the field is non-null type and resolved as null.
+func (e *executor) executeField(typeName string, source interface{}, fields []int, path []interface{}) (interface{}, bool) {
+	op, definition := e.operation, e.schema.Document
+	name := op.FieldNameString(fields[0])
+	if name == "__typename" {
+		return typeName, true
+	}
+
+	node, _ := definition.Index.FirstNodeByNameStr(typeName) // nolint: errcheck
+	fieldDefinition, ok := definition.NodeFieldDefinitionByName(node, []byte(name))
+	if !ok {
+		e.addError(fmt.Errorf("Cannot query field \"%s\" on type \"%s\"", name, typeName), fields, path)
+		return nil, true
+	}
+	fieldType := definition.FieldDefinitions[fieldDefinition].Type
+	nullable := !definition.TypeIsNonNull(fieldType)
+
+	args, err := e.coerceArguments(fieldDefinition, fields[0])
+	if err != nil {
+		e.addError(err, fields, path)
+		return nil, nullable
+	}
+
+	value, err := e.resolve(e.resolver(typeName, name), ResolveParams{
+		Context: e.ctx,
+		Source:  source,
+		Args:    args,
+		Info: ResolveInfo{
+			FieldName:  name,
+			ParentType: typeName,
+			Path:       path,
+			selectedFields: func() []string {
+				return e.selectedFields(definition.ResolveTypeNameString(fieldType), fields)
+			},
+			providedFields: func(argument string) []string {
+				return e.providedFields(fieldDefinition, fields[0], argument)
+			},
+		},
+	})
+	if err != nil {
+		e.addError(err, fields, path)
+		return nil, nullable
+	}
+
+	completed, _ := e.completeValue(typeName, fieldType, fields, value, path)
+	if completed == nil && !nullable {
+		return nil, false
+	}
+	return completed, true
+}
+
+func (e *executor) resolver(typeName, fieldName string) FieldResolveFn {
+	if typeName == string(e.schema.Document.Index.QueryTypeName) {
+		switch fieldName {
+		case "__schema":
+			return func(p ResolveParams) (interface{}, error) {
+				return e.schema.introspection, nil
+			}
+		case "__type":
+			return func(p ResolveParams) (interface{}, error) {
+				name, _ := p.Args["name"].(string) // nolint: errcheck
+				return e.schema.introspectionType(name), nil
+			}
+		}
+	}
+	if fn, ok := e.schema.resolvers[typeName][fieldName]; ok && fn != nil {
+		return fn
+	}
+	return defaultResolveFn
+}
+
+// resolve calls resolver with recovering from panic so that one broken resolver doesn't break the whole response
+func (e *executor) resolve(fn FieldResolveFn, p ResolveParams) (value interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic in resolver of %s.%s: %v", p.Info.ParentType, p.Info.FieldName, r)
+		}
+	}()
+	return fn(p)
+}
+
+// completeValue converts resolved value to response value along with field type.
+// The second value reports null has been caused by an error which is already recorded.
+func (e *executor) completeValue(parentType string, typeRef int, fields []int, value interface{}, path []interface{}) (interface{}, bool) {
+	definition := e.schema.Document
+	t := definition.Types[typeRef]
+
+	if t.TypeKind == ast.TypeKindNonNull {
+		completed, erred := e.completeValue(parentType, t.OfType, fields, value, path)
+		if completed == nil {
+			if !erred {
+				e.addError(fmt.Errorf(
+					"Cannot return null for non-nullable field %s.%s",
+					parentType, e.operation.FieldNameString(fields[0]),
+				), fields, path)
+			}
+			return nil, true
+		}
+		return completed, false
+	}
+
+	if isNil(value) {
+		return nil, false
+	}
+
+	if t.TypeKind == ast.TypeKindList {
+		items, ok := listValues(value)
+		if !ok {
+			e.addError(fmt.Errorf("Expected list for field %s", e.operation.FieldNameString(fields[0])), fields, path)
+			return nil, true
+		}
+		result := make([]interface{}, len(items))
+		for i, item := range items {
+			completed, _ := e.completeValue(parentType, t.OfType, fields, item, appendPath(path, i))
+			if completed == nil && definition.TypeIsNonNull(t.OfType) {
+				return nil, true
+			}
+			result[i] = completed
+		}
+		return result, false
+	}
+
+	typeName := definition.TypeNameString(typeRef)
+	node, ok := definition.Index.FirstNodeByNameStr(typeName)
+	if !ok {
+		e.addError(fmt.Errorf("Unknown type \"%s\"", typeName), fields, path)
+		return nil, true
+	}
+
+	var err error
+	switch node.Kind {
+	case ast.NodeKindScalarTypeDefinition:
+		if value, err = serializeScalar(typeName, value); err != nil {
+			e.addError(err, fields, path)
+			return nil, true
+		}
+		return value, false
+	case ast.NodeKindEnumTypeDefinition:
+		if value, err = e.serializeEnum(node, value); err != nil {
+			e.addError(err, fields, path)
+			return nil, true
+		}
+		return value, false
+	case ast.NodeKindInterfaceTypeDefinition, ast.NodeKindUnionTypeDefinition:
+		if typed, ok := value.(*typedValue); ok {
+			if typed.err != nil {
+				e.addError(typed.err, fields, path)
+				return nil, true
+			}
+			if isNil(typed.value) {
+				return nil, false
+			}
+			typeName, value = typed.typeName, typed.value
+			break
+		}
+		if a, ok := value.(*anypb.Any); ok {
+			if typeName, value, err = e.resolveAny(node, a); err != nil {
+				e.addError(err, fields, path)
+				return nil, true
+			}
+			break
+		}
+		if typeName, err = e.resolveAbstractType(node, value); err != nil {
+			e.addError(err, fields, path)
+			return nil, true
+		}
+	}
+
+	groups := newFieldGroups()
+	visited := make(map[string]struct{})
+	for _, ref := range fields {
+		if e.operation.Fields[ref].HasSelections {
+			e.collectFields(typeName, e.operation.Fields[ref].SelectionSet, groups, visited)
+		}
+	}
+	result, ok := e.executeFields(typeName, value, groups, path)
+	if !ok {
+		return nil, true
+	}
+	return result, false
+}
+
+// typedValue is a value of interface or union type which tells its object type,
+// or the error of fetching it which is reported with the path of the item, e.g. an item of _entities.
+type typedValue struct {
+	typeName string
+	value    interface{}
+	err      error
+}
+
+// resolveAbstractType determines object type of the value for interface or union type.
+// The value can tell its type by "__typename" key, otherwise the type must have only one possible type.
+func (e *executor) resolveAbstractType(node ast.Node, value interface{}) (string, error) {
+	definition := e.schema.Document
+	possibleTypes := e.possibleTypes(node)
+	if m, ok := value.(map[string]interface{}); ok {
+		if typeName, ok := m["__typename"].(string); ok {
+			for _, v := range possibleTypes {
+				if v == typeName {
+					return typeName, nil
+				}
+			}
+			return "", fmt.Errorf("Type \"%s\" is not a possible type of \"%s\"", typeName, definition.NodeNameString(node))
+		}
+	}
+	if len(possibleTypes) == 1 {
+		return possibleTypes[0], nil
+	}
+	return "", fmt.Errorf("Abstract type \"%s\" must resolve to an Object type at runtime", definition.NodeNameString(node))
+}
+
+// possibleTypes returns object type names which implement the interface or are members of the union
+func (e *executor) possibleTypes(node ast.Node) []string {
+	definition := e.schema.Document
+	var possibleTypes []string
+	switch node.Kind {
+	case ast.NodeKindInterfaceTypeDefinition:
+		for _, n := range definition.InterfaceTypeDefinitionImplementedByRootNodes(node.Ref) {
+			possibleTypes = append(possibleTypes, definition.NodeNameString(n))
+		}
+	case ast.NodeKindUnionTypeDefinition:
+		for _, ref := range definition.NodeUnionMemberRefs(node) {
+			possibleTypes = append(possibleTypes, definition.TypeNameString(ref))
+		}
+	}
+	return possibleTypes
+}
+
+// resolveAny unpacks Any by its type URL, and determines object type by full name of the contained message.
+// Messages are resolved from types which are linked into the binary, so that generated code of them must be imported.
+func (e *executor) resolveAny(node ast.Node, a *anypb.Any) (string, interface{}, error) {
+	m, err := a.UnmarshalNew()
+	if err != nil {
+		return "", nil, fmt.Errorf("Failed to unpack %s: %w", a.GetTypeUrl(), err)
+	}
+	name := string(m.ProtoReflect().Descriptor().FullName())
+	if typeName, ok := e.schema.anyTypes[name]; ok {
+		for _, v := range e.possibleTypes(node) {
+			if v == typeName {
+				return typeName, m, nil
+			}
+		}
+	}
+	return "", nil, fmt.Errorf("Message \"%s\" is not a possible type of \"%s\"", name, e.schema.Document.NodeNameString(node))
+}
+
+func (e *executor) serializeEnum(node ast.Node, value interface{}) (interface{}, error) {
+	definition := e.schema.Document
+	typeName := definition.NodeNameString(node)
+	isValid := func(name string) bool {
+		return definition.EnumTypeDefinitionContainsEnumValue(node.Ref, []byte(name))
+	}
+
+	if v, ok := value.(string); ok && isValid(v) {
+		return v, nil
+	}
+	if enum, ok := e.schema.enums[typeName]; ok {
+		for name, v := range enum.Values {
+			if enumValueEqual(v, value) {
+				return name, nil
+			}
+		}
+	}
+	if v, ok := value.(fmt.Stringer); ok && isValid(v.String()) {
+		return v.String(), nil
+	}
+	return nil, fmt.Errorf("Enum \"%s\" cannot represent value: %v", typeName, value)
+}
+
+func (e *executor) addError(err error, fields []int, path []interface{}) {
+	ge := NewGraphqlError(err)
+	ge.Path = path
+	for _, ref := range fields {
+		pos := e.operation.Fields[ref].Position
+		ge.Locations = append(ge.Locations, graphqlerrors.Location{
+			Line:   pos.LineStart,
+			Column: pos.CharStart,
+		})
+	}
+	e.errors = append(e.errors, ge)
+}
+
+// defaultResolveFn resolves field from parent value which is map or struct
+func defaultResolveFn(p ResolveParams) (interface{}, error) {
+	if m, ok := p.Source.(map[string]interface{}); ok {
+		return m[p.Info.FieldName], nil
+	}
+
+	v := derefValue(reflect.ValueOf(p.Source))
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, nil
+		}
+		if vv := v.MapIndex(reflect.ValueOf(p.Info.FieldName).Convert(v.Type().Key())); vv.IsValid() {
+			return vv.Interface(), nil
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" {
+				continue
+			}
+			tag := strings.Split(f.Tag.Get("json"), ",")[0]
+			if tag == "-" {
+				continue
+			}
+			if tag == "" {
+				tag = f.Name
+			}
+			if tag == p.Info.FieldName || strcase.ToLowerCamel(tag) == p.Info.FieldName {
+				return v.Field(i).Interface(), nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// listValues returns items of slice or array.
+// Go map is also accepted as a list of key-value objects like MarshalResponse does.
+func listValues(value interface{}) ([]interface{}, bool) {
+	if v, ok := value.([]interface{}); ok {
+		return v, true
+	}
+	v := derefValue(reflect.ValueOf(value))
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		items := make([]interface{}, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			items[i] = v.Index(i).Interface()
+		}
+		return items, true
+	case reflect.Map:
+		items := make([]interface{}, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			items = append(items, mapValue{
+				Key:   iter.Key().Interface(),
+				Value: iter.Value().Interface(),
+			})
+		}
+		sort.Slice(items, func(i, j int) bool {
+			return fmt.Sprint(items[i].(mapValue).Key) < fmt.Sprint(items[j].(mapValue).Key)
+		})
+		return items, true
+	default:
+		return nil, false
+	}
+}
+
+// isNil reports whether value is null in response.
+// Nil slice and map are not null but empty, e.g. repeated field which has no items is responded as [].
+func isNil(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
+
+// appendPath copies path in order not to share underlying array between sibling fields
+func appendPath(path []interface{}, key interface{}) []interface{} {
+	p := make([]interface{}, len(path), len(path)+1)
+	copy(p, path)
+	return append(p, key)
+}
@@ -0,0 +1,214 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+type testHandler struct {
+	types     Types
+	queries   Fields
+	mutations Fields
+}
+
+func (h *testHandler) CreateConnection(context.Context) (*grpc.ClientConn, func(), error) {
+	return nil, func() {}, nil
+}
+
+func (h *testHandler) GetTypes() Types {
+	return h.types
+}
+
+func (h *testHandler) GetQueries() Fields {
+	return h.queries
+}
+
+func (h *testHandler) GetMutations() Fields {
+	return h.mutations
+}
+
+type testColor int32
+
+type testUser struct {
+	Name    string    `json:"name,omitempty"`
+	Email   string    `json:"email,omitempty"`
+	Color   testColor `json:"color,omitempty"`
+	Friends []*testUser
+}
+
+func newTestHandler() *testHandler {
+	alice := &testUser{Name: "alice", Email: "alice@example.com", Color: 1}
+	bob := &testUser{Name: "bob", Color: 2}
+	alice.Friends = []*testUser{bob, {Name: ""}}
+
+	return &testHandler{
+		types: Types{
+			Definitions: []string{
+				`type User {
+  name: String!
+  email: String
+  color: Color
+  friends: [User!]
+  nickname: String
+}`,
+			},
+			Enums: []*Enum{
+				{
+					Name:       "Color",
+					Definition: "enum Color {\n  RED\n  BLUE\n}",
+					Values: map[string]interface{}{
+						"RED":  testColor(1),
+						"BLUE": testColor(2),
+					},
+				},
+			},
+			Resolvers: map[string]map[string]FieldResolveFn{
+				"User": {
+					"name": func(p ResolveParams) (interface{}, error) {
+						if name := p.Source.(*testUser).Name; name != "" {
+							return name, nil
+						}
+						return nil, nil
+					},
+					"nickname": func(p ResolveParams) (interface{}, error) {
+						panic("not implemented")
+					},
+				},
+			},
+		},
+		queries: Fields{
+			"user": &Field{
+				Args: "name: String!",
+				Type: "User",
+				Resolve: func(p ResolveParams) (interface{}, error) {
+					if p.Args["name"] == "alice" {
+						return alice, nil
+					}
+					return nil, errors.New("user not found")
+				},
+			},
+			"requiredUser": &Field{
+				Type: "User!",
+				Resolve: func(p ResolveParams) (interface{}, error) {
+					return nil, errors.New("backend is down")
+				},
+			},
+			"usersByColor": &Field{
+				Args: "color: Color = RED, limit: Int = 10",
+				Type: "[User]",
+				Resolve: func(p ResolveParams) (interface{}, error) {
+					if p.Args["color"] != testColor(1) || p.Args["limit"] != 2 {
+						return nil, errors.New("unexpected arguments")
+					}
+					return []*testUser{alice}, nil
+				},
+			},
+		},
+	}
+}
+
+func executeTestQuery(t *testing.T, query string, variables map[string]interface{}) (string, []GraphqlError) {
+	schema, err := buildSchema([]GraphqlHandler{newTestHandler()}, false)
+	assert.NoError(t, err)
+	operation, errs := parseOperation(query, schema.Document)
+	assert.Empty(t, errs)
+	data, errs := ExecuteGraphQL(context.Background(), schema, operation, "", variables)
+	return string(data), errs
+}
+
+func TestExecuteGraphQL(t *testing.T) {
+	data, errs := executeTestQuery(t, `{ alice: user(name: "alice") { name email color } }`, nil)
+	assert.Empty(t, errs)
+	assert.Equal(t, `{"alice":{"name":"alice","email":"alice@example.com","color":"RED"}}`, data)
+}
+
+func TestExecuteGraphQLReturnsPartialResult(t *testing.T) {
+	data, errs := executeTestQuery(t, `{
+  alice: user(name: "alice") { name email color }
+  nobody: user(name: "nobody") { name }
+}`, nil)
+
+	assert.JSONEq(t, `{"alice":{"name":"alice","email":"alice@example.com","color":"RED"},"nobody":null}`, data)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "user not found", errs[0].Message)
+	assert.Equal(t, []interface{}{"nobody"}, errs[0].Path)
+	assert.Equal(t, uint32(3), errs[0].Locations[0].Line)
+	assert.Equal(t, uint32(3), errs[0].Locations[0].Column)
+}
+
+func TestExecuteGraphQLPropagatesNull(t *testing.T) {
+	// null of non-null list item makes the list null
+	data, errs := executeTestQuery(t, `{ user(name: "alice") { name friends { name } } }`, nil)
+	assert.JSONEq(t, `{"user":{"name":"alice","friends":null}}`, data)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "Cannot return null for non-nullable field User.name", errs[0].Message)
+	assert.Equal(t, []interface{}{"user", "friends", 1, "name"}, errs[0].Path)
+
+	// null of non-null root field makes data null
+	data, errs = executeTestQuery(t, `{ user(name: "alice") { name } requiredUser { name } }`, nil)
+	assert.Equal(t, "null", data)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "backend is down", errs[0].Message)
+	assert.Equal(t, []interface{}{"requiredUser"}, errs[0].Path)
+}
+
+func TestExecuteGraphQLRespondsEmptyList(t *testing.T) {
+	// friends of bob is nil slice which must not be null
+	data, errs := executeTestQuery(t, `{ user(name: "alice") { friends { friends { email } } } }`, nil)
+	assert.Empty(t, errs)
+	assert.JSONEq(t, `{"user":{"friends":[{"friends":[]},{"friends":[]}]}}`, data)
+}
+
+func TestExecuteGraphQLRecoversPanic(t *testing.T) {
+	data, errs := executeTestQuery(t, `{ user(name: "alice") { name nickname } }`, nil)
+	assert.JSONEq(t, `{"user":{"name":"alice","nickname":null}}`, data)
+	assert.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Message, "panic in resolver of User.nickname")
+}
+
+func TestExecuteGraphQLWithFragmentsAndDirectives(t *testing.T) {
+	data, errs := executeTestQuery(t, `query ($withEmail: Boolean!) {
+  user(name: "alice") {
+    ...userFields
+    ... on User @include(if: $withEmail) { email }
+    color @skip(if: true)
+    __typename
+  }
+}
+fragment userFields on User { name }`, map[string]interface{}{"withEmail": true})
+
+	assert.Empty(t, errs)
+	assert.Equal(t, `{"user":{"name":"alice","email":"alice@example.com","__typename":"User"}}`, data)
+}
+
+func TestExecuteGraphQLCoercesArguments(t *testing.T) {
+	data, errs := executeTestQuery(t, `{ usersByColor(limit: 2) { name } }`, nil)
+	assert.Empty(t, errs)
+	assert.JSONEq(t, `{"usersByColor":[{"name":"alice"}]}`, data)
+
+	data, errs = executeTestQuery(t, `{ usersByColor(limit: "2") { name } }`, nil)
+	assert.JSONEq(t, `{"usersByColor":null}`, data)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, `Argument "limit" has invalid value; Int cannot represent non-integer value: 2`, errs[0].Message)
+}
+
+func TestExecuteGraphQLCoercesVariables(t *testing.T) {
+	data, errs := executeTestQuery(t, `query ($limit: Int) { usersByColor(limit: $limit) { name } }`, map[string]interface{}{"limit": float64(2)})
+	assert.Empty(t, errs)
+	assert.JSONEq(t, `{"usersByColor":[{"name":"alice"}]}`, data)
+
+	data, errs = executeTestQuery(t, `query ($name: String!) { user(name: $name) { name } }`, nil)
+	assert.Empty(t, data)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, `Variable "$name" of required type "String!" was not provided`, errs[0].Message)
+}
+
+func TestExecuteGraphQLIntrospection(t *testing.T) {
+	data, errs := executeTestQuery(t, `{ __type(name: "Color") { name kind } }`, nil)
+	assert.Empty(t, errs)
+	assert.JSONEq(t, `{"__type":{"name":"Color","kind":"ENUM"}}`, data)
+}
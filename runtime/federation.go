@@ -0,0 +1,227 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/wundergraph/graphql-go-tools/pkg/ast"
+)
+
+// federationLink declares the subgraph schema as Apollo Federation v2
+const federationLink = `extend schema @link(url: "https://specs.apollo.dev/federation/v2.0", import: ["@key"])`
+
+// federationTypes are types which Apollo Federation router requires in subgraph
+const federationTypes = `scalar _Any
+
+type _Service {
+  sdl: String
+}`
+
+// EntityResolveFn fetches an entity by its representation which contains __typename and key fields
+type EntityResolveFn func(ctx context.Context, representation map[string]interface{}) (interface{}, error)
+
+// Entity describes an object type which Apollo Federation router can fetch from this subgraph
+type Entity struct {
+	// Object type name of the entity
+	Name string
+	// Field sets which identify the entity, e.g. "id" or "organizationId userId"
+	Keys    []string
+	Resolve EntityResolveFn
+}
+
+// EnableFederation exposes the schema as Apollo Federation v2 subgraph.
+// _service and _entities root fields are added, and entities which handlers declare are fetched through _entities.
+func (s *ServeMux) EnableFederation() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.federation = true
+	if handlers := s.state.Load().handlers; len(handlers) > 0 {
+		return s.swap(handlers)
+	}
+	return nil
+}
+
+// addFederation adds _service and _entities root fields to queries and returns definitions of federation types.
+// definitions are the subgraph schema, which must not contain federation fields.
+func (s *Schema) addFederation(definitions []string, queries Fields, entities map[string]*Entity) []string {
+	names := make([]string, 0, len(entities))
+	for name := range entities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	sdl := make([]string, 0, len(definitions)+len(names)+1)
+	sdl = append(sdl, federationLink)
+	sdl = append(sdl, definitions...)
+	for _, name := range names {
+		for _, key := range entities[name].Keys {
+			sdl = append(sdl, fmt.Sprintf("extend type %s @key(fields: %s)", name, strconv.Quote(key)))
+		}
+	}
+	service := map[string]interface{}{
+		"sdl": strings.Join(sdl, "\n\n"),
+	}
+
+	queries["_service"] = &Field{
+		Type: "_Service!",
+		Resolve: func(p ResolveParams) (interface{}, error) {
+			return service, nil
+		},
+	}
+	added := []string{federationTypes}
+	if len(names) > 0 {
+		added = append(added, "union _Entity = "+strings.Join(names, " | "))
+		queries["_entities"] = &Field{
+			Args:    "representations: [_Any!]!",
+			Type:    "[_Entity]!",
+			Resolve: s.resolveEntities(entities),
+		}
+	}
+	return added
+}
+
+const (
+	// maxBatchSize limits items which a request fetches at once through _entities or nodes
+	maxBatchSize = 100
+	// batchConcurrency limits backend calls in flight for items of a request
+	batchConcurrency = 10
+)
+
+// resolveBatch calls fn for each index of n items by bounded number of workers, and waits all of them.
+func resolveBatch(n int, fn func(i int)) {
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < batchConcurrency && w < n; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
+
+// resolveEntities fetches entities of representations concurrently.
+// Failure of an entity doesn't fail others, it is reported as an error of the item.
+func (s *Schema) resolveEntities(entities map[string]*Entity) FieldResolveFn {
+	return func(p ResolveParams) (interface{}, error) {
+		representations, _ := p.Args["representations"].([]interface{}) // nolint: errcheck
+		if len(representations) > maxBatchSize {
+			return nil, fmt.Errorf("representations must not exceed %d items", maxBatchSize)
+		}
+		results := make([]interface{}, len(representations))
+		resolveBatch(len(representations), func(i int) {
+			results[i] = s.resolveEntity(p.Context, entities, representations[i])
+		})
+		return results, nil
+	}
+}
+
+func (s *Schema) resolveEntity(ctx context.Context, entities map[string]*Entity, r interface{}) (v *typedValue) {
+	representation, ok := r.(map[string]interface{})
+	if !ok {
+		return &typedValue{err: errors.New("Representation must be an object")}
+	}
+	typeName, _ := representation["__typename"].(string) // nolint: errcheck
+	entity, ok := entities[typeName]
+	if !ok {
+		return &typedValue{err: fmt.Errorf("Type \"%s\" is not an entity", typeName)}
+	}
+	representation, err := s.coerceRepresentation(typeName, representation)
+	if err != nil {
+		return &typedValue{err: fmt.Errorf("Invalid representation%s", inputErrorString(typeName, err))}
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			v = &typedValue{err: fmt.Errorf("panic in entity resolver of %s: %v", typeName, r)}
+		}
+	}()
+	value, err := entity.Resolve(ctx, representation)
+	return &typedValue{typeName: typeName, value: value, err: err}
+}
+
+// coerceRepresentation parses key fields of representation by field types of the entity like arguments,
+// because router sends them as _Any, e.g. Int64 key is a string which resolvers can't set to integer field of request.
+// Fields which the type doesn't define are kept as they are.
+func (s *Schema) coerceRepresentation(typeName string, representation map[string]interface{}) (map[string]interface{}, error) {
+	node, ok := s.Document.Index.FirstNodeByNameStr(typeName)
+	if !ok || node.Kind != ast.NodeKindObjectTypeDefinition {
+		return representation, nil
+	}
+	coerced := make(map[string]interface{}, len(representation))
+	for name, value := range representation {
+		ref, ok := s.Document.NodeFieldDefinitionByName(node, []byte(name))
+		if !ok {
+			coerced[name] = value
+			continue
+		}
+		v, err := s.coerceKey(s.Document.FieldDefinitionType(ref), value)
+		if err != nil {
+			return nil, withInputPath(err, name)
+		}
+		coerced[name] = v
+	}
+	return coerced, nil
+}
+
+// coerceKey parses value of key field by its output type, nested objects are key fields of the object
+func (s *Schema) coerceKey(typeRef int, value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	t := s.Document.Types[typeRef]
+	switch t.TypeKind {
+	case ast.TypeKindNonNull:
+		return s.coerceKey(t.OfType, value)
+	case ast.TypeKindList:
+		items, ok := value.([]interface{})
+		if !ok {
+			return s.coerceKey(t.OfType, value)
+		}
+		list := make([]interface{}, len(items))
+		for i, item := range items {
+			v, err := s.coerceKey(t.OfType, item)
+			if err != nil {
+				return nil, withInputPath(err, i)
+			}
+			list[i] = v
+		}
+		return list, nil
+	}
+
+	typeName := s.Document.TypeNameString(typeRef)
+	node, ok := s.Document.Index.FirstNodeByNameStr(typeName)
+	if !ok {
+		return value, nil
+	}
+	switch node.Kind {
+	case ast.NodeKindObjectTypeDefinition:
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Expected value of type \"%s\" to be an object", typeName)
+		}
+		return s.coerceRepresentation(typeName, fields)
+	case ast.NodeKindEnumTypeDefinition:
+		name, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("Enum \"%s\" cannot represent non-string value: %v", typeName, value)
+		}
+		return (&executor{schema: s}).parseEnum(node, name)
+	case ast.NodeKindScalarTypeDefinition:
+		return parseScalar(typeName, value)
+	}
+	return value, nil
+}
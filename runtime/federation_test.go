@@ -0,0 +1,134 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func newEntityTestHandler() *testHandler {
+	h := newTestHandler()
+	h.types.Entities = []*Entity{
+		{
+			Name: "User",
+			Keys: []string{"name"},
+			Resolve: func(ctx context.Context, representation map[string]interface{}) (interface{}, error) {
+				switch representation["name"] {
+				case "alice":
+					return &testUser{Name: "alice", Email: "alice@example.com"}, nil
+				case "carol":
+					return nil, nil
+				}
+				return nil, errors.New("user not found")
+			},
+		},
+	}
+	return h
+}
+
+func TestServeMuxFederation(t *testing.T) {
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(newEntityTestHandler()))
+	assert.Contains(t, serveTestQuery(mux, "{ _service { sdl } }"), `field: _service not defined on type: Query`)
+
+	assert.NoError(t, mux.EnableFederation())
+	schema := mux.CurrentSchema()
+	assert.NotNil(t, schema)
+	sdl, err := schema.resolvers["Query"]["_service"](ResolveParams{})
+	assert.NoError(t, err)
+	assert.Contains(t, sdl.(map[string]interface{})["sdl"], `extend schema @link(url: "https://specs.apollo.dev/federation/v2.0", import: ["@key"])`)
+	assert.Contains(t, sdl.(map[string]interface{})["sdl"], `extend type User @key(fields: "name")`)
+	assert.NotContains(t, sdl.(map[string]interface{})["sdl"], "_entities")
+
+	assert.JSONEq(t, `{
+		"data": {"_entities": [{"__typename": "User", "name": "alice", "email": "alice@example.com"}, null, null]},
+		"errors": [{"message": "user not found", "path": ["_entities", 2], "locations": [{"line": 1, "column": 3}]}]
+	}`, serveTestQuery(mux, `{ _entities(representations: [{__typename: \"User\", name: \"alice\"}, {__typename: \"User\", name: \"carol\"}, {__typename: \"User\", name: \"dave\"}]) { __typename ... on User { name email } } }`))
+}
+
+func TestServeMuxFederationRejectsUndefinedEntity(t *testing.T) {
+	mux := NewServeMux()
+	assert.NoError(t, mux.EnableFederation())
+	h := newVersionHandler("v1")
+	h.types.Entities = []*Entity{{Name: "User", Keys: []string{"id"}}}
+	assert.EqualError(t, mux.AddHandler(h), `schema validation error: entity "User" of handler #0 (*runtime.testHandler) is not a defined object type`)
+}
+
+func TestServeMuxFederationCoercesKeys(t *testing.T) {
+	h := newVersionHandler("v1")
+	h.types.Definitions = append(h.types.Definitions, "scalar Int64", "type Counter {\n  value: Int64\n}")
+	h.types.Entities = []*Entity{
+		{
+			Name: "Counter",
+			Keys: []string{"value"},
+			Resolve: func(ctx context.Context, representation map[string]interface{}) (interface{}, error) {
+				// generated resolvers unmarshal the representation into request message
+				var req wrapperspb.Int64Value
+				if err := MarshalRequest(representation, &req, false); err != nil {
+					return nil, err
+				}
+				return &req, nil
+			},
+		},
+	}
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(h))
+	assert.NoError(t, mux.EnableFederation())
+
+	// router sends Int64 key as string which is serialized so
+	assert.JSONEq(t, `{"data":{"_entities":[{"value":"9007199254740993"},{"value":"1"}]}}`,
+		serveTestQuery(mux, `{ _entities(representations: [{__typename: \"Counter\", value: \"9007199254740993\"}, {__typename: \"Counter\", value: 1}]) { ... on Counter { value } } }`))
+	assert.JSONEq(t, `{
+		"data": {"_entities": [null]},
+		"errors": [{"message": "Invalid representation at \"Counter.value\"; Int64 cannot represent value: one", "path": ["_entities", 0], "locations": [{"line": 1, "column": 3}]}]
+	}`, serveTestQuery(mux, `{ _entities(representations: [{__typename: \"Counter\", value: \"one\"}]) { ... on Counter { value } } }`))
+}
+
+func TestResolveEntitiesBoundsConcurrency(t *testing.T) {
+	var inFlight, peak int32
+	entities := map[string]*Entity{
+		"User": {
+			Name: "User",
+			Resolve: func(ctx context.Context, representation map[string]interface{}) (interface{}, error) {
+				n := atomic.AddInt32(&inFlight, 1)
+				defer atomic.AddInt32(&inFlight, -1)
+				for {
+					p := atomic.LoadInt32(&peak)
+					if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+						break
+					}
+				}
+				time.Sleep(time.Millisecond)
+				return representation, nil
+			},
+		},
+	}
+	representations := make([]interface{}, maxBatchSize)
+	for i := range representations {
+		representations[i] = map[string]interface{}{"__typename": "User"}
+	}
+
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(newEntityTestHandler()))
+	assert.NoError(t, mux.EnableFederation())
+	schema := mux.CurrentSchema()
+
+	results, err := schema.resolveEntities(entities)(ResolveParams{
+		Context: context.Background(),
+		Args:    map[string]interface{}{"representations": representations},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, results, maxBatchSize)
+	assert.LessOrEqual(t, peak, int32(batchConcurrency))
+
+	_, err = schema.resolveEntities(entities)(ResolveParams{
+		Context: context.Background(),
+		Args:    map[string]interface{}{"representations": append(representations, representations[0])},
+	})
+	assert.EqualError(t, err, "representations must not exceed 100 items")
+}
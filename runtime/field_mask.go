@@ -0,0 +1,53 @@
+package runtime
+
+import (
+	"strings"
+
+	"github.com/iancoleman/strcase"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// NewFieldMask builds FieldMask of the request from fields which are selected on the resolving field.
+// GraphQL names are converted to proto names when isCamel is true,
+// and paths are prefixed with the response field which is plucked, e.g. "user.name".
+func NewFieldMask(info ResolveInfo, prefix string, isCamel bool) *fieldmaskpb.FieldMask {
+	return fieldMask(info.SelectedFields(), prefix, isCamel)
+}
+
+// FieldMask builds FieldMask from fields which are selected on nodes of the connection,
+// paths are prefixed with the items field of List response.
+func (p *Pagination) FieldMask(info ResolveInfo, items string, isCamel bool) *fieldmaskpb.FieldMask {
+	var fields []string
+	for _, f := range info.SelectedFields() {
+		if strings.HasPrefix(f, "edges.node.") {
+			fields = append(fields, strings.TrimPrefix(f, "edges.node."))
+		}
+	}
+	return fieldMask(fields, items, isCamel)
+}
+
+// NewUpdateMask builds FieldMask of partial update from input fields which the client provides under the argument path,
+// say "input.user", so that the backend can tell omitted fields from fields which are set to zero value or null.
+func NewUpdateMask(info ResolveInfo, argument string, isCamel bool) *fieldmaskpb.FieldMask {
+	return fieldMask(info.ProvidedFields(argument), "", isCamel)
+}
+
+// fieldMask converts dotted GraphQL names to proto paths.
+// Empty mask is sent when no field of message is selected, which means all fields in AIP-157.
+func fieldMask(fields []string, prefix string, isCamel bool) *fieldmaskpb.FieldMask {
+	mask := &fieldmaskpb.FieldMask{}
+	for _, f := range fields {
+		if isCamel {
+			names := strings.Split(f, ".")
+			for i, name := range names {
+				names[i] = strcase.ToSnake(name)
+			}
+			f = strings.Join(names, ".")
+		}
+		if prefix != "" {
+			f = prefix + "." + f
+		}
+		mask.Paths = append(mask.Paths, f)
+	}
+	return mask
+}
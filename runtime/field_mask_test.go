@@ -0,0 +1,115 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+func newFieldMaskTestHandler(masks map[string]*fieldmaskpb.FieldMask) *testHandler {
+	return &testHandler{
+		types: Types{
+			Definitions: []string{
+				"type Address {\n  city: String\n  zipCode: String\n}",
+				"type Post {\n  title: String\n}",
+				"type Comment {\n  body: String\n}",
+				"union Activity = Post | Comment",
+				"type User {\n  name: String\n  homeAddress: Address\n  posts: [Post]\n  activity: Activity\n}",
+				"type PostEdge {\n  node: Post\n  cursor: String!\n}",
+				"type PostConnection {\n  edges: [PostEdge!]!\n  pageInfo: PageInfo!\n}",
+				"type PageInfo {\n  hasNextPage: Boolean!\n  hasPreviousPage: Boolean!\n  startCursor: String\n  endCursor: String\n}",
+				"input AddressInput {\n  city: String\n  zipCode: String\n}",
+				"input UserInput {\n  name: String\n  homeAddress: AddressInput\n  tags: [String]\n  role: String = \"member\"\n}",
+				"input UpdateUserInput {\n  user: UserInput\n  validateOnly: Boolean\n}",
+			},
+		},
+		mutations: Fields{
+			"updateUser": &Field{
+				Args: "input: UpdateUserInput!",
+				Type: "User",
+				Resolve: func(p ResolveParams) (interface{}, error) {
+					masks["updateUser"] = NewUpdateMask(p.Info, "input.user", true)
+					masks["updateUser.args"] = NewUpdateMask(p.Info, "", true)
+					return map[string]interface{}{}, nil
+				},
+			},
+		},
+		queries: Fields{
+			"user": &Field{
+				Type: "User",
+				Resolve: func(p ResolveParams) (interface{}, error) {
+					masks["user"] = NewFieldMask(p.Info, "", true)
+					return map[string]interface{}{}, nil
+				},
+			},
+			"posts": &Field{
+				Args: "first: Int, after: String",
+				Type: "PostConnection",
+				Resolve: func(p ResolveParams) (interface{}, error) {
+					pagination, err := NewPagination(p.Args)
+					if err != nil {
+						return nil, err
+					}
+					masks["posts"] = pagination.FieldMask(p.Info, "posts", true)
+					return pagination.Connection(nil, "")
+				},
+			},
+		},
+	}
+}
+
+func TestFieldMask(t *testing.T) {
+	masks := make(map[string]*fieldmaskpb.FieldMask)
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(newFieldMaskTestHandler(masks)))
+
+	serveTestQuery(mux, `{ user { __typename name alias: name homeAddress { city } ...UserFields activity { ... on Post { title } ... on Comment { body } } } } `+
+		`fragment UserFields on User { homeAddress { zipCode } posts { title } }`)
+	assert.Equal(t, []string{"name", "home_address.city", "home_address.zip_code", "posts.title", "activity"}, masks["user"].GetPaths())
+
+	serveTestQuery(mux, `{ user { name @skip(if: true) posts { title } } }`)
+	assert.Equal(t, []string{"posts.title"}, masks["user"].GetPaths())
+
+	// only fields of nodes are requested from the backend
+	serveTestQuery(mux, `{ posts(first: 1) { edges { cursor node { title } } pageInfo { hasNextPage } } }`)
+	assert.Equal(t, []string{"posts.title"}, masks["posts"].GetPaths())
+}
+
+func TestFieldMaskScalar(t *testing.T) {
+	mask, err := parseFieldMask("name, address.city,")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"name", "address.city"}, mask.(*fieldmaskpb.FieldMask).GetPaths())
+
+	for _, v := range []interface{}{
+		mask,
+		MarshalResponse(mask),
+		"name,address.city",
+	} {
+		s, err := serializeFieldMask(v)
+		assert.NoError(t, err)
+		assert.Equal(t, "name,address.city", s)
+	}
+}
+
+func TestUpdateMask(t *testing.T) {
+	masks := make(map[string]*fieldmaskpb.FieldMask)
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(newFieldMaskTestHandler(masks)))
+
+	// explicit null is provided to clear the field, while default value is not
+	serveTestQuery(mux, `mutation { updateUser(input: { user: { name: null, homeAddress: { city: \"Tokyo\" }, tags: [] } }) { name } }`)
+	assert.Equal(t, []string{"name", "home_address.city", "tags"}, masks["updateUser"].GetPaths())
+	assert.Equal(t, []string{"input.user.name", "input.user.home_address.city", "input.user.tags"}, masks["updateUser.args"].GetPaths())
+
+	// variables which are not provided are omitted
+	serveTestRequest(mux, `{"query":"mutation ($name: String, $address: AddressInput) { updateUser(input: { user: { name: $name, homeAddress: $address }, validateOnly: true }) { name } }",`+
+		`"variables":{"address":{"zipCode":null}}}`)
+	assert.Equal(t, []string{"home_address.zip_code"}, masks["updateUser"].GetPaths())
+
+	serveTestRequest(mux, `{"query":"mutation ($input: UpdateUserInput!) { updateUser(input: $input) { name } }","variables":{"input":{"user":{"homeAddress":null}}}}`)
+	assert.Equal(t, []string{"home_address"}, masks["updateUser"].GetPaths())
+
+	serveTestQuery(mux, `mutation { updateUser(input: { validateOnly: true }) { name } }`)
+	assert.Empty(t, masks["updateUser"].GetPaths())
+}
@@ -0,0 +1,112 @@
+package runtime
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+
+	"google.golang.org/grpc/codes"
+)
+
+const defaultMaskedErrorMessage = "Internal server error"
+
+// Status codes whose message is exposed by default.
+// They describe problems of client request, not internal details of backend.
+var defaultUnmaskedCodes = []codes.Code{
+	codes.InvalidArgument,
+	codes.NotFound,
+	codes.AlreadyExists,
+	codes.PermissionDenied,
+	codes.FailedPrecondition,
+	codes.OutOfRange,
+	codes.Unauthenticated,
+}
+
+// ErrorMasking hides internal error messages from clients.
+// Masked error is responded with generic message and "errorId" extension,
+// and the original error is logged with the same ID so that it can be traced from the response.
+type ErrorMasking struct {
+	// Status codes which keep their message.
+	// Default is INVALID_ARGUMENT, NOT_FOUND, ALREADY_EXISTS, PERMISSION_DENIED,
+	// FAILED_PRECONDITION, OUT_OF_RANGE and UNAUTHENTICATED.
+	AllowedCodes []codes.Code
+	// Message of masked error, default is "Internal server error"
+	Message string
+	// Logger receives masked error with its ID, default writes to standard logger
+	Logger func(id string, err GraphqlError)
+}
+
+// mask replaces message and extensions of errors which are not allowlisted
+func (m *ErrorMasking) mask(errs []GraphqlError) {
+	for i := range errs {
+		if m.isAllowed(errs[i]) {
+			continue
+		}
+		id := newErrorID()
+		m.log(id, errs[i])
+
+		errs[i].Message = m.message()
+		errs[i].Extensions = map[string]interface{}{
+			"errorId": id,
+		}
+	}
+}
+
+// isAllowed decides exposure by the kind of error, so that it doesn't depend on extensions of GraphqlErrorHandler.
+// Errors without original error are raised by the request itself like syntax or variable errors, which are exposed.
+// Errors from resolvers are exposed by the code of gRPC status found through the wrapping chain,
+// and only errors without status fall back to "code" extension which custom GraphqlErrorHandler may add.
+func (m *ErrorMasking) isAllowed(e GraphqlError) bool {
+	err := e.OriginalError()
+	if err == nil {
+		return true
+	}
+
+	var code string
+	if st, ok := grpcStatusFromError(err); ok {
+		code = CodeName(st.Code())
+	} else if c, ok := e.Extensions["code"].(string); ok {
+		code = c
+	} else {
+		return false
+	}
+
+	allowed := m.AllowedCodes
+	if len(allowed) == 0 {
+		allowed = defaultUnmaskedCodes
+	}
+	for _, c := range allowed {
+		if CodeName(c) == code {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *ErrorMasking) message() string {
+	if m.Message == "" {
+		return defaultMaskedErrorMessage
+	}
+	return m.Message
+}
+
+func (m *ErrorMasking) log(id string, e GraphqlError) {
+	if m.Logger != nil {
+		m.Logger(id, e)
+		return
+	}
+	if err := e.OriginalError(); err != nil {
+		log.Printf("[ERROR] GraphQL error %s: %+v\n", id, err)
+		return
+	}
+	log.Printf("[ERROR] GraphQL error %s: %s\n", id, e.Message)
+}
+
+// newErrorID generates opaque random ID which doesn't contain any information of the error
+func newErrorID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,94 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestErrorMaskingKeepsAllowedCodes(t *testing.T) {
+	errs := []GraphqlError{NewGraphqlError(status.Error(codes.NotFound, "user not found"))}
+	defaultGraphqlErrorHandler(errs)
+
+	m := &ErrorMasking{
+		Logger: func(id string, err GraphqlError) {
+			t.Errorf("unexpected masking: %s", err.Message)
+		},
+	}
+	m.mask(errs)
+
+	assert.Equal(t, "user not found", errs[0].Message)
+	assert.Equal(t, "NOT_FOUND", errs[0].Extensions["code"])
+}
+
+func TestErrorMaskingHidesInternalErrors(t *testing.T) {
+	original := status.Error(codes.Internal, "pq: relation \"users\" does not exist")
+	errs := []GraphqlError{
+		NewGraphqlError(original),
+		NewGraphqlError(errors.New("panic: runtime error")),
+	}
+	errs[0].Path = []interface{}{"user"}
+	defaultGraphqlErrorHandler(errs)
+
+	logged := make(map[string]error)
+	m := &ErrorMasking{
+		Message: "Something went wrong",
+		Logger: func(id string, err GraphqlError) {
+			logged[id] = err.OriginalError()
+		},
+	}
+	m.mask(errs)
+
+	for _, e := range errs {
+		assert.Equal(t, "Something went wrong", e.Message)
+		assert.Len(t, e.Extensions, 1)
+		id, ok := e.Extensions["errorId"].(string)
+		assert.True(t, ok)
+		assert.Len(t, id, 32)
+		assert.Contains(t, logged, id)
+	}
+	assert.Equal(t, original, logged[errs[0].Extensions["errorId"].(string)])
+	assert.Equal(t, []interface{}{"user"}, errs[0].Path)
+	assert.NotEqual(t, errs[0].Extensions["errorId"], errs[1].Extensions["errorId"])
+}
+
+func TestErrorMaskingWithCustomAllowedCodes(t *testing.T) {
+	errs := []GraphqlError{
+		NewGraphqlError(status.Error(codes.NotFound, "user not found")),
+		NewGraphqlError(status.Error(codes.Unavailable, "backend is restarting")),
+	}
+	defaultGraphqlErrorHandler(errs)
+
+	m := &ErrorMasking{
+		AllowedCodes: []codes.Code{codes.Unavailable},
+		Logger:       func(id string, err GraphqlError) {},
+	}
+	m.mask(errs)
+
+	assert.Equal(t, defaultMaskedErrorMessage, errs[0].Message)
+	assert.Equal(t, "backend is restarting", errs[1].Message)
+}
+
+func TestErrorMaskingWithCustomErrorHandler(t *testing.T) {
+	// Custom GraphqlErrorHandler doesn't add "code" extension
+	errs := []GraphqlError{
+		NewGraphqlError(fmt.Errorf("Failed to call RPC GetUser: %w", status.Error(codes.NotFound, "user not found"))),
+		NewGraphqlError(status.Error(codes.Unavailable, "circuit breaker is open for backend localhost:50051")),
+		NewGraphqlError(status.Error(codes.Internal, "pq: connection refused")),
+		{Message: `Variable "$name" of required type "String!" was not provided`},
+	}
+	m := &ErrorMasking{
+		AllowedCodes: []codes.Code{codes.NotFound, codes.Unavailable},
+		Logger:       func(id string, err GraphqlError) {},
+	}
+	m.mask(errs)
+
+	assert.Equal(t, "Failed to call RPC GetUser: rpc error: code = NotFound desc = user not found", errs[0].Message)
+	assert.Equal(t, "rpc error: code = Unavailable desc = circuit breaker is open for backend localhost:50051", errs[1].Message)
+	assert.Equal(t, defaultMaskedErrorMessage, errs[2].Message)
+	assert.Equal(t, `Variable "$name" of required type "String!" was not provided`, errs[3].Message)
+}
@@ -2,7 +2,13 @@ package runtime
 
 import (
 	"context"
+	"crypto/subtle"
+	"math"
+	"net"
 	"net/http"
+	"strings"
+	"sync"
+	"time"
 )
 
 type MiddlewareError struct {
@@ -31,3 +37,125 @@ func Cors() MiddlewareFunc {
 		return ctx, nil
 	}
 }
+
+// CorsWithOrigins provides CORS headers only when request origin is allowed.
+// "*" allows any origin without credentials, credentials are allowed only for listed origins.
+func CorsWithOrigins(origins ...string) MiddlewareFunc {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return ctx, nil
+		}
+		switch {
+		case containsString(origins, origin):
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Access-Control-Allow-Credentials", "true")
+			w.Header().Add("Vary", "Origin")
+		case containsString(origins, "*"):
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		default:
+			return ctx, nil
+		}
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+		w.Header().Set("Access-Control-Max-Age", "1728000")
+		return ctx, nil
+	}
+}
+
+func containsString(values []string, v string) bool {
+	for _, s := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// BearerAuth rejects requests which don't have one of tokens in Authorization header of "Bearer" scheme.
+// Empty tokens are never accepted.
+func BearerAuth(tokens ...string) MiddlewareFunc {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		const prefix = "Bearer "
+		header := r.Header.Get("Authorization")
+		if len(header) > len(prefix) && strings.EqualFold(header[:len(prefix)], prefix) {
+			token := header[len(prefix):]
+			for _, t := range tokens {
+				if t != "" && subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+					return ctx, nil
+				}
+			}
+		}
+		return ctx, NewMiddlewareError("UNAUTHENTICATED", "Invalid or missing bearer token")
+	}
+}
+
+// RateLimit limits requests per client IP address by token bucket,
+// which is refilled by rps tokens per second up to burst.
+func RateLimit(rps float64, burst int) MiddlewareFunc {
+	l := &rateLimiter{
+		rps:     rps,
+		burst:   float64(burst),
+		buckets: make(map[string]*tokenBucket),
+		now:     time.Now,
+	}
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		if !l.allow(clientIP(r)) {
+			return ctx, NewMiddlewareError("RATE_LIMITED", "Too many requests")
+		}
+		return ctx, nil
+	}
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+type rateLimiter struct {
+	mu      sync.Mutex
+	rps     float64
+	burst   float64
+	buckets map[string]*tokenBucket
+	sweptAt time.Time
+	now     func() time.Time
+}
+
+func (l *rateLimiter) allow(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	// drop buckets which have been refilled in order not to grow the map unlimitedly,
+	// at most once per the time to refill a bucket so that requests don't scan all buckets each time
+	if now.Sub(l.sweptAt).Seconds()*l.rps >= l.burst {
+		for k, v := range l.buckets {
+			if now.Sub(v.last).Seconds()*l.rps >= l.burst {
+				delete(l.buckets, k)
+			}
+		}
+		l.sweptAt = now
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rps)
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,110 @@
+package runtime
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorsWithOrigins(t *testing.T) {
+	cors := CorsWithOrigins("https://example.com")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/graphql", nil)
+	r.Header.Set("Origin", "https://example.com")
+	_, err := cors(context.Background(), w, r)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://example.com", w.Header().Get("Access-Control-Allow-Origin"))
+
+	w = httptest.NewRecorder()
+	r.Header.Set("Origin", "https://evil.example")
+	_, err = cors(context.Background(), w, r)
+	assert.NoError(t, err)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestCorsWithWildcardOrigin(t *testing.T) {
+	cors := CorsWithOrigins("*", "https://example.com")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/graphql", nil)
+	r.Header.Set("Origin", "https://any.example")
+	_, err := cors(context.Background(), w, r)
+	assert.NoError(t, err)
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+
+	w = httptest.NewRecorder()
+	r.Header.Set("Origin", "https://example.com")
+	_, err = cors(context.Background(), w, r)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+}
+
+func TestBearerAuth(t *testing.T) {
+	auth := BearerAuth("secret")
+	r := httptest.NewRequest("POST", "/graphql", nil)
+
+	_, err := auth(context.Background(), httptest.NewRecorder(), r)
+	assert.Equal(t, NewMiddlewareError("UNAUTHENTICATED", "Invalid or missing bearer token"), err)
+
+	r.Header.Set("Authorization", "Bearer secret")
+	_, err = auth(context.Background(), httptest.NewRecorder(), r)
+	assert.NoError(t, err)
+
+	for _, header := range []string{"secret", "Basic secret", "Bearer ", "Bearer"} {
+		r.Header.Set("Authorization", header)
+		_, err = auth(context.Background(), httptest.NewRecorder(), r)
+		assert.Error(t, err, header)
+	}
+}
+
+func TestBearerAuthRejectsEmptyToken(t *testing.T) {
+	auth := BearerAuth("")
+	r := httptest.NewRequest("POST", "/graphql", nil)
+	r.Header.Set("Authorization", "Bearer ")
+
+	_, err := auth(context.Background(), httptest.NewRecorder(), r)
+	assert.Error(t, err)
+}
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := &rateLimiter{
+		rps:     1,
+		burst:   2,
+		buckets: make(map[string]*tokenBucket),
+		now:     func() time.Time { return now },
+	}
+
+	assert.True(t, l.allow("10.0.0.1"))
+	assert.True(t, l.allow("10.0.0.1"))
+	assert.False(t, l.allow("10.0.0.1"))
+	assert.True(t, l.allow("10.0.0.2"))
+
+	now = now.Add(time.Second)
+	assert.True(t, l.allow("10.0.0.1"))
+	assert.False(t, l.allow("10.0.0.1"))
+
+	// refilled buckets are dropped once the time to refill a bucket has passed since the last sweep
+	now = now.Add(time.Minute)
+	assert.True(t, l.allow("10.0.0.3"))
+	assert.Len(t, l.buckets, 1)
+	now = now.Add(time.Second)
+	assert.True(t, l.allow("10.0.0.4"))
+	assert.Len(t, l.buckets, 2)
+	now = now.Add(time.Second)
+	assert.True(t, l.allow("10.0.0.4"))
+	assert.Len(t, l.buckets, 1)
+}
+
+func TestRateLimitRespondsError(t *testing.T) {
+	mux := NewServeMux(RateLimit(1, 1))
+	assert.NoError(t, mux.AddHandler(newVersionHandler("v1")))
+	assert.Equal(t, `{"data":{"version":"v1"}}`, serveTestQuery(mux, "{ version }"))
+	assert.Equal(t, `{"errors":[{"message":"Too many requests","extensions":{"code":"RATE_LIMITED"}}]}`, serveTestQuery(mux, "{ version }"))
+}
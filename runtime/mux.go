@@ -3,112 +3,153 @@ package runtime
 import (
 	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"net/http"
+	"sync"
+	"sync/atomic"
 
 	"github.com/wundergraph/graphql-go-tools/pkg/ast"
 	"github.com/wundergraph/graphql-go-tools/pkg/astnormalization"
 	"github.com/wundergraph/graphql-go-tools/pkg/astparser"
-	"github.com/wundergraph/graphql-go-tools/pkg/asttransform"
 	"github.com/wundergraph/graphql-go-tools/pkg/operationreport"
 	"google.golang.org/grpc"
 )
 
 type MiddlewareFunc func(ctx context.Context, w http.ResponseWriter, r *http.Request) (context.Context, error)
 
+// GraphqlHandler provides types and root fields of a gRPC service.
+// Resolvers of root fields create connection to the backend by CreateConnection on each call.
 type GraphqlHandler interface {
 	CreateConnection(context.Context) (*grpc.ClientConn, func(), error)
-	GetMutations(*grpc.ClientConn) map[string]*ast.FieldDefinition
-	GetQueries(*grpc.ClientConn) map[string]*ast.FieldDefinition
+	GetTypes() Types
+	GetQueries() Fields
+	GetMutations() Fields
 }
 
 type ServeMux struct {
-	middlewares  []MiddlewareFunc
-	Schema       *ast.Document
+	middlewares []MiddlewareFunc
+	// Schema is merged from registered handlers, nil if no handler is registered.
+	//
+	// Deprecated: Schema is updated without synchronization when handlers are reloaded, use CurrentSchema instead.
+	Schema       *Schema
 	ErrorHandler GraphqlErrorHandler
+	// CircuitBreakers guards backend calls per host, nil disables circuit breaking
+	CircuitBreakers *CircuitBreakers
+	// ErrorMasking hides internal error messages from clients, nil responds errors as they are
+	ErrorMasking *ErrorMasking
+	// TrustedDocuments allows only the listed operations, nil accepts any query
+	TrustedDocuments *TrustedDocuments
+	// DocumentCache reuses parsed and validated operations of the same query, nil parses every request
+	DocumentCache *DocumentCache
 
-	handlers []GraphqlHandler
+	// federation serves the schema as Apollo Federation subgraph, see EnableFederation
+	federation bool
+
+	// mu serializes updates of handlers, requests read state without locking
+	mu    sync.Mutex
+	state atomic.Pointer[muxState]
+}
+
+// muxState is a set of registered handlers, the schema merged from them and rules to validate operations.
+// It is never modified after stored so that in-flight requests keep using consistent one.
+type muxState struct {
+	handlers   []GraphqlHandler
+	schema     *Schema
+	validation *operationValidation
+	// version is incremented on every update, cached operations of previous versions are never used
+	version uint64
 }
 
 func NewServeMux(ms ...MiddlewareFunc) *ServeMux {
-	return &ServeMux{
+	s := &ServeMux{
 		middlewares: ms,
-		handlers:    make([]GraphqlHandler, 0),
 	}
+	validation, _ := newOperationValidation(nil) // nolint: errcheck
+	s.state.Store(&muxState{validation: validation})
+	return s
 }
 
-func (s *ServeMux) AddHandler(h GraphqlHandler) error {
-	if err := s.validateHandler(h); err != nil {
-		return err
-	}
-	s.handlers = append(s.handlers, h)
-	return nil
+// CurrentSchema returns current schema, or nil if no handler is registered.
+// It is safe to call while handlers are reloaded.
+func (s *ServeMux) CurrentSchema() *Schema {
+	return s.state.Load().schema
 }
 
-func buildSchema(queries, mutations map[string]*ast.FieldDefinition) (*ast.Document, error) {
-	document := ast.NewDocument()
-
-	if len(queries) > 0 {
-		queryType := ast.ObjectTypeDefinition{
-			Description: ast.Description{
-				IsDefined:     true,
-				IsBlockString: false,
-				Content:       document.Input.AppendInputBytes([]byte("The query root of the schema.")),
-			},
-			Name: document.Input.AppendInputString("Query"),
-		}
-		queryTypeIndex := document.AddObjectTypeDefinition(queryType)
-		for name, field := range queries {
-			fieldDef := document.AddFieldDefinition(ast.FieldDefinition{
-				Name: document.Input.AppendInputString(name),
-				Type: field.Type,
-			})
-			document.ObjectTypeDefinitions[queryTypeIndex].FieldsDefinition.Refs = append(document.ObjectTypeDefinitions[queryTypeIndex].FieldsDefinition.Refs, fieldDef)
-		}
+// AddHandler registers handler and rebuilds the schema.
+// The handler is rejected when merged schema is invalid.
+func (s *ServeMux) AddHandler(h GraphqlHandler, opts ...HandlerOption) error {
+	for _, o := range opts {
+		h = o(h)
 	}
 
-	if len(mutations) > 0 {
-		mutationType := ast.ObjectTypeDefinition{
-			Description: ast.Description{
-				IsDefined:     true,
-				IsBlockString: false,
-				Content:       document.Input.AppendInputBytes([]byte("The mutation root of the schema.")),
-			},
-			Name: document.Input.AppendInputString("Mutation"),
-		}
-		mutationTypeIndex := document.AddObjectTypeDefinition(mutationType)
-		for name, field := range mutations {
-			fieldDef := document.AddFieldDefinition(ast.FieldDefinition{
-				Name: document.Input.AppendInputString(name),
-				Type: field.Type,
-			})
-			document.ObjectTypeDefinitions[mutationTypeIndex].FieldsDefinition.Refs = append(document.ObjectTypeDefinitions[mutationTypeIndex].FieldsDefinition.Refs, fieldDef)
-		}
-	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	return document, nil
+	current := s.state.Load().handlers
+	handlers := make([]GraphqlHandler, 0, len(current)+1)
+	handlers = append(handlers, current...)
+	return s.swap(append(handlers, h))
 }
 
-func (s *ServeMux) validateHandler(h GraphqlHandler) error {
-	queries := h.GetQueries(nil)
-	mutations := h.GetMutations(nil)
+// RemoveHandler unregisters handler which is compared by identity, and rebuilds the schema.
+// Requests which are already running continue to be executed on the previous schema.
+func (s *ServeMux) RemoveHandler(h GraphqlHandler) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	if len(queries) == 0 && len(mutations) == 0 {
-		return nil
+	current := s.state.Load().handlers
+	handlers := make([]GraphqlHandler, 0, len(current))
+	for _, v := range current {
+		if v != h && unwrapHandler(v) != h {
+			handlers = append(handlers, v)
+		}
+	}
+	if len(handlers) == len(current) {
+		return errors.New("handler is not registered")
 	}
+	return s.swap(handlers)
+}
+
+// ReplaceHandlers replaces all handlers at once, e.g. to reload backend definitions without restart.
+// When the schema can't be built from new handlers, current handlers are kept and error is returned.
+func (s *ServeMux) ReplaceHandlers(hs ...GraphqlHandler) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	handlers := make([]GraphqlHandler, len(hs))
+	copy(handlers, hs)
+	return s.swap(handlers)
+}
 
-	document, err := buildSchema(queries, mutations)
+// swap builds schema from handlers and stores them atomically.
+// Handlers can't be emptied because the mux would fail every request afterwards.
+// Caller must hold s.mu.
+func (s *ServeMux) swap(handlers []GraphqlHandler) error {
+	current := s.state.Load()
+	if len(handlers) == 0 {
+		return errors.New("at least one handler must be registered to serve the schema")
+	}
+	schema, err := buildSchema(handlers, s.federation)
 	if err != nil {
-		return fmt.Errorf("schema validation error: %s", err)
+		return err
 	}
+	s.store(&muxState{
+		handlers:   handlers,
+		schema:     schema,
+		validation: current.validation,
+	})
+	return nil
+}
 
-	report := &operationreport.Report{}
-	asttransform.MergeDefinitionWithBaseSchema(document)
-	if report.HasErrors() {
-		return fmt.Errorf("schema validation error: %s", report.Error())
+// store stores next state with new version and drops cached operations of previous state.
+// Caller must hold s.mu.
+func (s *ServeMux) store(next *muxState) {
+	next.version = s.state.Load().version + 1
+	s.state.Store(next)
+	s.Schema = next.schema
+	if s.DocumentCache != nil {
+		s.DocumentCache.Purge()
 	}
-
-	return nil
 }
 
 func (s *ServeMux) Use(ms ...MiddlewareFunc) *ServeMux {
@@ -117,26 +158,56 @@ func (s *ServeMux) Use(ms ...MiddlewareFunc) *ServeMux {
 }
 
 func (s *ServeMux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	var params struct {
-		Query         string                 `json:"query"`
-		OperationName string                 `json:"operationName"`
-		Variables     map[string]interface{} `json:"variables"`
+	ctx := r.Context()
+	for _, m := range s.middlewares {
+		var err error
+		if ctx, err = m(ctx, w, r); err != nil {
+			ge := NewGraphqlError(err)
+			if me, ok := err.(*MiddlewareError); ok {
+				ge.Extensions = map[string]interface{}{
+					"code": me.Code,
+				}
+			}
+			respondResult(w, nil, []GraphqlError{ge})
+			return
+		}
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+	req, err := parseRequest(r)
+	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	// Parse the query
-	operation, parseErrors := parseOperation(s.Schema)
-	if len(parseErrors) > 0 {
-		respondWithErrors(w, parseErrors)
+	if s.TrustedDocuments != nil {
+		query, ge := s.TrustedDocuments.resolve(req)
+		if ge != nil {
+			respondResult(w, nil, []GraphqlError{*ge})
+			return
+		}
+		req.Query = query
+	}
+
+	// Keep using the same schema until the end of this request even if handlers are reloaded
+	state := s.state.Load()
+	schema := state.schema
+	if schema == nil {
+		respondResult(w, nil, []GraphqlError{{Message: "No handler is registered"}})
 		return
 	}
 
-	// Execute the query
-	result, errors := ExecuteGraphQL(r.Context(), s.Schema, operation, params.Variables)
+	operation, errs := s.prepareOperation(state, req)
+	if len(errs) > 0 {
+		respondResult(w, nil, errs)
+		return
+	}
+
+	if s.CircuitBreakers != nil {
+		ctx = withCircuitBreakers(ctx, s.CircuitBreakers)
+	}
+
+	// Execute the query, data can be partial with field errors
+	data, errors := ExecuteGraphQL(ctx, schema, operation, req.OperationName, req.Variables)
 
 	if len(errors) > 0 {
 		if s.ErrorHandler != nil {
@@ -144,46 +215,64 @@ func (s *ServeMux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		} else {
 			defaultGraphqlErrorHandler(errors)
 		}
-		respondWithErrors(w, errors)
-	} else {
-		w.Header().Set("Content-Type", "application/json")
-		w.Write(result)
+		if s.ErrorMasking != nil {
+			s.ErrorMasking.mask(errors)
+		}
 	}
+	respondResult(w, data, errors)
 }
 
-func respondWithErrors(w http.ResponseWriter, errors []GraphqlError) {
+// respondResult writes response. data is omitted when the operation has not been executed.
+func respondResult(w http.ResponseWriter, data []byte, errors []GraphqlError) {
 	response := struct {
-		Errors []GraphqlError `json:"errors"`
+		Data   json.RawMessage `json:"data,omitempty"`
+		Errors []GraphqlError  `json:"errors,omitempty"`
 	}{
+		Data:   data,
 		Errors: errors,
 	}
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	json.NewEncoder(w).Encode(response) // nolint: errcheck
 }
 
-func parseOperation(schema *ast.Document) (*ast.OperationDefinition, []GraphqlError) {
-	report := &operationreport.Report{}
-	queryDocument := ast.NewDocument()
-	queryParser := astparser.NewParser()
-	queryParser.Parse(queryDocument, report)
-
-	if report.HasErrors() {
-		return nil, ConvertToGraphQLErrors(report.ExternalErrors)
+// prepareOperation parses, normalizes and validates the query, or returns the cached operation of the same query.
+// Only valid operations are cached.
+func (s *ServeMux) prepareOperation(state *muxState, req *GraphqlRequest) (*ast.Document, []GraphqlError) {
+	var key documentKey
+	if s.DocumentCache != nil {
+		key = newDocumentKey(req.Query, req.OperationName, state.version)
+		if operation, ok := s.DocumentCache.get(key); ok {
+			return operation, nil
+		}
 	}
 
-	normalizer := astnormalization.NewNormalizer(false, false)
-	normalizer.NormalizeOperation(queryDocument, schema, report)
+	operation, errs := parseOperation(req.Query, state.schema.Document)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	if errs := state.validation.validate(operation, state.schema.Document); len(errs) > 0 {
+		return nil, errs
+	}
 
-	if report.HasErrors() {
-		return nil, ConvertToGraphQLErrors(report.ExternalErrors)
+	// Operation of the state which has been replaced while validating would never be hit, and it's not cached
+	if s.DocumentCache != nil && s.state.Load().version == state.version {
+		s.DocumentCache.add(key, operation)
 	}
+	return operation, nil
+}
 
-	if len(queryDocument.OperationDefinitions) == 0 {
-		return nil, []GraphqlError{{Message: "No operation found in the query"}}
+func parseOperation(query string, schema *ast.Document) (*ast.Document, []GraphqlError) {
+	report := &operationreport.Report{}
+	operation, parseReport := astparser.ParseGraphqlDocumentString(query)
+	if parseReport.HasErrors() {
+		return nil, ConvertToGraphQLErrors(parseReport.ExternalErrors)
 	}
 
-	// Get the first operation definition
-	operation := &queryDocument.OperationDefinitions[0]
+	normalizer := astnormalization.NewNormalizer(false, false)
+	normalizer.NormalizeOperation(&operation, schema, report)
 
-	return operation, nil
+	if report.HasErrors() {
+		return nil, ConvertToGraphQLErrors(report.ExternalErrors)
+	}
+	return &operation, nil
 }
@@ -0,0 +1,221 @@
+package runtime
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newVersionHandler(version string) *testHandler {
+	return &testHandler{
+		queries: Fields{
+			"version": &Field{
+				Type: "String!",
+				Resolve: func(p ResolveParams) (interface{}, error) {
+					return version, nil
+				},
+			},
+		},
+	}
+}
+
+func serveTestQuery(mux *ServeMux, query string) string {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/graphql", strings.NewReader(`{"query":"`+query+`"}`))
+	mux.ServeHTTP(w, r)
+	return strings.TrimSpace(w.Body.String())
+}
+
+func TestServeMuxReplaceHandlers(t *testing.T) {
+	mux := NewServeMux()
+	v1 := newVersionHandler("v1")
+	assert.NoError(t, mux.AddHandler(v1))
+	assert.Equal(t, `{"data":{"version":"v1"}}`, serveTestQuery(mux, "{ version }"))
+
+	assert.NoError(t, mux.ReplaceHandlers(newVersionHandler("v2")))
+	assert.Equal(t, `{"data":{"version":"v2"}}`, serveTestQuery(mux, "{ version }"))
+
+	// broken reload is rejected and the mux keeps serving current schema
+	broken := &testHandler{
+		queries: Fields{
+			"user": &Field{Type: "UndefinedType"},
+		},
+	}
+	assert.Error(t, mux.ReplaceHandlers(broken))
+	assert.Error(t, mux.AddHandler(broken))
+	assert.Error(t, mux.ReplaceHandlers())
+	assert.Equal(t, `{"data":{"version":"v2"}}`, serveTestQuery(mux, "{ version }"))
+}
+
+func TestServeMuxRemoveHandler(t *testing.T) {
+	mux := NewServeMux()
+	h := newTestHandler()
+	v1 := newVersionHandler("v1")
+	assert.NoError(t, mux.AddHandler(h))
+	assert.NoError(t, mux.AddHandler(v1))
+
+	assert.NoError(t, mux.RemoveHandler(h))
+	assert.Contains(t, serveTestQuery(mux, `{ user(name: \"alice\") { name } }`), `field: user not defined on type: Query`)
+	assert.Equal(t, `{"data":{"version":"v1"}}`, serveTestQuery(mux, "{ version }"))
+	assert.Error(t, mux.RemoveHandler(h))
+
+	// the last handler can't be removed, otherwise every request would fail
+	assert.Error(t, mux.RemoveHandler(v1))
+	assert.Equal(t, `{"data":{"version":"v1"}}`, serveTestQuery(mux, "{ version }"))
+	assert.Same(t, mux.CurrentSchema(), mux.Schema)
+}
+
+func TestServeMuxKeepsSchemaForInFlightRequest(t *testing.T) {
+	started, reloaded := make(chan struct{}), make(chan struct{})
+	slow := &testHandler{
+		queries: Fields{
+			"version": &Field{
+				Type: "String!",
+				Resolve: func(p ResolveParams) (interface{}, error) {
+					close(started)
+					<-reloaded
+					return "v1", nil
+				},
+			},
+			"legacy": &Field{
+				Type: "Boolean",
+				Resolve: func(p ResolveParams) (interface{}, error) {
+					return true, nil
+				},
+			},
+		},
+	}
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(slow))
+
+	done := make(chan string)
+	go func() {
+		done <- serveTestQuery(mux, "{ version legacy }")
+	}()
+	<-started
+	assert.NoError(t, mux.ReplaceHandlers(newVersionHandler("v2")))
+	close(reloaded)
+
+	assert.Equal(t, `{"data":{"version":"v1","legacy":true}}`, <-done)
+	assert.Equal(t, `{"data":{"version":"v2"}}`, serveTestQuery(mux, "{ version }"))
+}
+
+type namedTestHandler struct {
+	*testHandler
+	name string
+}
+
+func (h *namedTestHandler) Name() string {
+	return h.name
+}
+
+func TestServeMuxDetectsConflicts(t *testing.T) {
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(&namedTestHandler{newTestHandler(), "users.UserService"}))
+
+	// identical types are shared between handlers
+	shared := newTestHandler()
+	shared.queries = Fields{"me": shared.queries["user"]}
+	shared.types.Resolvers = nil
+	assert.NoError(t, mux.AddHandler(shared))
+
+	err := mux.AddHandler(&namedTestHandler{newVersionHandler("v1"), "other.OtherService"})
+	assert.NoError(t, err)
+	err = mux.AddHandler(&namedTestHandler{newVersionHandler("v2"), "another.AnotherService"})
+	assert.EqualError(t, err, "schema conflict: query field \"version\" is defined by both handler #2 (other.OtherService) as `version: String!` and handler #3 (another.AnotherService) as `version: String!`")
+
+	user := &testHandler{
+		types: Types{
+			Definitions: []string{"type User {\n  name: Int\n}"},
+		},
+	}
+	err = mux.AddHandler(user)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `schema conflict: type "User" is defined differently by handler #0 (users.UserService) and handler #3 (*runtime.testHandler)`)
+	assert.Contains(t, err.Error(), "name: Int")
+
+	resolver := newTestHandler()
+	resolver.queries = nil
+	err = mux.AddHandler(resolver)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `schema conflict: field "User.`)
+	assert.Contains(t, err.Error(), `is resolved by both handler #0 (users.UserService) and handler #3 (*runtime.testHandler)`)
+
+	color := newTestHandler()
+	color.queries = nil
+	color.types.Resolvers = nil
+	color.types.Enums[0].Values = map[string]interface{}{"RED": testColor(2), "BLUE": testColor(1)}
+	err = mux.AddHandler(color)
+	assert.EqualError(t, err, `schema conflict: enum "Color" has different values in handler #0 (users.UserService) (BLUE=2, RED=1) and handler #3 (*runtime.testHandler) (BLUE=1, RED=2)`)
+
+	// schema is unchanged after rejected handlers
+	assert.Equal(t, `{"data":{"version":"v1"}}`, serveTestQuery(mux, "{ version }"))
+}
+
+func TestServeMuxNamespace(t *testing.T) {
+	mux := NewServeMux()
+	users := newTestHandler()
+	assert.NoError(t, mux.AddHandler(users, WithNamespace("users")))
+	assert.NoError(t, mux.AddHandler(newVersionHandler("v1")))
+
+	assert.Equal(t, `{"data":{"users":{"user":{"name":"alice"}},"version":"v1"}}`, serveTestQuery(mux, `{ users { user(name: \"alice\") { name } } version }`))
+	assert.Equal(t, `{"data":{"__type":{"name":"UsersQuery"}}}`, serveTestQuery(mux, `{ __type(name: \"UsersQuery\") { name } }`))
+
+	// namespace can't be shared with root field
+	err := mux.AddHandler(newVersionHandler("v2"), WithNamespace("version"))
+	assert.EqualError(t, err, `schema conflict: namespace "version" of handler #2 (*runtime.testHandler) conflicts with query field defined by handler #1 (*runtime.testHandler)`)
+
+	// namespace must be a GraphQL name
+	err = mux.AddHandler(newVersionHandler("v2"), WithNamespace("my-users"))
+	assert.EqualError(t, err, `schema validation error: handler #2 (*runtime.testHandler): namespace "my-users" is not a valid GraphQL name`)
+
+	// type of namespace can't be defined by handlers
+	conflict := &testHandler{queries: Fields{"usersQuery": &Field{Type: "UsersQuery"}}}
+	conflict.types.Definitions = []string{"type UsersQuery {\n  id: ID\n}"}
+	err = mux.AddHandler(conflict)
+	assert.EqualError(t, err, `schema conflict: type "UsersQuery" of namespace "users" of handler #0 (*runtime.testHandler) is already defined by handler #2 (*runtime.testHandler)`)
+
+	// handlers in the same namespace are merged
+	assert.NoError(t, mux.AddHandler(newVersionHandler("v2"), WithNamespace("users")))
+	assert.Equal(t, `{"data":{"users":{"version":"v2"}}}`, serveTestQuery(mux, `{ users { version } }`))
+
+	assert.NoError(t, mux.RemoveHandler(users))
+	assert.Contains(t, serveTestQuery(mux, `{ users { user(name: \"alice\") { name } } }`), `field: user not defined on type: UsersQuery`)
+}
+
+func TestServeMuxSplitsSharedDefinitions(t *testing.T) {
+	pet := map[string]interface{}{"name": "tama"}
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(&testHandler{
+		types: Types{
+			Definitions: []string{"type Pet {\n  name: String\n}"},
+		},
+		queries: Fields{
+			"pet": &Field{
+				Type: "Pet",
+				Resolve: func(p ResolveParams) (interface{}, error) {
+					return pet, nil
+				},
+			},
+		},
+	}))
+
+	// Pet is defined together with Owner, only Owner is added to the schema
+	assert.NoError(t, mux.AddHandler(&testHandler{
+		types: Types{
+			Definitions: []string{"type Pet {\n  name: String\n}\n\n\"Owner of pet\"\ntype Owner {\n  pet: Pet\n}"},
+		},
+		queries: Fields{
+			"owner": &Field{
+				Type: "Owner",
+				Resolve: func(p ResolveParams) (interface{}, error) {
+					return map[string]interface{}{"pet": pet}, nil
+				},
+			},
+		},
+	}))
+	assert.Equal(t, `{"data":{"pet":{"name":"tama"},"owner":{"pet":{"name":"tama"}}}}`, serveTestQuery(mux, "{ pet { name } owner { pet { name } } }"))
+	assert.Equal(t, `{"data":{"__type":{"description":"Owner of pet"}}}`, serveTestQuery(mux, `{ __type(name: \"Owner\") { description } }`))
+}
@@ -0,0 +1,109 @@
+package runtime
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// graphqlName matches names of GraphQL spec, names starting with "__" are reserved for introspection
+var graphqlName = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+
+// HandlerOption configures a handler when it is registered to ServeMux
+type HandlerOption func(GraphqlHandler) GraphqlHandler
+
+// WithNamespace nests queries and mutations of the handler under the namespace field,
+// e.g. WithNamespace("billing") exposes `query { billing { invoice(id: 1) { ... } } }`.
+// It overrides the namespace which is declared by graphql.service option, empty string disables nesting.
+// Namespace must be a GraphQL name, otherwise the handler is rejected when it's registered.
+func WithNamespace(namespace string) HandlerOption {
+	return func(h GraphqlHandler) GraphqlHandler {
+		return &namespacedHandler{
+			GraphqlHandler: unwrapHandler(h),
+			namespace:      namespace,
+		}
+	}
+}
+
+// namespacedHandler overrides namespace of the wrapped handler
+type namespacedHandler struct {
+	GraphqlHandler
+	namespace string
+}
+
+func (h *namespacedHandler) Namespace() string {
+	return h.namespace
+}
+
+func (h *namespacedHandler) Name() string {
+	if named, ok := h.GraphqlHandler.(interface{ Name() string }); ok {
+		return named.Name()
+	}
+	return fmt.Sprintf("%T", h.GraphqlHandler)
+}
+
+// unwrapHandler returns the handler which user registered
+func unwrapHandler(h GraphqlHandler) GraphqlHandler {
+	if n, ok := h.(*namespacedHandler); ok {
+		return n.GraphqlHandler
+	}
+	return h
+}
+
+// handlerNamespace returns namespace of handler.
+// Generated handlers implement Namespace() when graphql.service option declares it.
+func handlerNamespace(h GraphqlHandler) string {
+	if n, ok := h.(interface{ Namespace() string }); ok {
+		return n.Namespace()
+	}
+	return ""
+}
+
+// validateNamespace checks that namespace can be a field name, and that its type name is not reserved
+func validateNamespace(namespace string) error {
+	if !graphqlName.MatchString(namespace) || strings.HasPrefix(namespace, "__") {
+		return fmt.Errorf("namespace %q is not a valid GraphQL name", namespace)
+	}
+	return nil
+}
+
+// namespaceValue is the value of namespace fields, nested fields are resolved by handler's resolvers
+type namespaceValue struct{}
+
+func resolveNamespace(p ResolveParams) (interface{}, error) {
+	return namespaceValue{}, nil
+}
+
+// namespaceTypeName returns object type name of namespace, e.g. BillingQuery
+func namespaceTypeName(namespace, operation string) string {
+	return strings.ToUpper(namespace[:1]) + namespace[1:] + operation
+}
+
+// addNamespaceTypes renders object types of namespaces and adds namespace fields to root fields.
+// The types are registered to merger so that they don't conflict with types which handlers define.
+func (s *Schema) addNamespaceTypes(merger *schemaMerger, operation string, namespaces map[string]Fields, roots Fields) ([]string, error) {
+	names := make([]string, 0, len(namespaces))
+	for ns := range namespaces {
+		names = append(names, ns)
+	}
+	sort.Strings(names)
+
+	definitions := make([]string, 0, len(names))
+	for _, ns := range names {
+		typeName := namespaceTypeName(ns, operation)
+		description := fmt.Sprintf("%s fields of %s namespace.", operation, ns)
+		definition, err := merger.addNamespaceDefinition(strings.ToLower(operation), ns, typeName, rootTypeDefinition(typeName, description, namespaces[ns]))
+		if err != nil {
+			return nil, err
+		}
+		definitions = append(definitions, definition)
+		s.resolvers[typeName] = rootResolvers(namespaces[ns])
+		roots[ns] = &Field{
+			Description: description,
+			Type:        typeName + "!",
+			Resolve:     resolveNamespace,
+		}
+	}
+	return definitions, nil
+}
@@ -0,0 +1,136 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// nodeInterface is Relay Node interface which node types implement
+const nodeInterface = `"""An object with a global ID."""
+interface Node {
+  """The global ID of the object."""
+  id: ID!
+}`
+
+// NodeResolveFn fetches an object by backend ID which is decoded from global ID
+type NodeResolveFn func(ctx context.Context, id interface{}) (interface{}, error)
+
+// Node describes an object type which implements Relay Node interface.
+// Its id field is exposed as global ID which encodes the type name and backend ID.
+type Node struct {
+	// Object type name of the node
+	Name string
+	// Field of the object which holds backend ID, e.g. "id" or "userId"
+	IDField string
+	// Resolve fetches the node by backend ID, nil if another handler fetches it
+	Resolve NodeResolveFn
+}
+
+// EncodeGlobalID returns opaque global ID which encodes type name and backend ID
+func EncodeGlobalID(typeName string, id interface{}) (string, error) {
+	buf, err := json.Marshal(id)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString([]byte(typeName + ":" + string(buf))), nil
+}
+
+// DecodeGlobalID returns type name and backend ID which global ID encodes.
+// Integer IDs are decoded as int64, or json.Number if they don't fit in int64.
+func DecodeGlobalID(globalID string) (string, interface{}, error) {
+	buf, err := base64.StdEncoding.DecodeString(globalID)
+	if err != nil {
+		return "", nil, fmt.Errorf("Invalid global ID \"%s\"", globalID)
+	}
+	typeName, raw, ok := strings.Cut(string(buf), ":")
+	if !ok || typeName == "" {
+		return "", nil, fmt.Errorf("Invalid global ID \"%s\"", globalID)
+	}
+
+	var id interface{}
+	dec := json.NewDecoder(bytes.NewReader([]byte(raw)))
+	dec.UseNumber()
+	if err := dec.Decode(&id); err != nil {
+		return "", nil, fmt.Errorf("Invalid global ID \"%s\"", globalID)
+	}
+	if n, ok := id.(json.Number); ok {
+		if v, err := n.Int64(); err == nil {
+			return typeName, v, nil
+		}
+	}
+	return typeName, id, nil
+}
+
+// addNodes adds node and nodes root fields to queries, and resolvers of global ID fields of node types.
+// It returns definition of Node interface.
+func (s *Schema) addNodes(queries Fields, nodes map[string]*Node) []string {
+	for name, n := range nodes {
+		if _, ok := s.resolvers[name]; !ok {
+			s.resolvers[name] = make(map[string]FieldResolveFn)
+		}
+		s.resolvers[name]["id"] = globalIDResolver(name, n.IDField)
+	}
+
+	queries["node"] = &Field{
+		Description: "Fetches an object given its global ID.",
+		Args:        "id: ID!",
+		Type:        "Node",
+		Resolve: func(p ResolveParams) (interface{}, error) {
+			id, _ := p.Args["id"].(string) // nolint: errcheck
+			return resolveNode(p.Context, nodes, id), nil
+		},
+	}
+	queries["nodes"] = &Field{
+		Description: "Fetches objects given their global IDs.",
+		Args:        "ids: [ID!]!",
+		Type:        "[Node]!",
+		Resolve: func(p ResolveParams) (interface{}, error) {
+			ids, _ := p.Args["ids"].([]interface{}) // nolint: errcheck
+			if len(ids) > maxBatchSize {
+				return nil, fmt.Errorf("ids must not exceed %d items", maxBatchSize)
+			}
+			results := make([]interface{}, len(ids))
+			resolveBatch(len(ids), func(i int) {
+				id, _ := ids[i].(string) // nolint: errcheck
+				results[i] = resolveNode(p.Context, nodes, id)
+			})
+			return results, nil
+		},
+	}
+	return []string{nodeInterface}
+}
+
+// globalIDResolver resolves id field of node as global ID which encodes backend ID in idField
+func globalIDResolver(typeName, idField string) FieldResolveFn {
+	return func(p ResolveParams) (interface{}, error) {
+		p.Info.FieldName = idField
+		id, err := defaultResolveFn(p)
+		if err != nil || isNil(id) {
+			return nil, err
+		}
+		return EncodeGlobalID(typeName, id)
+	}
+}
+
+func resolveNode(ctx context.Context, nodes map[string]*Node, globalID string) (v *typedValue) {
+	typeName, id, err := DecodeGlobalID(globalID)
+	if err != nil {
+		return &typedValue{err: err}
+	}
+	node, ok := nodes[typeName]
+	if !ok || node.Resolve == nil {
+		return &typedValue{err: fmt.Errorf("Type \"%s\" of global ID can't be fetched", typeName)}
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			v = &typedValue{err: fmt.Errorf("panic in node resolver of %s: %v", typeName, r)}
+		}
+	}()
+	value, err := node.Resolve(ctx, id)
+	return &typedValue{typeName: typeName, value: value, err: err}
+}
@@ -0,0 +1,99 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testNode struct {
+	ID   int64  `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+func newNodeTestHandler() *testHandler {
+	return &testHandler{
+		types: Types{
+			Definitions: []string{"type Member implements Node {\n  id: ID!\n  name: String\n}"},
+			Nodes: []*Node{
+				{
+					Name:    "Member",
+					IDField: "id",
+					Resolve: func(ctx context.Context, id interface{}) (interface{}, error) {
+						if id == int64(1) {
+							return &testNode{ID: 1, Name: "alice"}, nil
+						}
+						return nil, errors.New("member not found")
+					},
+				},
+			},
+		},
+		queries: Fields{
+			"member": &Field{
+				Type: "Member",
+				Resolve: func(p ResolveParams) (interface{}, error) {
+					return &testNode{ID: 1, Name: "alice"}, nil
+				},
+			},
+		},
+	}
+}
+
+func TestGlobalID(t *testing.T) {
+	id, err := EncodeGlobalID("Member", int64(1))
+	assert.NoError(t, err)
+	assert.Equal(t, "TWVtYmVyOjE=", id)
+
+	typeName, v, err := DecodeGlobalID(id)
+	assert.NoError(t, err)
+	assert.Equal(t, "Member", typeName)
+	assert.Equal(t, int64(1), v)
+
+	id, err = EncodeGlobalID("Member", "a:b")
+	assert.NoError(t, err)
+	_, v, err = DecodeGlobalID(id)
+	assert.NoError(t, err)
+	assert.Equal(t, "a:b", v)
+
+	_, _, err = DecodeGlobalID("invalid")
+	assert.EqualError(t, err, `Invalid global ID "invalid"`)
+}
+
+func TestServeMuxNode(t *testing.T) {
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(newNodeTestHandler()))
+
+	assert.Equal(t, `{"data":{"member":{"id":"TWVtYmVyOjE=","name":"alice"}}}`, serveTestQuery(mux, "{ member { id name } }"))
+	assert.Equal(t, `{"data":{"node":{"__typename":"Member","id":"TWVtYmVyOjE=","name":"alice"}}}`,
+		serveTestQuery(mux, `{ node(id: \"TWVtYmVyOjE=\") { __typename id ... on Member { name } } }`))
+
+	unknown, err := EncodeGlobalID("Member", 2)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"data": {"nodes": [{"id": "TWVtYmVyOjE="}, null, null]},
+		"errors": [
+			{"message": "member not found", "path": ["nodes", 1], "locations": [{"line": 1, "column": 3}]},
+			{"message": "Invalid global ID \"invalid\"", "path": ["nodes", 2], "locations": [{"line": 1, "column": 3}]}
+		]
+	}`, serveTestQuery(mux, `{ nodes(ids: [\"TWVtYmVyOjE=\", \"`+unknown+`\", \"invalid\"]) { id } }`))
+}
+
+func TestServeMuxNodesLimitsIDs(t *testing.T) {
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(newNodeTestHandler()))
+
+	ids := make([]string, maxBatchSize+1)
+	for i := range ids {
+		ids[i] = `\"TWVtYmVyOjE=\"`
+	}
+	assert.Contains(t, serveTestQuery(mux, `{ nodes(ids: [`+strings.Join(ids, ", ")+`]) { id } }`), `ids must not exceed 100 items`)
+}
+
+func TestServeMuxNodeReservesRootFields(t *testing.T) {
+	h := newNodeTestHandler()
+	h.queries["node"] = &Field{Type: "String"}
+	assert.EqualError(t, NewServeMux().AddHandler(h), `schema conflict: query field "node" of handler #0 (*runtime.testHandler) is reserved for Relay Node interface`)
+}
@@ -0,0 +1,129 @@
+package runtime
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultInitialBackoff    = 100 * time.Millisecond
+	defaultMaxBackoff        = time.Second
+	defaultBackoffMultiplier = 2
+)
+
+// CallPolicy describes how a backend RPC is called.
+// Generated handlers declare it from GraphqlCallPolicy option of each method.
+type CallPolicy struct {
+	// Deadline of the whole call including retries. Zero means no deadline.
+	Timeout time.Duration
+	// Retry policy, it is applied only when Idempotent is true.
+	Retry *RetryPolicy
+	// If true, the RPC is safe to be called more than once.
+	Idempotent bool
+}
+
+// RetryPolicy describes retrying backend call on failure.
+type RetryPolicy struct {
+	// Maximum number of attempts including the original call
+	MaxAttempts int
+	// Backoff before the first retry
+	InitialBackoff time.Duration
+	// Upper limit of backoff
+	MaxBackoff time.Duration
+	// Backoff growth factor applied on each retry
+	BackoffMultiplier float64
+	// Status codes to be retried. Default is codes.Unavailable only.
+	RetryableCodes []codes.Code
+}
+
+// Invoke calls backend RPC of the host through call function with applying the policy.
+// If policy is nil, call is invoked once with the given context.
+// When ServeMux enables circuit breakers, each attempt is guarded by the breaker of the host
+// so that the call fails fast with UNAVAILABLE while the backend is considered down.
+func Invoke(ctx context.Context, host string, p *CallPolicy, call func(context.Context) error) error {
+	breaker := circuitBreakerFromContext(ctx, host)
+	if p == nil {
+		return invokeWithBreaker(ctx, breaker, call)
+	}
+
+	if p.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.Timeout)
+		defer cancel()
+	}
+
+	attempts := p.maxAttempts()
+	for attempt := 1; ; attempt++ {
+		err := invokeWithBreaker(ctx, breaker, call)
+		if err == nil {
+			return nil
+		}
+		if attempt >= attempts || !p.Retry.isRetryable(err) {
+			return err
+		}
+
+		timer := time.NewTimer(p.Retry.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+func invokeWithBreaker(ctx context.Context, b *CircuitBreaker, call func(context.Context) error) error {
+	done, err := b.allow()
+	if err != nil {
+		return err
+	}
+	err = call(ctx)
+	done(err)
+	return err
+}
+
+func (p *CallPolicy) maxAttempts() int {
+	if !p.Idempotent || p.Retry == nil || p.Retry.MaxAttempts < 1 {
+		return 1
+	}
+	return p.Retry.MaxAttempts
+}
+
+func (r *RetryPolicy) isRetryable(err error) bool {
+	code := status.Code(err)
+	if len(r.RetryableCodes) == 0 {
+		return code == codes.Unavailable
+	}
+	for _, c := range r.RetryableCodes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns randomized duration to wait before next attempt.
+// See: https://github.com/grpc/proposal/blob/master/A6-client-retries.md#exponential-backoff
+func (r *RetryPolicy) backoff(attempt int) time.Duration {
+	initial, maxBackoff, multiplier := r.InitialBackoff, r.MaxBackoff, r.BackoffMultiplier
+	if initial <= 0 {
+		initial = defaultInitialBackoff
+	}
+	if maxBackoff <= 0 {
+		maxBackoff = defaultMaxBackoff
+	}
+	if multiplier <= 0 {
+		multiplier = defaultBackoffMultiplier
+	}
+
+	d := float64(initial) * math.Pow(multiplier, float64(attempt-1))
+	if d > float64(maxBackoff) {
+		d = float64(maxBackoff)
+	}
+	return time.Duration(rand.Int63n(int64(d) + 1)) // nolint: gosec
+}
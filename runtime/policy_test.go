@@ -0,0 +1,115 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func failingCall(calls *int, failures int, code codes.Code) func(context.Context) error {
+	return func(ctx context.Context) error {
+		*calls++
+		if *calls <= failures {
+			return status.Error(code, "backend failure")
+		}
+		return nil
+	}
+}
+
+func TestInvokeWithoutPolicy(t *testing.T) {
+	var calls int
+	err := Invoke(context.Background(), "localhost:50051", nil, failingCall(&calls, 1, codes.Unavailable))
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestInvokeRetriesIdempotentCall(t *testing.T) {
+	p := &CallPolicy{
+		Idempotent: true,
+		Retry: &RetryPolicy{
+			MaxAttempts:    3,
+			InitialBackoff: time.Millisecond,
+			MaxBackoff:     time.Millisecond,
+		},
+	}
+	var calls int
+	err := Invoke(context.Background(), "localhost:50051", p, failingCall(&calls, 2, codes.Unavailable))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+}
+
+func TestInvokeStopsAtMaxAttempts(t *testing.T) {
+	p := &CallPolicy{
+		Idempotent: true,
+		Retry: &RetryPolicy{
+			MaxAttempts:    2,
+			InitialBackoff: time.Millisecond,
+		},
+	}
+	var calls int
+	err := Invoke(context.Background(), "localhost:50051", p, failingCall(&calls, 5, codes.Unavailable))
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Equal(t, 2, calls)
+}
+
+func TestInvokeDoesNotRetryNonIdempotentCall(t *testing.T) {
+	p := &CallPolicy{
+		Retry: &RetryPolicy{
+			MaxAttempts:    3,
+			InitialBackoff: time.Millisecond,
+		},
+	}
+	var calls int
+	err := Invoke(context.Background(), "localhost:50051", p, failingCall(&calls, 1, codes.Unavailable))
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestInvokeRetriesOnlyRetryableCodes(t *testing.T) {
+	p := &CallPolicy{
+		Idempotent: true,
+		Retry: &RetryPolicy{
+			MaxAttempts:    3,
+			InitialBackoff: time.Millisecond,
+			RetryableCodes: []codes.Code{codes.Aborted},
+		},
+	}
+	var calls int
+	err := Invoke(context.Background(), "localhost:50051", p, failingCall(&calls, 1, codes.Unavailable))
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Equal(t, 1, calls)
+
+	calls = 0
+	err = Invoke(context.Background(), "localhost:50051", p, failingCall(&calls, 1, codes.Aborted))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+}
+
+func TestInvokeAppliesTimeout(t *testing.T) {
+	p := &CallPolicy{
+		Timeout: 10 * time.Millisecond,
+	}
+	err := Invoke(context.Background(), "localhost:50051", p, func(ctx context.Context) error {
+		_, ok := ctx.Deadline()
+		assert.True(t, ok)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+func TestRetryBackoffIsCapped(t *testing.T) {
+	r := &RetryPolicy{
+		InitialBackoff:    10 * time.Millisecond,
+		MaxBackoff:        20 * time.Millisecond,
+		BackoffMultiplier: 10,
+	}
+	for attempt := 1; attempt < 5; attempt++ {
+		assert.LessOrEqual(t, r.backoff(attempt), 20*time.Millisecond)
+	}
+}
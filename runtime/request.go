@@ -2,19 +2,36 @@ package runtime
 
 import (
 	"errors"
-	"hash/fnv"
+	"fmt"
+	"reflect"
+	"strings"
 
 	"encoding/json"
 	"net/http"
 
 	"github.com/iancoleman/strcase"
-	"github.com/wundergraph/graphql-go-tools/pkg/execution"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
 type GraphqlRequest struct {
 	Query         string                 `json:"query"`
 	Variables     map[string]interface{} `json:"variables"`
 	OperationName string                 `json:"operationName"`
+	// DocumentID refers a trusted document instead of query text
+	DocumentID string                 `json:"documentId"`
+	Extensions map[string]interface{} `json:"extensions"`
+}
+
+// documentID returns ID of the document which request refers,
+// Apollo clients send it as "extensions.persistedQuery.sha256Hash"
+func (r *GraphqlRequest) documentID() string {
+	if r.DocumentID != "" {
+		return r.DocumentID
+	}
+	pq, _ := r.Extensions["persistedQuery"].(map[string]interface{}) // nolint: errcheck
+	hash, _ := pq["sha256Hash"].(string)                             // nolint: errcheck
+	return hash
 }
 
 func parseRequest(r *http.Request) (*GraphqlRequest, error) {
@@ -28,23 +45,6 @@ func parseRequest(r *http.Request) (*GraphqlRequest, error) {
 	return &req, nil
 }
 
-func convertVariables(vars map[string]interface{}) execution.Variables {
-	result := make(execution.Variables)
-	for k, v := range vars {
-		jsonBytes, err := json.Marshal(v)
-		if err != nil {
-			// Handle error appropriately
-			continue
-		}
-		// Use a hash of the key as uint64
-		h := fnv.New64a()
-		h.Write([]byte(k))
-		key := h.Sum64()
-		result[key] = jsonBytes
-	}
-	return result
-}
-
 // MarshalRequest marshals graphql request arguments to gRPC request message
 func MarshalRequest(args, v interface{}, isCamel bool) error {
 	if args == nil {
@@ -57,11 +57,138 @@ func MarshalRequest(args, v interface{}, isCamel bool) error {
 	if isCamel {
 		m = toLowerCaseKeys(m)
 	}
-	buf, err := json.Marshal(m)
+	buf, err := json.Marshal(withoutMessages(reflect.TypeOf(v), m))
 	if err != nil {
 		return err
 	}
-	return json.Unmarshal(buf, &v)
+	if err := json.Unmarshal(buf, &v); err != nil {
+		return err
+	}
+	return setMessages(reflect.ValueOf(v), m)
+}
+
+// withoutMessages copies arguments replacing values which are set by setMessages with null,
+// because encoding/json can't unmarshal oneof like Value and scalar of wrapper field into generated struct.
+// Type of the target is followed by json name, and it's nil for values which no field accepts.
+func withoutMessages(typ reflect.Type, value interface{}) interface{} {
+	switch t := value.(type) {
+	case proto.Message:
+		return nil
+	case map[string]interface{}:
+		ret := make(map[string]interface{}, len(t))
+		for k, v := range t {
+			ret[k] = withoutMessages(jsonFieldType(typ, k), v)
+		}
+		return ret
+	case []interface{}:
+		var elem reflect.Type
+		if typ = derefType(typ); typ != nil && typ.Kind() == reflect.Slice {
+			elem = typ.Elem()
+		}
+		ret := make([]interface{}, len(t))
+		for i, v := range t {
+			ret[i] = withoutMessages(elem, v)
+		}
+		return ret
+	default:
+		if t != nil && isWrapperType(typ) {
+			return nil
+		}
+		return t
+	}
+}
+
+// jsonFieldType returns type of the struct field which has the json name
+func jsonFieldType(typ reflect.Type, name string) reflect.Type {
+	if typ = derefType(typ); typ == nil || typ.Kind() != reflect.Struct {
+		return nil
+	}
+	for i := 0; i < typ.NumField(); i++ {
+		if strings.Split(typ.Field(i).Tag.Get("json"), ",")[0] == name {
+			return typ.Field(i).Type
+		}
+	}
+	return nil
+}
+
+func derefType(typ reflect.Type) reflect.Type {
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ
+}
+
+// isWrapperType reports whether the type is pointer to generated wrapper message, e.g. *wrapperspb.Int32Value
+func isWrapperType(typ reflect.Type) bool {
+	if typ == nil || typ.Kind() != reflect.Ptr || typ.Elem().Kind() != reflect.Struct {
+		return false
+	}
+	m, ok := reflect.Zero(typ).Interface().(proto.Message)
+	return ok && IsWrapper(m.ProtoReflect().Descriptor())
+}
+
+// setMessages sets messages in arguments to the fields of generated struct which have the same json name,
+// and scalars to wrapper fields as wrapper messages
+func setMessages(target reflect.Value, value interface{}) error {
+	switch t := value.(type) {
+	case proto.Message:
+		return setMessageField(target, t)
+	case map[string]interface{}:
+		target = derefValue(target)
+		if target.Kind() != reflect.Struct {
+			return nil
+		}
+		typ := target.Type()
+		for i := 0; i < typ.NumField(); i++ {
+			name := strings.Split(typ.Field(i).Tag.Get("json"), ",")[0]
+			v, ok := t[name]
+			if name == "" || !ok {
+				continue
+			}
+			if err := setMessages(target.Field(i), v); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+		}
+	case []interface{}:
+		target = derefValue(target)
+		if target.Kind() != reflect.Slice {
+			return nil
+		}
+		for i := 0; i < len(t) && i < target.Len(); i++ {
+			if err := setMessages(target.Index(i), t[i]); err != nil {
+				return err
+			}
+		}
+	default:
+		if t == nil || !target.IsValid() || !isWrapperType(target.Type()) {
+			return nil
+		}
+		m, err := newWrapper(target.Type(), t)
+		if err != nil {
+			return err
+		}
+		return setMessageField(target, m)
+	}
+	return nil
+}
+
+// setMessageField sets message to the field, Value of JSON scalar is converted to Struct or ListValue which the field requires
+func setMessageField(field reflect.Value, m proto.Message) error {
+	rv := reflect.ValueOf(m)
+	if v, ok := m.(*structpb.Value); ok && field.Kind() == reflect.Ptr && rv.Type() != field.Type() {
+		if target, ok := reflect.New(field.Type().Elem()).Interface().(proto.Message); ok {
+			converted, err := ConvertJSON(v, target.ProtoReflect().Descriptor().FullName())
+			if err != nil {
+				return err
+			}
+			rv = reflect.ValueOf(converted)
+		}
+	}
+	if !field.CanSet() || !rv.Type().AssignableTo(field.Type()) {
+		return fmt.Errorf("cannot use %s as %s", m.ProtoReflect().Descriptor().FullName(), field.Type())
+	}
+	field.Set(rv)
+	return nil
 }
 
 // Convert to lower case keyname string
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/iancoleman/strcase"
+	"google.golang.org/protobuf/proto"
 )
 
 func derefValue(v reflect.Value) reflect.Value {
@@ -19,13 +20,30 @@ func MarshalResponse(resp interface{}) interface{} {
 	if resp == nil {
 		return nil
 	}
-	v := derefValue(reflect.ValueOf(resp))
+	return marshalValue(derefValue(reflect.ValueOf(resp)))
+}
+
+// marshalValue marshals reflect value by its kind.
+// Bytes, messages of JSON scalar and wrappers are kept as they are so that scalar serializes them,
+// because oneof of Value can't be read from json tags.
+func marshalValue(v reflect.Value) interface{} {
 	switch v.Kind() {
 	case reflect.Struct:
+		if v.CanAddr() {
+			if m, ok := v.Addr().Interface().(proto.Message); ok {
+				md := m.ProtoReflect().Descriptor()
+				if _, ok := jsonMessages[md.FullName()]; ok || IsWrapper(md) {
+					return m
+				}
+			}
+		}
 		return marshalStruct(v)
 	case reflect.Map:
 		return marshalMap(v)
 	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Bytes()
+		}
 		return marshalSlice(v)
 	default:
 		return primitive(v)
@@ -38,17 +56,7 @@ func marshalSlice(v reflect.Value) []interface{} {
 	ret := make([]interface{}, size)
 
 	for i := 0; i < size; i++ {
-		vv := derefValue(v.Index(i))
-		switch vv.Kind() {
-		case reflect.Struct:
-			ret[i] = marshalStruct(vv)
-		case reflect.Map:
-			ret[i] = marshalMap(vv)
-		case reflect.Slice:
-			ret[i] = marshalSlice(vv)
-		default:
-			ret[i] = primitive(vv)
-		}
+		ret[i] = marshalValue(derefValue(v.Index(i)))
 	}
 	return ret
 }
@@ -67,18 +75,7 @@ func marshalStruct(v reflect.Value) map[string]interface{} {
 		}
 
 		name := strcase.ToLowerCamel(strings.TrimSuffix(tag, ",omitempty"))
-		vv := derefValue(v.Field(i))
-
-		switch vv.Kind() {
-		case reflect.Struct:
-			ret[name] = marshalStruct(vv)
-		case reflect.Map:
-			ret[name] = marshalMap(vv)
-		case reflect.Slice:
-			ret[name] = marshalSlice(vv)
-		default:
-			ret[name] = primitive(vv)
-		}
+		ret[name] = marshalValue(derefValue(v.Field(i)))
 	}
 	return ret
 }
@@ -95,35 +92,10 @@ func marshalMap(v reflect.Value) []mapValue {
 
 	iter := v.MapRange()
 	for iter.Next() {
-		key := iter.Key()
-		value := iter.Value()
-		kk := derefValue(key)
-		vv := derefValue(value)
-
-		mapItem := mapValue{}
-
-		switch kk.Kind() {
-		case reflect.Struct:
-			mapItem.Key = marshalStruct(kk)
-		case reflect.Map:
-			mapItem.Key = marshalMap(kk)
-		case reflect.Slice:
-			mapItem.Key = marshalSlice(kk)
-		default:
-			mapItem.Key = primitive(kk)
-		}
-
-		switch vv.Kind() {
-		case reflect.Struct:
-			mapItem.Value = marshalStruct(vv)
-		case reflect.Map:
-			mapItem.Value = marshalMap(vv)
-		case reflect.Slice:
-			mapItem.Value = marshalSlice(vv)
-		default:
-			mapItem.Value = primitive(vv)
-		}
-		ret = append(ret, mapItem)
+		ret = append(ret, mapValue{
+			Key:   marshalValue(derefValue(iter.Key())),
+			Value: marshalValue(derefValue(iter.Value())),
+		})
 	}
 
 	return ret
@@ -0,0 +1,461 @@
+package runtime
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// maxSafeInteger is the largest integer which JSON number of float64 represents exactly
+const maxSafeInteger = 1<<53 - 1
+
+// scalarCoercer parses input value and serializes resolved value of a custom scalar
+type scalarCoercer struct {
+	parse     func(value interface{}) (interface{}, error)
+	serialize func(value interface{}) (interface{}, error)
+}
+
+// wellKnownScalars are custom scalars which protoc-gen-graphql maps protobuf types to.
+// They are coerced when the schema declares them, and other custom scalars are passed through as they are.
+var wellKnownScalars = map[string]scalarCoercer{
+	"Int64":  {parse: parseInt64, serialize: serializeInt64},
+	"UInt64": {parse: parseUInt64, serialize: serializeUInt64},
+	"Bytes":  {parse: parseBytes, serialize: serializeBytes},
+	// DateTime is google.protobuf.Timestamp in RFC 3339 format
+	"DateTime": {parse: parseDateTime, serialize: serializeDateTime},
+	// Duration is google.protobuf.Duration
+	"Duration": {parse: parseDuration, serialize: serializeDuration},
+	// JSON is google.protobuf.Struct, Value, ListValue or Any
+	"JSON": {parse: parseJSON, serialize: serializeJSON},
+	// FieldMask is google.protobuf.FieldMask of comma separated paths
+	"FieldMask": {parse: parseFieldMask, serialize: serializeFieldMask},
+}
+
+// parseInt64 accepts decimal string, or integer which JSON number represents exactly
+func parseInt64(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return n, nil
+		}
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n, nil
+		}
+	case int64:
+		return v, nil
+	case int:
+		return int64(v), nil
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) <= maxSafeInteger {
+			return int64(v), nil
+		}
+	}
+	return nil, fmt.Errorf("Int64 cannot represent value: %v", value)
+}
+
+// parseUInt64 accepts decimal string, or non-negative integer which JSON number represents exactly
+func parseUInt64(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		if n, err := strconv.ParseUint(v, 10, 64); err == nil {
+			return n, nil
+		}
+	case json.Number:
+		if n, err := strconv.ParseUint(v.String(), 10, 64); err == nil {
+			return n, nil
+		}
+	case int64:
+		if v >= 0 {
+			return uint64(v), nil
+		}
+	case int:
+		if v >= 0 {
+			return uint64(v), nil
+		}
+	case float64:
+		if v == math.Trunc(v) && v >= 0 && v <= maxSafeInteger {
+			return uint64(v), nil
+		}
+	}
+	return nil, fmt.Errorf("UInt64 cannot represent value: %v", value)
+}
+
+// parseBytes decodes base64 string, URL-safe and unpadded encodings are accepted as protojson does
+func parseBytes(value interface{}) (interface{}, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("Bytes cannot represent a non string value: %v", value)
+	}
+	enc := base64.StdEncoding
+	if strings.ContainsAny(s, "-_") {
+		enc = base64.URLEncoding
+	}
+	if len(s)%4 != 0 {
+		enc = enc.WithPadding(base64.NoPadding)
+	}
+	b, err := enc.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("Bytes cannot represent non base64 value: %v", value)
+	}
+	return b, nil
+}
+
+// serializeInt64 formats integer as decimal string
+func serializeInt64(value interface{}) (interface{}, error) {
+	v := derefValue(reflect.ValueOf(value))
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if v.Uint() <= math.MaxInt64 {
+			return strconv.FormatUint(v.Uint(), 10), nil
+		}
+	case reflect.String:
+		if _, err := strconv.ParseInt(v.String(), 10, 64); err == nil {
+			return v.String(), nil
+		}
+	}
+	return nil, fmt.Errorf("Int64 cannot represent value: %v", value)
+}
+
+// serializeUInt64 formats non-negative integer as decimal string
+func serializeUInt64(value interface{}) (interface{}, error) {
+	v := derefValue(reflect.ValueOf(value))
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Int() >= 0 {
+			return strconv.FormatInt(v.Int(), 10), nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.String:
+		if _, err := strconv.ParseUint(v.String(), 10, 64); err == nil {
+			return v.String(), nil
+		}
+	}
+	return nil, fmt.Errorf("UInt64 cannot represent value: %v", value)
+}
+
+// serializeBytes encodes bytes as base64 string.
+// String is regarded as already encoded, e.g. bytes field which protojson or dynamic handler converted.
+func serializeBytes(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case []byte:
+		return base64.StdEncoding.EncodeToString(v), nil
+	case string:
+		return v, nil
+	}
+	return nil, fmt.Errorf("Bytes cannot represent value: %v", value)
+}
+
+// parseDateTime parses RFC 3339 string into Timestamp, which is marshaled into request message as it is
+func parseDateTime(value interface{}) (interface{}, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("DateTime cannot represent a non string value: %v", value)
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return nil, fmt.Errorf("DateTime cannot represent non RFC 3339 value: %v", value)
+	}
+	ts := timestamppb.New(t)
+	if err := ts.CheckValid(); err != nil {
+		return nil, fmt.Errorf("DateTime cannot represent value: %v", value)
+	}
+	return ts, nil
+}
+
+// serializeDateTime formats Timestamp as RFC 3339 string in UTC.
+// Timestamp is accepted as message, or object of seconds and nanos which MarshalResponse and dynamic handler convert it to.
+func serializeDateTime(value interface{}) (interface{}, error) {
+	var ts *timestamppb.Timestamp
+	switch v := value.(type) {
+	case *timestamppb.Timestamp:
+		ts = v
+	case time.Time:
+		ts = timestamppb.New(v)
+	case string:
+		if _, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			return v, nil
+		}
+	case map[string]interface{}:
+		// zero values are omitted
+		seconds, _ := toFloat(v["seconds"]) // nolint: errcheck
+		nanos, _ := toFloat(v["nanos"])     // nolint: errcheck
+		ts = &timestamppb.Timestamp{Seconds: int64(seconds), Nanos: int32(nanos)}
+	}
+	if ts == nil || ts.CheckValid() != nil {
+		return nil, fmt.Errorf("DateTime cannot represent value: %v", value)
+	}
+	return ts.AsTime().Format(time.RFC3339Nano), nil
+}
+
+// iso8601Duration matches ISO 8601 duration of days and time, e.g. P1DT2H3M4.5S.
+// Years, months and weeks are rejected because their length is not fixed.
+var iso8601Duration = regexp.MustCompile(`^(-)?P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+
+// parseDuration parses "1.5s" as protojson formats Duration, or ISO 8601 duration like "PT1.5S"
+func parseDuration(value interface{}) (interface{}, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("Duration cannot represent a non string value: %v", value)
+	}
+	if d, err := parseISO8601Duration(s); err == nil {
+		return d, nil
+	}
+	var d durationpb.Duration
+	if err := protojson.Unmarshal([]byte(strconv.Quote(s)), &d); err != nil {
+		return nil, fmt.Errorf("Duration cannot represent value: %v", value)
+	}
+	return &d, nil
+}
+
+func parseISO8601Duration(s string) (*durationpb.Duration, error) {
+	m := iso8601Duration.FindStringSubmatch(s)
+	if m == nil || m[2]+m[3]+m[4]+m[5] == "" || strings.HasSuffix(s, "T") {
+		return nil, fmt.Errorf("invalid ISO 8601 duration: %s", s)
+	}
+	var seconds float64
+	for i, unit := range []float64{86400, 3600, 60, 1} {
+		if m[i+2] == "" {
+			continue
+		}
+		n, err := strconv.ParseFloat(m[i+2], 64)
+		if err != nil {
+			return nil, err
+		}
+		seconds += n * unit
+	}
+	if m[1] == "-" {
+		seconds = -seconds
+	}
+	d := &durationpb.Duration{
+		Seconds: int64(seconds),
+		Nanos:   int32(math.Round((seconds - math.Trunc(seconds)) * 1e9)),
+	}
+	if err := d.CheckValid(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// serializeDuration formats Duration as protojson does, e.g. "1.500s".
+// Duration is accepted as message, or object of seconds and nanos which MarshalResponse and dynamic handler convert it to.
+func serializeDuration(value interface{}) (interface{}, error) {
+	var d *durationpb.Duration
+	switch v := value.(type) {
+	case *durationpb.Duration:
+		d = v
+	case time.Duration:
+		d = durationpb.New(v)
+	case string:
+		if _, err := parseDuration(v); err == nil {
+			return v, nil
+		}
+	case map[string]interface{}:
+		// zero values are omitted
+		seconds, _ := toFloat(v["seconds"]) // nolint: errcheck
+		nanos, _ := toFloat(v["nanos"])     // nolint: errcheck
+		d = &durationpb.Duration{Seconds: int64(seconds), Nanos: int32(nanos)}
+	}
+	if d == nil || d.CheckValid() != nil {
+		return nil, fmt.Errorf("Duration cannot represent value: %v", value)
+	}
+	buf, err := protojson.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+	return strconv.Unquote(string(buf))
+}
+
+// parseJSON converts any input value into Value, MarshalRequest and dynamic handler convert it to Struct or ListValue which field requires
+func parseJSON(value interface{}) (interface{}, error) {
+	if n, ok := value.(json.Number); ok {
+		f, err := n.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("JSON cannot represent value: %v", value)
+		}
+		value = f
+	}
+	v, err := structpb.NewValue(value)
+	if err != nil {
+		return nil, fmt.Errorf("JSON cannot represent value: %v", value)
+	}
+	return v, nil
+}
+
+// serializeJSON converts Struct, Value and ListValue into plain JSON value, and other values are passed through.
+// Any is converted as protojson does, which has "@type" key with the fields of contained message.
+func serializeJSON(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case *structpb.Struct:
+		return v.AsMap(), nil
+	case *structpb.Value:
+		return v.AsInterface(), nil
+	case *structpb.ListValue:
+		return v.AsSlice(), nil
+	case *anypb.Any:
+		buf, err := protojson.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("JSON cannot represent value: %w", err)
+		}
+		var out interface{}
+		if err := json.Unmarshal(buf, &out); err != nil {
+			return nil, err
+		}
+		return out, nil
+	}
+	return value, nil
+}
+
+// jsonMessages are messages which JSON scalar represents, with constructors of their generated types
+var jsonMessages = map[protoreflect.FullName]func() proto.Message{
+	"google.protobuf.Struct":    func() proto.Message { return &structpb.Struct{} },
+	"google.protobuf.Value":     func() proto.Message { return &structpb.Value{} },
+	"google.protobuf.ListValue": func() proto.Message { return &structpb.ListValue{} },
+	"google.protobuf.Any":       func() proto.Message { return &anypb.Any{} },
+}
+
+// NewJSONMessage returns empty generated message of the name when JSON scalar represents it,
+// so that messages of other representations like dynamicpb can be converted to be serialized by the scalar.
+func NewJSONMessage(name protoreflect.FullName) (proto.Message, bool) {
+	newMessage, ok := jsonMessages[name]
+	if !ok {
+		return nil, false
+	}
+	return newMessage(), true
+}
+
+// IsWrapper reports whether the message is one of google.protobuf wrappers, which are mapped to nullable scalars of their value
+func IsWrapper(md protoreflect.MessageDescriptor) bool {
+	return md.ParentFile().Path() == "google/protobuf/wrappers.proto"
+}
+
+// unwrapValue returns value of wrapper message so that scalar serializes it, other values are returned as they are
+func unwrapValue(value interface{}) interface{} {
+	m, ok := value.(proto.Message)
+	if !ok || !IsWrapper(m.ProtoReflect().Descriptor()) {
+		return value
+	}
+	r := m.ProtoReflect()
+	return r.Get(r.Descriptor().Fields().ByName("value")).Interface()
+}
+
+// newWrapper builds wrapper message of the type from scalar value as protojson does, e.g. Int64Value from "1"
+func newWrapper(typ reflect.Type, value interface{}) (proto.Message, error) {
+	m, ok := reflect.New(typ.Elem()).Interface().(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a message", typ)
+	}
+	buf, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	if err := protojson.Unmarshal(buf, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ConvertJSON converts Value which JSON scalar is parsed into to the message of name, Struct, ListValue or Value itself.
+// Any is converted from object which has "@type" key as protojson does.
+func ConvertJSON(v *structpb.Value, name protoreflect.FullName) (proto.Message, error) {
+	switch name {
+	case "google.protobuf.Any":
+		a := &anypb.Any{}
+		if err := UnmarshalAnyJSON(v, a, nil); err != nil {
+			return nil, err
+		}
+		return a, nil
+	case "google.protobuf.Value":
+		return v, nil
+	case "google.protobuf.Struct":
+		if s := v.GetStructValue(); s != nil {
+			return s, nil
+		}
+		return nil, fmt.Errorf("JSON value must be an object for %s", name)
+	case "google.protobuf.ListValue":
+		if l := v.GetListValue(); l != nil {
+			return l, nil
+		}
+		return nil, fmt.Errorf("JSON value must be a list for %s", name)
+	}
+	return nil, fmt.Errorf("JSON value cannot be used as %s", name)
+}
+
+// UnmarshalAnyJSON sets JSON value which has "@type" key into Any message.
+// The contained message is resolved by resolver, or types which are linked into the binary when resolver is nil.
+func UnmarshalAnyJSON(v *structpb.Value, a proto.Message, resolver AnyResolver) error {
+	if v.GetStructValue() == nil {
+		return errors.New("JSON value must be an object with \"@type\" for google.protobuf.Any")
+	}
+	buf, err := protojson.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := (protojson.UnmarshalOptions{Resolver: resolver}).Unmarshal(buf, a); err != nil {
+		return fmt.Errorf("JSON value cannot be used as google.protobuf.Any: %w", err)
+	}
+	return nil
+}
+
+// AnyResolver resolves message types which google.protobuf.Any contains by type URL
+type AnyResolver interface {
+	protoregistry.MessageTypeResolver
+	protoregistry.ExtensionTypeResolver
+}
+
+// parseFieldMask splits comma separated paths into FieldMask
+func parseFieldMask(value interface{}) (interface{}, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("FieldMask cannot represent a non string value: %v", value)
+	}
+	mask := &fieldmaskpb.FieldMask{}
+	for _, path := range strings.Split(s, ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			mask.Paths = append(mask.Paths, path)
+		}
+	}
+	return mask, nil
+}
+
+// serializeFieldMask joins paths of FieldMask with comma.
+// FieldMask is accepted as message, or object of paths which MarshalResponse and dynamic handler convert it to.
+func serializeFieldMask(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case *fieldmaskpb.FieldMask:
+		return strings.Join(v.GetPaths(), ","), nil
+	case string:
+		return v, nil
+	case map[string]interface{}:
+		paths, ok := listValues(v["paths"])
+		if !ok && v["paths"] != nil {
+			break
+		}
+		names := make([]string, len(paths))
+		for i, p := range paths {
+			names[i] = fmt.Sprint(p)
+		}
+		return strings.Join(names, ","), nil
+	}
+	return nil, fmt.Errorf("FieldMask cannot represent value: %v", value)
+}
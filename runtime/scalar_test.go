@@ -0,0 +1,300 @@
+package runtime
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type testScalars struct {
+	Id    int64  `json:"id,omitempty"`
+	Count uint64 `json:"count,omitempty"`
+	Data  []byte `json:"data,omitempty"`
+}
+
+func newScalarTestHandler() *testHandler {
+	return &testHandler{
+		types: Types{
+			Definitions: []string{
+				"scalar Int64",
+				"scalar UInt64",
+				"scalar Bytes",
+				"type Scalars {\n  id: Int64\n  count: UInt64\n  data: Bytes\n}",
+			},
+		},
+		queries: Fields{
+			"echo": &Field{
+				Args: "id: Int64!, count: UInt64, data: Bytes",
+				Type: "Scalars",
+				Resolve: func(p ResolveParams) (interface{}, error) {
+					var req testScalars
+					if err := MarshalRequest(p.Args, &req, false); err != nil {
+						return nil, err
+					}
+					return MarshalResponse(&req), nil
+				},
+			},
+		},
+	}
+}
+
+func TestWellKnownScalars(t *testing.T) {
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(newScalarTestHandler()))
+
+	assert.Equal(t, `{"data":{"echo":{"id":"9007199254740993","count":"18446744073709551615","data":"aGVsbG8="}}}`,
+		serveTestQuery(mux, `{ echo(id: \"9007199254740993\", count: \"18446744073709551615\", data: \"aGVsbG8=\") { id count data } }`))
+	assert.Equal(t, `{"data":{"echo":{"id":"-1","count":"2","data":"aGVsbG8="}}}`,
+		serveTestRequest(mux, `{"query":"query ($id: Int64!, $data: Bytes) { echo(id: $id, count: 2, data: $data) { id count data } }","variables":{"id":-1,"data":"aGVsbG8"}}`))
+
+	assert.Contains(t, serveTestQuery(mux, `{ echo(id: 1.5) { id } }`), `Int64 cannot represent value: 1.5`)
+	assert.Contains(t, serveTestQuery(mux, `{ echo(id: 1, count: -1) { id } }`), `UInt64 cannot represent value: -1`)
+	assert.Contains(t, serveTestQuery(mux, `{ echo(id: 1, data: \"!\") { id } }`), `Bytes cannot represent non base64 value: !`)
+	// integer which JSON number can't represent exactly must be sent as string
+	assert.Contains(t, serveTestRequest(mux, `{"query":"query ($id: Int64!) { echo(id: $id) { id } }","variables":{"id":9007199254740993}}`),
+		`Int64 cannot represent value`)
+}
+
+func TestMarshalResponseKeepsBytes(t *testing.T) {
+	assert.Equal(t, map[string]interface{}{
+		"id":    int64(0),
+		"count": uint64(0),
+		"data":  []byte("hello"),
+	}, MarshalResponse(&testScalars{Data: []byte("hello")}))
+}
+
+type testEvent struct {
+	At *timestamppb.Timestamp `json:"at,omitempty"`
+}
+
+func TestDateTimeScalar(t *testing.T) {
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(&testHandler{
+		types: Types{
+			Definitions: []string{"scalar DateTime", "type Event {\n  at: DateTime\n}"},
+		},
+		queries: Fields{
+			"event": &Field{
+				Args: "at: DateTime!",
+				Type: "Event",
+				Resolve: func(p ResolveParams) (interface{}, error) {
+					var req testEvent
+					if err := MarshalRequest(p.Args, &req, false); err != nil {
+						return nil, err
+					}
+					return MarshalResponse(&req), nil
+				},
+			},
+		},
+	}))
+
+	assert.Equal(t, `{"data":{"event":{"at":"2023-11-14T22:13:20.5Z"}}}`,
+		serveTestQuery(mux, `{ event(at: \"2023-11-15T07:13:20.5+09:00\") { at } }`))
+	assert.Equal(t, `{"data":{"event":{"at":"1970-01-01T00:00:00Z"}}}`,
+		serveTestQuery(mux, `{ event(at: \"1970-01-01T00:00:00Z\") { at } }`))
+	assert.Contains(t, serveTestQuery(mux, `{ event(at: \"2023-11-14\") { at } }`), `DateTime cannot represent non RFC 3339 value: 2023-11-14`)
+}
+
+type testTask struct {
+	Timeout  *durationpb.Duration `json:"timeout,omitempty"`
+	Metadata *structpb.Struct     `json:"metadata,omitempty"`
+	Tags     *structpb.ListValue  `json:"tags,omitempty"`
+	Extra    *structpb.Value      `json:"extra,omitempty"`
+}
+
+func TestDurationAndJSONScalars(t *testing.T) {
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(&testHandler{
+		types: Types{
+			Definitions: []string{
+				"scalar Duration",
+				"scalar JSON",
+				"type Task {\n  timeout: Duration\n  metadata: JSON\n  tags: JSON\n  extra: JSON\n}",
+			},
+		},
+		queries: Fields{
+			"task": &Field{
+				Args: "timeout: Duration, metadata: JSON, tags: JSON, extra: JSON",
+				Type: "Task",
+				Resolve: func(p ResolveParams) (interface{}, error) {
+					var req testTask
+					if err := MarshalRequest(p.Args, &req, false); err != nil {
+						return nil, err
+					}
+					return MarshalResponse(&req), nil
+				},
+			},
+		},
+	}))
+
+	assert.Equal(t, `{"data":{"task":{"timeout":"1.500s","metadata":{"a":{"b":[1,true,null]}},"tags":["x"],"extra":"text"}}}`,
+		serveTestQuery(mux, `{ task(timeout: \"1.5s\", metadata: {a: {b: [1, true, null]}}, tags: [\"x\"], extra: \"text\") { timeout metadata tags extra } }`))
+	assert.Equal(t, `{"data":{"task":{"timeout":"5430.250s","metadata":{"n":1.5},"tags":null,"extra":null}}}`,
+		serveTestRequest(mux, `{"query":"query ($m: JSON) { task(timeout: \"PT1H30M30.25S\", metadata: $m) { timeout metadata tags extra } }","variables":{"m":{"n":1.5}}}`))
+	assert.Equal(t, `{"data":{"task":{"timeout":"-86400s"}}}`, serveTestQuery(mux, `{ task(timeout: \"-P1D\") { timeout } }`))
+
+	assert.Contains(t, serveTestQuery(mux, `{ task(timeout: \"P1Y\") { timeout } }`), `Duration cannot represent value: P1Y`)
+	assert.Contains(t, serveTestQuery(mux, `{ task(metadata: [1]) { metadata } }`), `JSON value must be an object for google.protobuf.Struct`)
+}
+
+type testEnvelope struct {
+	Raw     *anypb.Any `json:"raw,omitempty"`
+	Payload *anypb.Any `json:"payload,omitempty"`
+}
+
+func TestAnyScalarAndUnion(t *testing.T) {
+	pack := func(m proto.Message) *anypb.Any {
+		a, err := anypb.New(m)
+		assert.NoError(t, err)
+		return a
+	}
+	payloads := map[string]*anypb.Any{
+		"text":   pack(wrapperspb.String("hello")),
+		"number": pack(wrapperspb.Int64(42)),
+		"flag":   pack(wrapperspb.Bool(true)),
+	}
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(&testHandler{
+		types: Types{
+			Definitions: []string{
+				"scalar JSON",
+				"scalar Int64",
+				"type Text {\n  value: String\n}",
+				"type Number {\n  value: Int64\n}",
+				"union Payload = Text | Number",
+				"type Envelope {\n  raw: JSON\n  payload: Payload\n}",
+			},
+			AnyTypes: map[string]string{
+				"google.protobuf.StringValue": "Text",
+				"google.protobuf.Int64Value":  "Number",
+			},
+		},
+		queries: Fields{
+			"envelope": &Field{
+				Args: "raw: JSON, kind: String",
+				Type: "Envelope",
+				Resolve: func(p ResolveParams) (interface{}, error) {
+					var req testEnvelope
+					if err := MarshalRequest(map[string]interface{}{"raw": p.Args["raw"]}, &req, false); err != nil {
+						return nil, err
+					}
+					kind, _ := p.Args["kind"].(string) // nolint: errcheck
+					req.Payload = payloads[kind]
+					return MarshalResponse(&req), nil
+				},
+			},
+		},
+	}))
+
+	// "@type" can't be a key of object literal, so that Any is sent as variable
+	assert.Equal(t, `{"data":{"envelope":{"raw":{"@type":"type.googleapis.com/google.protobuf.StringValue","value":"hi"}}}}`,
+		serveTestRequest(mux, `{"query":"query ($raw: JSON) { envelope(raw: $raw) { raw } }","variables":{"raw":{"@type":"type.googleapis.com/google.protobuf.StringValue","value":"hi"}}}`))
+	assert.Contains(t, serveTestQuery(mux, `{ envelope(raw: {value: \"hi\"}) { raw } }`), `JSON value cannot be used as google.protobuf.Any`)
+
+	query := `{ envelope(kind: \"%s\") { payload { __typename ... on Text { value } ... on Number { number: value } } } }`
+	assert.Equal(t, `{"data":{"envelope":{"payload":{"__typename":"Text","value":"hello"}}}}`, serveTestQuery(mux, fmt.Sprintf(query, "text")))
+	assert.Equal(t, `{"data":{"envelope":{"payload":{"__typename":"Number","number":"42"}}}}`, serveTestQuery(mux, fmt.Sprintf(query, "number")))
+	assert.Equal(t, `{"data":{"envelope":{"payload":null}}}`, serveTestQuery(mux, fmt.Sprintf(query, "none")))
+	assert.Contains(t, serveTestQuery(mux, fmt.Sprintf(query, "flag")), `Message \"google.protobuf.BoolValue\" is not a possible type of \"Payload\"`)
+}
+
+func TestServeMuxDetectsAnyTypeConflicts(t *testing.T) {
+	anyHandler := func(definition string, anyTypes map[string]string) *testHandler {
+		return &testHandler{types: Types{Definitions: []string{definition}, AnyTypes: anyTypes}}
+	}
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(newVersionHandler("v1")))
+	assert.NoError(t, mux.AddHandler(anyHandler("type Text {\n  value: String\n}", map[string]string{"google.protobuf.StringValue": "Text"})))
+
+	// the same mapping of shared proto is not a conflict
+	assert.NoError(t, mux.AddHandler(anyHandler("type Text {\n  value: String\n}", map[string]string{"google.protobuf.StringValue": "Text"})))
+
+	assert.EqualError(t,
+		mux.AddHandler(anyHandler("type Label {\n  value: String\n}", map[string]string{"google.protobuf.StringValue": "Label"})),
+		`schema conflict: message "google.protobuf.StringValue" of google.protobuf.Any is mapped to "Text" by handler #1 (*runtime.testHandler) and "Label" by handler #3 (*runtime.testHandler)`)
+	assert.EqualError(t,
+		mux.AddHandler(anyHandler("scalar Label", map[string]string{"google.protobuf.BoolValue": "Label"})),
+		`schema validation error: type "Label" of handler #3 (*runtime.testHandler) for google.protobuf.BoolValue is not a defined object type`)
+}
+
+type testWrappers struct {
+	Count  *wrapperspb.Int32Value    `json:"count,omitempty"`
+	Total  *wrapperspb.Int64Value    `json:"total,omitempty"`
+	Ratio  *wrapperspb.DoubleValue   `json:"ratio,omitempty"`
+	Data   *wrapperspb.BytesValue    `json:"data,omitempty"`
+	Tags   []*wrapperspb.StringValue `json:"tags,omitempty"`
+	Nested *testWrappers             `json:"nested,omitempty"`
+}
+
+func TestWrapperScalars(t *testing.T) {
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(&testHandler{
+		types: Types{
+			Definitions: []string{
+				"scalar Int64",
+				"scalar Bytes",
+				"type Wrappers {\n  count: Int\n  total: Int64\n  ratio: Float\n  data: Bytes\n  tags: [String]\n  nested: Wrappers\n}",
+				"input WrappersInput {\n  count: Int\n  total: Int64\n  ratio: Float\n  data: Bytes\n  tags: [String]\n  nested: WrappersInput\n}",
+			},
+		},
+		queries: Fields{
+			"wrappers": &Field{
+				Args: "input: WrappersInput",
+				Type: "Wrappers",
+				Resolve: func(p ResolveParams) (interface{}, error) {
+					var req testWrappers
+					if err := MarshalRequest(p.Args["input"], &req, true); err != nil {
+						return nil, err
+					}
+					if req.Count != nil && req.Count.GetValue() < 0 {
+						return &req, nil
+					}
+					return MarshalResponse(&req), nil
+				},
+			},
+		},
+	}))
+
+	// wrappers are nullable scalars, and unset wrapper is null unlike zero value of scalar field
+	assert.Equal(t, `{"data":{"wrappers":{"count":0,"total":"9007199254740993","ratio":null,"data":"aGk=","tags":["a",null],"nested":{"count":1,"total":null}}}}`,
+		serveTestQuery(mux, `{ wrappers(input: { count: 0, total: \"9007199254740993\", ratio: null, data: \"aGk=\", tags: [\"a\", null], nested: { count: 1 } }) { count total ratio data tags nested { count total } } }`))
+	// generated struct which is returned as it is
+	assert.Equal(t, `{"data":{"wrappers":{"count":-1,"total":null}}}`,
+		serveTestQuery(mux, `{ wrappers(input: { count: -1 }) { count total } }`))
+
+	// wrappers parameter "object" keeps object types which have value field
+	var req testWrappers
+	assert.NoError(t, MarshalRequest(map[string]interface{}{"count": map[string]interface{}{"value": 2}}, &req, false))
+	assert.Equal(t, int32(2), req.Count.GetValue())
+}
+
+func TestIntSerializationIsRangeChecked(t *testing.T) {
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(&testHandler{
+		types: Types{
+			Definitions: []string{"type Counter {\n  small: Int\n  large: Int\n}"},
+		},
+		queries: Fields{
+			"counter": &Field{
+				Type: "Counter",
+				Resolve: func(p ResolveParams) (interface{}, error) {
+					// uint32 and fixed32 fields are mapped to Int
+					return map[string]interface{}{"small": uint32(math.MaxInt32), "large": uint32(math.MaxInt32 + 1)}, nil
+				},
+			},
+		},
+	}))
+
+	assert.JSONEq(t, `{
+		"data": {"counter": {"small": 2147483647, "large": null}},
+		"errors": [{"message": "Int cannot represent non 32-bit signed integer value: 2147483648", "path": ["counter", "large"], "locations": [{"line": 1, "column": 19}]}]
+	}`, serveTestQuery(mux, `{ counter { small large } }`))
+}
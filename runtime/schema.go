@@ -0,0 +1,317 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/wundergraph/graphql-go-tools/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/pkg/astparser"
+	"github.com/wundergraph/graphql-go-tools/pkg/asttransform"
+	"github.com/wundergraph/graphql-go-tools/pkg/astvalidation"
+	"github.com/wundergraph/graphql-go-tools/pkg/introspection"
+	"github.com/wundergraph/graphql-go-tools/pkg/operationreport"
+)
+
+// FieldResolveFn resolves value of a field
+type FieldResolveFn func(p ResolveParams) (interface{}, error)
+
+// ResolveParams is passed to FieldResolveFn
+type ResolveParams struct {
+	Context context.Context
+	// Value of parent object, nil for root fields
+	Source interface{}
+	// Coerced argument values of the field
+	Args map[string]interface{}
+	Info ResolveInfo
+}
+
+// ResolveInfo describes the field which is being resolved
+type ResolveInfo struct {
+	FieldName  string
+	ParentType string
+	Path       []interface{}
+
+	selectedFields func() []string
+	providedFields func(argument string) []string
+}
+
+// SelectedFields returns dotted names of fields which are selected under the resolving field through fragments,
+// e.g. { user { name posts { title } } } returns "name" and "posts.title" for user field.
+// Object fields are expanded to their subfields, and __typename is excluded.
+func (i ResolveInfo) SelectedFields() []string {
+	if i.selectedFields == nil {
+		return nil
+	}
+	return i.selectedFields()
+}
+
+// ProvidedFields returns dotted names of input fields which the client provides under the argument path,
+// e.g. { updateUser(input: { user: { name: "foo", address: { city: null } } }) } returns "name" and "address.city" for "input.user".
+// Fields which are set to null are included but default values are not, and empty path returns provided arguments.
+func (i ResolveInfo) ProvidedFields(argument string) []string {
+	if i.providedFields == nil {
+		return nil
+	}
+	return i.providedFields(argument)
+}
+
+// Field describes a root field of query or mutation
+type Field struct {
+	Description string
+	// Arguments in SDL, e.g. `id: Int!, name: String = "foo"`
+	Args string
+	// Type reference in SDL, e.g. `[User!]!`
+	Type    string
+	Resolve FieldResolveFn
+}
+
+// Fields is a set of root fields keyed by field name
+type Fields map[string]*Field
+
+// Enum describes an enum type with internal values of each enum value
+type Enum struct {
+	Name       string
+	Definition string
+	// Internal value keyed by enum value name, it is passed to resolvers instead of the name
+	Values map[string]interface{}
+}
+
+// Types describes types which handler provides
+type Types struct {
+	// SDL of object and input object types
+	Definitions []string
+	Enums       []*Enum
+	// Resolvers of object type fields keyed by type name and field name.
+	// Fields which don't have resolver are resolved from parent value.
+	Resolvers map[string]map[string]FieldResolveFn
+	// Federation entities, which are exposed only when ServeMux enables federation
+	Entities []*Entity
+	// Types which implement Relay Node interface
+	Nodes []*Node
+	// Object type names of unions keyed by full name of message which google.protobuf.Any contains
+	AnyTypes map[string]string
+}
+
+// Schema is an executable schema which is merged from handlers
+type Schema struct {
+	Document *ast.Document
+
+	resolvers     map[string]map[string]FieldResolveFn
+	enums         map[string]*Enum
+	anyTypes      map[string]string
+	introspection map[string]interface{}
+}
+
+// buildSchema merges types and root fields of handlers into one schema.
+// It fails when handlers provide conflicting root fields, types or enums.
+// When federation is true, federation fields are added to serve the schema as a subgraph.
+func buildSchema(handlers []GraphqlHandler, federation bool) (*Schema, error) {
+	s := &Schema{
+		resolvers: make(map[string]map[string]FieldResolveFn),
+		enums:     make(map[string]*Enum),
+		anyTypes:  make(map[string]string),
+	}
+	queries, mutations := make(Fields), make(Fields)
+	queryNamespaces, mutationNamespaces := make(map[string]Fields), make(map[string]Fields)
+	entities := make(map[string]*Entity)
+	nodes := make(map[string]*Node)
+
+	var definitions []string
+	merger := newSchemaMerger(handlers)
+	appendDefinition := func(index int, d string) error {
+		added, err := merger.addDefinition(index, d)
+		if added != "" {
+			definitions = append(definitions, added)
+		}
+		return err
+	}
+
+	for i, h := range handlers {
+		types := h.GetTypes()
+		for _, d := range types.Definitions {
+			if err := appendDefinition(i, d); err != nil {
+				return nil, err
+			}
+		}
+		for _, e := range types.Enums {
+			if err := merger.addEnum(i, e, s.enums); err != nil {
+				return nil, err
+			}
+			if err := appendDefinition(i, e.Definition); err != nil {
+				return nil, err
+			}
+			s.enums[e.Name] = e
+		}
+		for typeName, fields := range types.Resolvers {
+			if _, ok := s.resolvers[typeName]; !ok {
+				s.resolvers[typeName] = make(map[string]FieldResolveFn)
+			}
+			for name, fn := range fields {
+				if err := merger.addResolver(i, typeName, name); err != nil {
+					return nil, err
+				}
+				s.resolvers[typeName][name] = fn
+			}
+		}
+		for name, typeName := range types.AnyTypes {
+			if err := merger.addAnyType(i, name, typeName, s.anyTypes); err != nil {
+				return nil, err
+			}
+			s.anyTypes[name] = typeName
+		}
+		for _, e := range types.Entities {
+			if err := merger.addEntity(i, e); err != nil {
+				return nil, err
+			}
+			entities[e.Name] = e
+		}
+		for _, n := range types.Nodes {
+			if err := merger.addNode(i, n, nodes); err != nil {
+				return nil, err
+			}
+			// keep the node which can be fetched
+			if prev, ok := nodes[n.Name]; !ok || prev.Resolve == nil {
+				nodes[n.Name] = n
+			}
+		}
+		namespace := handlerNamespace(h)
+		if err := merger.addRootFields(i, "query", namespace, h.GetQueries(), queries, queryNamespaces); err != nil {
+			return nil, err
+		}
+		if err := merger.addRootFields(i, "mutation", namespace, h.GetMutations(), mutations, mutationNamespaces); err != nil {
+			return nil, err
+		}
+	}
+
+	queryNamespaceTypes, err := s.addNamespaceTypes(merger, "Query", queryNamespaces, queries)
+	if err != nil {
+		return nil, err
+	}
+	mutationNamespaceTypes, err := s.addNamespaceTypes(merger, "Mutation", mutationNamespaces, mutations)
+	if err != nil {
+		return nil, err
+	}
+	definitions = append(definitions, queryNamespaceTypes...)
+	definitions = append(definitions, mutationNamespaceTypes...)
+	if len(nodes) > 0 {
+		if err := merger.reserveRootFields("query", "Relay Node interface", "node", "nodes"); err != nil {
+			return nil, err
+		}
+		definitions = append(definitions, s.addNodes(queries, nodes)...)
+	}
+	if federation {
+		if err := merger.reserveRootFields("query", "Apollo Federation", "_service", "_entities"); err != nil {
+			return nil, err
+		}
+		subgraph := append(append([]string{}, definitions...), rootTypeDefinitions(queries, mutations)...)
+		definitions = append(definitions, s.addFederation(subgraph, queries, entities)...)
+	}
+	definitions = append(definitions, rootTypeDefinitions(queries, mutations)...)
+	if len(queries) > 0 {
+		s.resolvers["Query"] = rootResolvers(queries)
+	}
+	if len(mutations) > 0 {
+		s.resolvers["Mutation"] = rootResolvers(mutations)
+	}
+
+	document, report := astparser.ParseGraphqlDocumentString(strings.Join(definitions, "\n\n"))
+	if report.HasErrors() {
+		return nil, fmt.Errorf("schema validation error: %s", report.Error())
+	}
+	if err := asttransform.MergeDefinitionWithBaseSchema(&document); err != nil {
+		return nil, fmt.Errorf("schema validation error: %s", err)
+	}
+	astvalidation.DefaultDefinitionValidator().Validate(&document, &report)
+	if report.HasErrors() {
+		return nil, fmt.Errorf("schema validation error: %s", report.Error())
+	}
+	s.Document = &document
+
+	if err := s.generateIntrospection(); err != nil {
+		return nil, fmt.Errorf("schema validation error: %s", err)
+	}
+	return s, nil
+}
+
+// rootTypeDefinitions renders Query and Mutation types which have fields
+func rootTypeDefinitions(queries, mutations Fields) []string {
+	var definitions []string
+	if len(queries) > 0 {
+		definitions = append(definitions, rootTypeDefinition("Query", "The query root of the schema.", queries))
+	}
+	if len(mutations) > 0 {
+		definitions = append(definitions, rootTypeDefinition("Mutation", "The mutation root of the schema.", mutations))
+	}
+	return definitions
+}
+
+// rootTypeDefinition renders root fields as SDL of object type.
+// Fields are sorted by name in order to keep the schema stable.
+func rootTypeDefinition(name, description string, fields Fields) string {
+	names := make([]string, 0, len(fields))
+	for n := range fields {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	b := new(strings.Builder)
+	b.WriteString(schemaDescription(description, ""))
+	b.WriteString("type " + name + " {\n")
+	for _, n := range names {
+		f := fields[n]
+		b.WriteString(schemaDescription(f.Description, "  "))
+		b.WriteString("  " + n)
+		if args := strings.TrimSpace(f.Args); args != "" {
+			b.WriteString("(" + args + ")")
+		}
+		b.WriteString(": " + f.Type + "\n")
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+func rootResolvers(fields Fields) map[string]FieldResolveFn {
+	resolvers := make(map[string]FieldResolveFn, len(fields))
+	for name, f := range fields {
+		resolvers[name] = f.Resolve
+	}
+	return resolvers
+}
+
+// schemaDescription formats description as block string
+func schemaDescription(description, indent string) string {
+	if description == "" {
+		return ""
+	}
+	return indent + `"""` + strings.ReplaceAll(description, `"""`, `\"""`) + `"""` + "\n"
+}
+
+// generateIntrospection prepares result of __schema query from the schema document
+func (s *Schema) generateIntrospection() error {
+	var data introspection.Data
+	report := &operationreport.Report{}
+	introspection.NewGenerator().Generate(s.Document, report, &data)
+	if report.HasErrors() {
+		return report
+	}
+
+	buf, err := json.Marshal(data.Schema)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(buf, &s.introspection)
+}
+
+// introspectionType returns introspection result of the named type, or nil
+func (s *Schema) introspectionType(name string) interface{} {
+	types, _ := s.introspection["types"].([]interface{}) // nolint: errcheck
+	for _, t := range types {
+		if v, ok := t.(map[string]interface{}); ok && v["name"] == name {
+			return v
+		}
+	}
+	return nil
+}
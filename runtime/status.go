@@ -0,0 +1,117 @@
+package runtime
+
+import (
+	"errors"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Canonical status code names.
+// See: https://github.com/googleapis/googleapis/blob/master/google/rpc/code.proto
+var canonicalCodes = map[codes.Code]string{
+	codes.OK:                 "OK",
+	codes.Canceled:           "CANCELLED",
+	codes.Unknown:            "UNKNOWN",
+	codes.InvalidArgument:    "INVALID_ARGUMENT",
+	codes.DeadlineExceeded:   "DEADLINE_EXCEEDED",
+	codes.NotFound:           "NOT_FOUND",
+	codes.AlreadyExists:      "ALREADY_EXISTS",
+	codes.PermissionDenied:   "PERMISSION_DENIED",
+	codes.ResourceExhausted:  "RESOURCE_EXHAUSTED",
+	codes.FailedPrecondition: "FAILED_PRECONDITION",
+	codes.Aborted:            "ABORTED",
+	codes.OutOfRange:         "OUT_OF_RANGE",
+	codes.Unimplemented:      "UNIMPLEMENTED",
+	codes.Internal:           "INTERNAL",
+	codes.Unavailable:        "UNAVAILABLE",
+	codes.DataLoss:           "DATA_LOSS",
+	codes.Unauthenticated:    "UNAUTHENTICATED",
+}
+
+// CodeName returns canonical name of status code like "NOT_FOUND"
+func CodeName(c codes.Code) string {
+	if name, ok := canonicalCodes[c]; ok {
+		return name
+	}
+	return c.String()
+}
+
+// canonicalCodeName converts code name in gRPC error message like "NotFound" to canonical one
+func canonicalCodeName(name string) string {
+	for c := range canonicalCodes {
+		if c.String() == name {
+			return CodeName(c)
+		}
+	}
+	return strings.ToUpper(name)
+}
+
+// grpcStatusFromError finds gRPC status through the chain of wrapped errors.
+// Unlike status.FromError, status message is kept as backend returned.
+func grpcStatusFromError(err error) (*status.Status, bool) {
+	if err == nil {
+		return nil, false
+	}
+	var se interface {
+		GRPCStatus() *status.Status
+	}
+	if !errors.As(err, &se) {
+		return nil, false
+	}
+	st := se.GRPCStatus()
+	if st == nil {
+		return nil, false
+	}
+	return st, true
+}
+
+// StatusExtensions returns GraphQL error extensions of gRPC status.
+// Following google.rpc error details are decoded into typed entries:
+//
+//	BadRequest   -> "fieldViolations": [{"field": "...", "description": "..."}]
+//	ErrorInfo    -> "reason", "domain" and "metadata"
+//	RetryInfo    -> "retryDelay": "1.5s"
+//	QuotaFailure -> "quotaViolations": [{"subject": "...", "description": "..."}]
+func StatusExtensions(st *status.Status) map[string]interface{} {
+	ext := map[string]interface{}{
+		"code": CodeName(st.Code()),
+	}
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.BadRequest:
+			violations := make([]map[string]interface{}, 0, len(d.GetFieldViolations()))
+			for _, v := range d.GetFieldViolations() {
+				violations = append(violations, map[string]interface{}{
+					"field":       v.GetField(),
+					"description": v.GetDescription(),
+				})
+			}
+			ext["fieldViolations"] = violations
+		case *errdetails.ErrorInfo:
+			ext["reason"] = d.GetReason()
+			if d.GetDomain() != "" {
+				ext["domain"] = d.GetDomain()
+			}
+			if len(d.GetMetadata()) > 0 {
+				ext["metadata"] = d.GetMetadata()
+			}
+		case *errdetails.RetryInfo:
+			if d.GetRetryDelay() != nil {
+				ext["retryDelay"] = d.GetRetryDelay().AsDuration().String()
+			}
+		case *errdetails.QuotaFailure:
+			violations := make([]map[string]interface{}, 0, len(d.GetViolations()))
+			for _, v := range d.GetViolations() {
+				violations = append(violations, map[string]interface{}{
+					"subject":     v.GetSubject(),
+					"description": v.GetDescription(),
+				})
+			}
+			ext["quotaViolations"] = violations
+		}
+	}
+	return ext
+}
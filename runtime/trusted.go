@@ -0,0 +1,100 @@
+package runtime
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync/atomic"
+
+	"github.com/wundergraph/graphql-go-tools/pkg/astparser"
+)
+
+// TrustedDocuments restricts operations to the documents which a manifest lists.
+// Requests refer a document by "documentId", or by "extensions.persistedQuery.sha256Hash" as Apollo clients send.
+// Ad-hoc query text is rejected, except the text which is identical to the referred document.
+type TrustedDocuments struct {
+	documents map[string]*trustedDocument
+}
+
+type trustedDocument struct {
+	query string
+	used  atomic.Uint64
+}
+
+// TrustedDocumentUsage is the number of requests which a manifest entry has served
+type TrustedDocumentUsage struct {
+	ID    string
+	Count uint64
+}
+
+// NewTrustedDocuments returns allowlist of documents which are keyed by their IDs, e.g. hashes.
+// Documents are checked to be parsable so that broken manifest is rejected on startup.
+func NewTrustedDocuments(documents map[string]string) (*TrustedDocuments, error) {
+	t := &TrustedDocuments{
+		documents: make(map[string]*trustedDocument, len(documents)),
+	}
+	for id, query := range documents {
+		if _, report := astparser.ParseGraphqlDocumentString(query); report.HasErrors() {
+			return nil, fmt.Errorf("trusted document %q is invalid: %s", id, report.Error())
+		}
+		t.documents[id] = &trustedDocument{query: query}
+	}
+	return t, nil
+}
+
+// LoadTrustedDocuments reads manifest file which is a JSON object of ID to document,
+// e.g. {"5d41402a...": "query Me { me { name } }"}
+func LoadTrustedDocuments(path string) (*TrustedDocuments, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var documents map[string]string
+	if err := json.Unmarshal(buf, &documents); err != nil {
+		return nil, fmt.Errorf("failed to parse trusted documents %s: %w", path, err)
+	}
+	return NewTrustedDocuments(documents)
+}
+
+// Usage reports how many requests each manifest entry has served, sorted by ID.
+// Entries which have never been used are included with zero count.
+func (t *TrustedDocuments) Usage() []TrustedDocumentUsage {
+	usage := make([]TrustedDocumentUsage, 0, len(t.documents))
+	for id, d := range t.documents {
+		usage = append(usage, TrustedDocumentUsage{
+			ID:    id,
+			Count: d.used.Load(),
+		})
+	}
+	sort.Slice(usage, func(i, j int) bool {
+		return usage[i].ID < usage[j].ID
+	})
+	return usage
+}
+
+// resolve returns the trusted document which request refers
+func (t *TrustedDocuments) resolve(req *GraphqlRequest) (string, *GraphqlError) {
+	id := req.documentID()
+	if id == "" {
+		return "", &GraphqlError{
+			Message:    "Only trusted documents are allowed, send documentId instead of query",
+			Extensions: map[string]interface{}{"code": "TRUSTED_DOCUMENT_REQUIRED"},
+		}
+	}
+	d, ok := t.documents[id]
+	if !ok {
+		return "", &GraphqlError{
+			Message:    fmt.Sprintf("Trusted document \"%s\" is not found", id),
+			Extensions: map[string]interface{}{"code": "PERSISTED_QUERY_NOT_FOUND"},
+		}
+	}
+	if req.Query != "" && req.Query != d.query {
+		return "", &GraphqlError{
+			Message:    fmt.Sprintf("Query doesn't match trusted document \"%s\"", id),
+			Extensions: map[string]interface{}{"code": "TRUSTED_DOCUMENT_REQUIRED"},
+		}
+	}
+	d.used.Add(1)
+	return d.query, nil
+}
@@ -0,0 +1,59 @@
+package runtime
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func serveTestRequest(mux *ServeMux, body string) string {
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("POST", "/graphql", strings.NewReader(body)))
+	return strings.TrimSpace(w.Body.String())
+}
+
+func TestServeMuxTrustedDocuments(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{
+		"abc": "query User($name: String!) { user(name: $name) { name } }",
+		"def": "{ user(name: \"bob\") { name } }"
+	}`), 0o600))
+	documents, err := LoadTrustedDocuments(path)
+	assert.NoError(t, err)
+
+	mux := NewServeMux()
+	mux.TrustedDocuments = documents
+	assert.NoError(t, mux.AddHandler(newTestHandler()))
+
+	assert.Equal(t, `{"data":{"user":{"name":"alice"}}}`,
+		serveTestRequest(mux, `{"documentId":"abc","variables":{"name":"alice"}}`))
+	assert.Equal(t, `{"data":{"user":{"name":"alice"}}}`,
+		serveTestRequest(mux, `{"extensions":{"persistedQuery":{"version":1,"sha256Hash":"abc"}},"variables":{"name":"alice"}}`))
+	// query text is accepted only when it's identical to the document
+	assert.Equal(t, `{"data":{"user":{"name":"alice"}}}`,
+		serveTestRequest(mux, `{"documentId":"abc","query":"query User($name: String!) { user(name: $name) { name } }","variables":{"name":"alice"}}`))
+
+	assert.Equal(t, `{"errors":[{"message":"Only trusted documents are allowed, send documentId instead of query","extensions":{"code":"TRUSTED_DOCUMENT_REQUIRED"}}]}`,
+		serveTestQuery(mux, `{ user(name: \"alice\") { name } }`))
+	assert.Equal(t, `{"errors":[{"message":"Query doesn't match trusted document \"abc\"","extensions":{"code":"TRUSTED_DOCUMENT_REQUIRED"}}]}`,
+		serveTestRequest(mux, `{"documentId":"abc","query":"{ user(name: \"alice\") { name email } }"}`))
+	assert.Equal(t, `{"errors":[{"message":"Trusted document \"unknown\" is not found","extensions":{"code":"PERSISTED_QUERY_NOT_FOUND"}}]}`,
+		serveTestRequest(mux, `{"documentId":"unknown"}`))
+
+	assert.Equal(t, []TrustedDocumentUsage{{ID: "abc", Count: 3}, {ID: "def", Count: 0}}, documents.Usage())
+}
+
+func TestLoadTrustedDocumentsInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"abc": "{ user("}`), 0o600))
+	_, err := LoadTrustedDocuments(path)
+	assert.ErrorContains(t, err, `trusted document "abc" is invalid`)
+
+	assert.NoError(t, os.WriteFile(path, []byte(`["{ user }"]`), 0o600))
+	_, err = LoadTrustedDocuments(path)
+	assert.ErrorContains(t, err, "failed to parse trusted documents")
+}
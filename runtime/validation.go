@@ -0,0 +1,154 @@
+package runtime
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/wundergraph/graphql-go-tools/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/pkg/astvalidation"
+	"github.com/wundergraph/graphql-go-tools/pkg/operationreport"
+)
+
+// validationRules are operation validation rules of GraphQL specification in the order of evaluation
+var validationRules = []struct {
+	name string
+	rule func() astvalidation.Rule
+}{
+	{"AllVariablesUsed", astvalidation.AllVariablesUsed},
+	{"AllVariableUsesDefined", astvalidation.AllVariableUsesDefined},
+	{"DocumentContainsExecutableOperation", astvalidation.DocumentContainsExecutableOperation},
+	{"OperationNameUniqueness", astvalidation.OperationNameUniqueness},
+	{"LoneAnonymousOperation", astvalidation.LoneAnonymousOperation},
+	{"SubscriptionSingleRootField", astvalidation.SubscriptionSingleRootField},
+	{"FieldSelections", astvalidation.FieldSelections},
+	{"FieldSelectionMerging", astvalidation.FieldSelectionMerging},
+	{"KnownArguments", astvalidation.KnownArguments},
+	{"ValidArguments", astvalidation.ValidArguments},
+	{"Values", astvalidation.Values},
+	{"ArgumentUniqueness", astvalidation.ArgumentUniqueness},
+	{"RequiredArguments", astvalidation.RequiredArguments},
+	{"Fragments", astvalidation.Fragments},
+	{"DirectivesAreDefined", astvalidation.DirectivesAreDefined},
+	{"DirectivesAreInValidLocations", astvalidation.DirectivesAreInValidLocations},
+	{"VariableUniqueness", astvalidation.VariableUniqueness},
+	{"DirectivesAreUniquePerLocation", astvalidation.DirectivesAreUniquePerLocation},
+	{"VariablesAreInputTypes", astvalidation.VariablesAreInputTypes},
+}
+
+// ValidationRules returns names of operation validation rules which ServeMux applies by default
+func ValidationRules() []string {
+	names := make([]string, len(validationRules))
+	for i, r := range validationRules {
+		names[i] = r.name
+	}
+	return names
+}
+
+// DisableValidationRules skips validation rules of the names for compatibility with existing clients.
+// Rules which are disabled by previous call are enabled again, and no argument enables all rules.
+func (s *ServeMux) DisableValidationRules(names ...string) error {
+	v, err := newOperationValidation(names)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next := *s.state.Load()
+	next.validation = v
+	s.store(&next)
+	return nil
+}
+
+// operationValidation validates operations against the schema with enabled rules.
+// Validator keeps state while walking the document, so that validators are pooled for concurrent requests.
+type operationValidation struct {
+	names []string
+	rules []astvalidation.Rule
+	pool  sync.Pool
+}
+
+func newOperationValidation(disabled []string) (*operationValidation, error) {
+	skip := make(map[string]struct{}, len(disabled))
+	for _, name := range disabled {
+		skip[name] = struct{}{}
+	}
+	v := &operationValidation{}
+	for _, r := range validationRules {
+		if _, ok := skip[r.name]; ok {
+			delete(skip, r.name)
+			continue
+		}
+		v.names = append(v.names, r.name)
+		v.rules = append(v.rules, r.rule())
+	}
+	for name := range skip {
+		return nil, fmt.Errorf("unknown validation rule %q", name)
+	}
+
+	v.pool.New = func() interface{} {
+		return astvalidation.NewOperationValidator(v.rules)
+	}
+	return v, nil
+}
+
+// validate returns all violations which enabled rules report.
+// Validation errors have locations but no path, because path is only for errors of execution.
+func (v *operationValidation) validate(operation, schema *ast.Document) []GraphqlError {
+	validator := v.pool.Get().(*astvalidation.OperationValidator) // nolint: errcheck
+	defer v.pool.Put(validator)
+
+	report := &operationreport.Report{}
+	if validator.Validate(operation, schema, report) == astvalidation.Valid {
+		return nil
+	}
+	if len(report.ExternalErrors) == 0 {
+		return []GraphqlError{{Message: report.Error()}}
+	}
+
+	// Most rules stop walking at their first violation, so that rules are run one by one to report all of them.
+	// It's done only for invalid operation, valid one is walked once.
+	var violations []GraphqlError
+	for i, rule := range v.rules {
+		report := &operationreport.Report{}
+		astvalidation.NewOperationValidator([]astvalidation.Rule{rule}).Validate(operation, schema, report)
+		for _, err := range report.ExternalErrors {
+			switch v.names[i] {
+			case "Values":
+				if isCustomScalarLiteralError(schema, err) {
+					continue
+				}
+			case "AllVariablesUsed":
+				err.Message = unusedVariableMessage(err.Message)
+			}
+			violations = append(violations, GraphqlError{
+				Message:   err.Message,
+				Locations: err.Locations,
+			})
+		}
+	}
+	return violations
+}
+
+// unusedVariableMessage rewords the error of AllVariablesUsed rule as GraphQL specification does
+func unusedVariableMessage(message string) string {
+	var name string
+	if _, err := fmt.Sscanf(message, "variable: %s defined on operation:", &name); err != nil {
+		return message
+	}
+	return fmt.Sprintf(`Variable "$%s" is never used.`, name)
+}
+
+// isCustomScalarLiteralError reports whether err of Values rule is about literal of custom scalar.
+// The rule accepts only string literal for custom scalars, while they accept any literal, e.g. object of _Any,
+// and their values are checked on execution.
+func isCustomScalarLiteralError(schema *ast.Document, err operationreport.ExternalError) bool {
+	typeName, _, ok := strings.Cut(err.Message, " cannot represent value: ")
+	if !ok || isBuiltInScalar(typeName) {
+		return false
+	}
+	node, ok := schema.Index.FirstNodeByNameStr(typeName)
+	return ok && node.Kind == ast.NodeKindScalarTypeDefinition
+}
@@ -0,0 +1,36 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServeMuxValidatesOperation(t *testing.T) {
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(newTestHandler()))
+
+	assert.JSONEq(t, `{"errors": [
+		{"message": "Variable \"$unused\" is never used."},
+		{"message": "Variable \"$n\" of type \"Int\" used in position expecting type \"String!\".", "locations": [{"line": 1, "column": 8}, {"line": 1, "column": 47}]}
+	]}`, serveTestQuery(mux, `query ($n: Int, $unused: String) { user(name: $n) { name } }`))
+
+	assert.JSONEq(t, `{"errors": [
+		{"message": "Variable \"$unused\" is never used."}
+	]}`, serveTestQuery(mux, `query ($unused: String) { user(name: \"alice\") { name } }`))
+}
+
+func TestServeMuxDisableValidationRules(t *testing.T) {
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(newTestHandler()))
+	assert.NoError(t, mux.DisableValidationRules("AllVariablesUsed"))
+
+	assert.Equal(t, `{"data":{"user":{"name":"alice"}}}`, serveTestQuery(mux, `query ($unused: String) { user(name: \"alice\") { name } }`))
+
+	// rules are enabled again
+	assert.NoError(t, mux.DisableValidationRules())
+	assert.Contains(t, serveTestQuery(mux, `query ($unused: String) { user(name: \"alice\") { name } }`), `is never used`)
+
+	assert.EqualError(t, mux.DisableValidationRules("Unknown"), `unknown validation rule "Unknown"`)
+	assert.Contains(t, ValidationRules(), "FieldSelectionMerging")
+}
@@ -0,0 +1,128 @@
+package spec
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+	descriptor "google.golang.org/protobuf/types/descriptorpb"
+)
+
+// Fields of AIP-158 paginated List RPC
+const (
+	pageSizeField      = "page_size"
+	pageTokenField     = "page_token"
+	nextPageTokenField = "next_page_token"
+)
+
+// connectionArgs are Relay connection arguments which replace page_size and page_token
+const connectionArgs = "first: Int, after: String"
+
+// pageInfoDefinition is Relay PageInfo type which all connections share
+const pageInfoDefinition = `"""Information about pagination in a connection."""
+type PageInfo {
+  hasNextPage: Boolean!
+  hasPreviousPage: Boolean!
+  startCursor: String
+  endCursor: String
+}`
+
+// IsConnection returns true if the paginated List RPC is exposed as Relay connection
+func (q *Query) IsConnection() bool {
+	return q.Response().GetConnection()
+}
+
+// ConnectionItemsField returns repeated field of the response which holds items of the page.
+// It is the pluck field if declared, otherwise the first repeated field.
+func (q *Query) ConnectionItemsField() *Field {
+	for _, f := range q.PluckResponse() {
+		if f.IsRepeated() {
+			return f
+		}
+	}
+	return nil
+}
+
+// ConnectionItemsFieldName returns Go getter name of the items field
+func (q *Query) ConnectionItemsFieldName() string {
+	return strcase.ToCamel(q.ConnectionItemsField().Name())
+}
+
+// ConnectionTypeName returns connection type name of the items, e.g. UserConnection
+func (q *Query) ConnectionTypeName() string {
+	return q.ConnectionItemsField().GraphqlType() + "Connection"
+}
+
+func (q *Query) connectionEdgeName() string {
+	return q.ConnectionItemsField().GraphqlType() + "Edge"
+}
+
+// ConnectionDefinitions returns SDL of connection, edge and PageInfo types.
+// They are separated so that the same types of other queries are merged.
+func (q *Query) ConnectionDefinitions() []string {
+	if !q.IsConnection() {
+		return nil
+	}
+	item := q.ConnectionItemsField().GraphqlType()
+	return []string{
+		fmt.Sprintf("\"\"\"A connection to a list of %s.\"\"\"\ntype %s {\n  edges: [%s!]!\n  pageInfo: PageInfo!\n}", item, q.ConnectionTypeName(), q.connectionEdgeName()),
+		fmt.Sprintf("\"\"\"An edge in a connection of %s.\"\"\"\ntype %s {\n  node: %s\n  cursor: String!\n}", item, q.connectionEdgeName(), item),
+		pageInfoDefinition,
+	}
+}
+
+// connectionArgs returns arguments of the request except page_size and page_token
+func (q *Query) connectionArgs() []*Field {
+	var fields []*Field
+	for _, f := range q.PluckRequest() {
+		if f.Name() == pageSizeField || f.Name() == pageTokenField {
+			continue
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// ValidateConnection checks that the RPC follows AIP-158 pagination when connection option is declared
+func (q *Query) ValidateConnection() error {
+	if !q.IsConnection() {
+		return nil
+	}
+	if err := requirePaginationField(q.Input, pageSizeField, isIntegerType); err != nil {
+		return err
+	}
+	if err := requirePaginationField(q.Input, pageTokenField, isStringType); err != nil {
+		return err
+	}
+	if err := requirePaginationField(q.Output, nextPageTokenField, isStringType); err != nil {
+		return err
+	}
+	if q.ConnectionItemsField() == nil {
+		if pluck := q.Response().GetPluck(); pluck != "" {
+			return fmt.Errorf("pluck field %s of connection %s must be repeated", pluck, q.QueryName())
+		}
+		return fmt.Errorf("response %s of connection %s doesn't have repeated items field", q.Output.FullPath(), q.QueryName())
+	}
+	return nil
+}
+
+func requirePaginationField(m *Message, name string, valid func(descriptor.FieldDescriptorProto_Type) bool) error {
+	for _, f := range m.Fields() {
+		if f.Name() != name {
+			continue
+		}
+		if f.IsRepeated() || !valid(f.Type()) {
+			return fmt.Errorf("pagination field %s of %s has invalid type", name, m.FullPath())
+		}
+		return nil
+	}
+	return fmt.Errorf("%s doesn't have pagination field %s", m.FullPath(), name)
+}
+
+func isIntegerType(t descriptor.FieldDescriptorProto_Type) bool {
+	return strings.Contains(t.String(), "INT") || strings.Contains(t.String(), "FIXED")
+}
+
+func isStringType(t descriptor.FieldDescriptorProto_Type) bool {
+	return t == descriptor.FieldDescriptorProto_TYPE_STRING
+}
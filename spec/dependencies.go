@@ -6,25 +6,22 @@ const (
 	DependTypeMessage DependType = iota
 	DependTypeInput
 	DependTypeEnum
-	DependTypeInterface
 )
 
 // shorthand alias
 type ms map[string]struct{}
 
 type Dependencies struct {
-	message    ms
-	enum       ms
-	input      ms
-	interfaces ms
+	message ms
+	enum    ms
+	input   ms
 }
 
 func NewDependencies() *Dependencies {
 	return &Dependencies{
-		message:    ms{},
-		enum:       ms{},
-		input:      ms{},
-		interfaces: ms{},
+		message: ms{},
+		enum:    ms{},
+		input:   ms{},
 	}
 }
 
@@ -36,8 +33,6 @@ func (d *Dependencies) Depend(t DependType, pkg string) {
 		d.enum[pkg] = struct{}{}
 	case DependTypeInput:
 		d.input[pkg] = struct{}{}
-	case DependTypeInterface:
-		d.interfaces[pkg] = struct{}{}
 	}
 }
 
@@ -50,18 +45,15 @@ func (d *Dependencies) IsDepended(t DependType, pkg string) bool {
 		_, ok = d.enum[pkg]
 	case DependTypeInput:
 		_, ok = d.input[pkg]
-	case DependTypeInterface:
-		_, ok = d.interfaces[pkg]
 	}
 	return ok
 }
 
 func (d *Dependencies) GetDependendencies() map[string][]string {
 	ret := map[string][]string{
-		"message":   {},
-		"enum":      {},
-		"input":     {},
-		"interface": {},
+		"message": {},
+		"enum":    {},
+		"input":   {},
 	}
 	for p := range d.message {
 		ret["message"] = append(ret["message"], p)
@@ -72,8 +64,5 @@ func (d *Dependencies) GetDependendencies() map[string][]string {
 	for p := range d.input {
 		ret["input"] = append(ret["input"], p)
 	}
-	for p := range d.interfaces {
-		ret["interface"] = append(ret["interface"], p)
-	}
 	return ret
 }
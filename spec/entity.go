@@ -0,0 +1,100 @@
+package spec
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+	graphqlv1 "github.com/nebucloud/nebucloud-gateway/gen/go/graphql/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+var fieldSetName = regexp.MustCompile(`[_A-Za-z][_0-9A-Za-z]*`)
+
+// EntityOption returns the federation entity option of this message
+func (m *Message) EntityOption() *graphqlv1.GraphqlEntity {
+	opts := m.descriptor.GetOptions()
+	if opts == nil {
+		return nil
+	}
+	if entity, ok := proto.GetExtension(opts, graphqlv1.E_Entity).(*graphqlv1.GraphqlEntity); ok {
+		return entity
+	}
+	return nil
+}
+
+func (m *Message) IsEntity() bool {
+	return m.EntityOption() != nil
+}
+
+// EntityKeys returns key field sets with field names in schema, e.g. "organizationId userId"
+func (m *Message) EntityKeys() []string {
+	keys := m.EntityOption().GetKeys()
+	if !m.isCamel {
+		return keys
+	}
+	camel := make([]string, len(keys))
+	for i, k := range keys {
+		camel[i] = fieldSetName.ReplaceAllStringFunc(k, strcase.ToLowerCamel)
+	}
+	return camel
+}
+
+// EntityResolver returns the query which fetches the entity, or nil if services don't define it
+func (m *Message) EntityResolver(services []*Service) *Query {
+	name := m.EntityOption().GetResolver()
+	for _, s := range services {
+		for _, q := range s.Queries {
+			if q.QueryName() == name {
+				return q
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateEntity checks that entity declares keys of its own fields and the resolver is defined in services
+func (m *Message) ValidateEntity(services []*Service) error {
+	if !m.IsEntity() {
+		return nil
+	}
+	keys := m.EntityKeys()
+	if len(keys) == 0 {
+		return fmt.Errorf("entity %s must declare at least one key", m.FullPath())
+	}
+	fields := make(map[string]struct{})
+	for _, f := range m.Fields() {
+		fields[f.FieldName()] = struct{}{}
+	}
+	for _, k := range keys {
+		for _, name := range topLevelFields(k) {
+			if _, ok := fields[name]; !ok {
+				return fmt.Errorf("key %q of entity %s refers undefined field %s", k, m.FullPath(), name)
+			}
+		}
+	}
+	if m.EntityResolver(services) == nil {
+		return fmt.Errorf("resolver %q of entity %s is not defined in package %s", m.EntityOption().GetResolver(), m.FullPath(), m.Package())
+	}
+	return nil
+}
+
+// topLevelFields returns field names of field set which are not nested in sub selections
+func topLevelFields(fieldSet string) []string {
+	var names []string
+	depth := 0
+	for _, token := range strings.Fields(strings.NewReplacer("{", " { ", "}", " } ").Replace(fieldSet)) {
+		switch token {
+		case "{":
+			depth++
+		case "}":
+			depth--
+		default:
+			if depth == 0 {
+				names = append(names, token)
+			}
+		}
+	}
+	return names
+}
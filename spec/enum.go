@@ -76,3 +76,16 @@ func (e *Enum) Values() []*EnumValue {
 func (e *Enum) FullPath() string {
 	return e.File.Package() + "." + e.PathName()
 }
+
+// GraphqlEnumName returns enum type name in schema
+func (e *Enum) GraphqlEnumName() string {
+	return graphqlName(e, "Enum", e.Name())
+}
+
+// EnumFunc returns Go expression which returns *runtime.Enum
+func (e *Enum) EnumFunc(rootPackage string) string {
+	if pkg := NewPackage(e); pkg.Name != rootPackage {
+		return pkg.Name + "." + PrefixEnum(e.Name())
+	}
+	return PrefixEnum(e.Name())
+}
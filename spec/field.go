@@ -1,7 +1,8 @@
 package spec
 
 import (
-	"log"
+	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/iancoleman/strcase"
@@ -17,9 +18,12 @@ type Field struct {
 	*File
 
 	paths []int
+	// owner is the message which defines this field
+	owner *Message
 
-	DependType    interface{}
-	IsCyclic      bool
+	DependType interface{}
+	// UnionTypes are messages which google.protobuf.Any field may contain, declared by any_types option
+	UnionTypes    []*Message
 	isCamel       bool
 	forceRequired bool
 }
@@ -105,62 +109,27 @@ func (f *Field) IsRepeated() bool {
 	return f.Label() == descriptor.FieldDescriptorProto_LABEL_REPEATED
 }
 
-func (f *Field) FieldType(rootPackage string) string {
-	pkg := NewGoPackageFromString(rootPackage)
-	fieldType := f.GraphqlGoType(pkg.Name, false)
-	if f.IsRequired() {
-		fieldType = "graphql.NewNonNull(" + fieldType + ")"
-	}
-	if f.IsRepeated() {
-		fieldType = "graphql.NewList(" + fieldType + ")"
-		if f.IsRequired() {
-			fieldType = "graphql.NewNonNull(" + fieldType + ")"
-		}
-	}
-	return fieldType
+// SchemaType returns SDL type reference of the field as output type
+func (f *Field) SchemaType() string {
+	return f.wrapType(f.GraphqlType())
 }
 
-func (f *Field) FieldTypeInput(rootPackage string) string {
-	pkg := NewGoPackageFromString(rootPackage)
-	fieldType := f.GraphqlGoType(pkg.Name, true)
-	if f.IsRequired() {
-		fieldType = "graphql.NewNonNull(" + fieldType + ")"
-	}
-	if f.IsRepeated() {
-		fieldType = "graphql.NewList(" + fieldType + ")"
-		if f.IsRequired() {
-			fieldType = "graphql.NewNonNull(" + fieldType + ")"
-		}
-	}
-	return fieldType
+// SchemaInputType returns SDL type reference of the field as input type
+func (f *Field) SchemaInputType() string {
+	return f.wrapType(f.GraphqlInputType())
 }
 
-func (f *Field) SchemaType() string {
-	fieldType := f.GraphqlType()
-	if f.IsRepeated() {
-		fieldType = "[" + fieldType + "]"
-	}
+// wrapType wraps named type with list and non-null modifiers.
+// Items of required list are also non-null, e.g. [String!]!
+func (f *Field) wrapType(fieldType string) string {
 	if f.IsRequired() {
 		fieldType += "!"
 	}
-	return fieldType
-}
-
-func (f *Field) SchemaInputType() string {
-	var prefix string
-	if f.Type() == descriptor.FieldDescriptorProto_TYPE_MESSAGE {
-		m := f.DependType.(*Message) // nolint: errcheck
-		if f.Package() == m.Package() || IsGooglePackage(f) {
-			prefix = "Input_"
-		}
-	}
-
-	fieldType := prefix + f.GraphqlType()
 	if f.IsRepeated() {
 		fieldType = "[" + fieldType + "]"
-	}
-	if f.IsRequired() {
-		fieldType += "!"
+		if f.IsRequired() {
+			fieldType += "!"
+		}
 	}
 	return fieldType
 }
@@ -182,13 +151,16 @@ func (f *Field) DefaultValue() string {
 		descriptor.FieldDescriptorProto_TYPE_ENUM:
 		return f.Option.GetDefault()
 	case descriptor.FieldDescriptorProto_TYPE_STRING:
-		return `"` + f.Option.GetDefault() + `"`
+		if f.Option.GetDefault() == "" {
+			return ""
+		}
+		return strconv.Quote(f.Option.GetDefault())
 	default:
 		return ""
 	}
 }
 
-// GraphqlType returns appropriate GraphQL type
+// GraphqlType returns appropriate GraphQL type name
 func (f *Field) GraphqlType() string {
 	switch f.Type() {
 	case descriptor.FieldDescriptorProto_TYPE_BOOL:
@@ -197,90 +169,78 @@ func (f *Field) GraphqlType() string {
 		descriptor.FieldDescriptorProto_TYPE_FLOAT:
 		return "Float"
 	case descriptor.FieldDescriptorProto_TYPE_INT32,
-		descriptor.FieldDescriptorProto_TYPE_INT64,
 		descriptor.FieldDescriptorProto_TYPE_SFIXED32,
+		descriptor.FieldDescriptorProto_TYPE_FIXED32,
+		descriptor.FieldDescriptorProto_TYPE_SINT32,
+		descriptor.FieldDescriptorProto_TYPE_UINT32:
+		return "Int"
+	case descriptor.FieldDescriptorProto_TYPE_INT64,
 		descriptor.FieldDescriptorProto_TYPE_SFIXED64,
-		descriptor.FieldDescriptorProto_TYPE_UINT32,
+		descriptor.FieldDescriptorProto_TYPE_SINT64:
+		if f.int64AsInt {
+			return "Int"
+		}
+		return Int64Scalar
+	case descriptor.FieldDescriptorProto_TYPE_FIXED64,
 		descriptor.FieldDescriptorProto_TYPE_UINT64:
-		return "Int"
+		if f.int64AsInt {
+			return "Int"
+		}
+		return UInt64Scalar
 	case descriptor.FieldDescriptorProto_TYPE_STRING:
 		return "String"
+	case descriptor.FieldDescriptorProto_TYPE_BYTES:
+		return BytesScalar
 	case descriptor.FieldDescriptorProto_TYPE_MESSAGE:
+		if f.IsUnion() {
+			return f.UnionName()
+		}
 		m := f.DependType.(*Message) // nolint: errcheck
-		tn := strings.TrimPrefix(f.TypeName(), m.Package()+".")
-		return strings.ReplaceAll(tn, ".", "_")
+		return m.GraphqlTypeName()
 	case descriptor.FieldDescriptorProto_TYPE_ENUM:
 		e := f.DependType.(*Enum) // nolint: errcheck
-		tn := strings.TrimPrefix(f.TypeName(), e.Package()+".")
-		return strings.ReplaceAll(tn, ".", "_")
+		return e.GraphqlEnumName()
 	default:
 		return "Unknown"
 	}
 }
 
-// GraphqlGoType returns appropriate graphql-go type
-func (f *Field) GraphqlGoType(rootPackage string, isInput bool) string {
-	switch f.Type() {
-	case descriptor.FieldDescriptorProto_TYPE_BOOL:
-		return "graphql.Boolean"
-	case descriptor.FieldDescriptorProto_TYPE_DOUBLE,
-		descriptor.FieldDescriptorProto_TYPE_FLOAT:
-		return "graphql.Float"
-	case descriptor.FieldDescriptorProto_TYPE_INT32,
-		descriptor.FieldDescriptorProto_TYPE_INT64,
-		descriptor.FieldDescriptorProto_TYPE_SFIXED32,
-		descriptor.FieldDescriptorProto_TYPE_SFIXED64,
-		descriptor.FieldDescriptorProto_TYPE_FIXED32,
-		descriptor.FieldDescriptorProto_TYPE_FIXED64,
-		descriptor.FieldDescriptorProto_TYPE_SINT32,
-		descriptor.FieldDescriptorProto_TYPE_SINT64,
-		descriptor.FieldDescriptorProto_TYPE_UINT32,
-		descriptor.FieldDescriptorProto_TYPE_UINT64:
-		return "graphql.Int"
-	case descriptor.FieldDescriptorProto_TYPE_STRING,
-		descriptor.FieldDescriptorProto_TYPE_BYTES:
-		return "graphql.String"
-	case descriptor.FieldDescriptorProto_TYPE_MESSAGE:
+// AnyTypes returns full names of messages which any_types option declares
+func (f *Field) AnyTypes() []string {
+	if f.Option == nil {
+		return nil
+	}
+	return f.Option.GetAnyTypes()
+}
+
+// IsUnion returns true if the field is google.protobuf.Any which is declared as union of known messages.
+// Union is output only, so that the field accepts JSON scalar as input.
+func (f *Field) IsUnion() bool {
+	return len(f.UnionTypes) > 0
+}
+
+// UnionName returns union type name of the field, which is unique by the message and field name
+func (f *Field) UnionName() string {
+	return graphqlName(f.owner, "Union", f.owner.TypeName()+"_"+strcase.ToCamel(f.Name()))
+}
+
+// UnionDefinition returns SDL of union type of the field
+func (f *Field) UnionDefinition() string {
+	members := make([]string, len(f.UnionTypes))
+	for i, m := range f.UnionTypes {
+		members[i] = m.GraphqlTypeName()
+	}
+	return schemaDescription(fmt.Sprintf("Message which %s.%s contains", f.owner.FullPath(), f.Name()), "") +
+		"union " + f.UnionName() + " = " + strings.Join(members, " | ")
+}
+
+// GraphqlInputType returns appropriate GraphQL input type name
+func (f *Field) GraphqlInputType() string {
+	if f.Type() == descriptor.FieldDescriptorProto_TYPE_MESSAGE {
 		m := f.DependType.(*Message) // nolint: errcheck
-		tn := strings.TrimPrefix(f.TypeName(), m.Package()+".")
-		if f.IsCyclic {
-			return PrefixInterface(strings.ReplaceAll(tn, ".", "_"))
-		}
-		var pkgPrefix string
-		pkg := NewPackage(m)
-		if IsGooglePackage(m) {
-			// Case google.protobuf.XXX
-			ptypeName, err := getImplementedPtypes(m)
-			if err != nil {
-				log.Fatalln("[PROTOC-GEN-GRAPHQL] Error:", err)
-			}
-			pkgPrefix = "gql_ptypes_" + ptypeName + "."
-		} else if rootPackage != "." {
-			// Case message is nested, also includes map_entry
-			if pkg.Name != rootPackage {
-				if !IsGooglePackage(m) {
-					pkgPrefix = pkg.Name + "."
-				}
-			}
-		}
-		if isInput {
-			return pkgPrefix + PrefixInput(strings.ReplaceAll(tn, ".", "_"))
-		}
-		return pkgPrefix + PrefixType(strings.ReplaceAll(tn, ".", "_"))
-	case descriptor.FieldDescriptorProto_TYPE_ENUM:
-		e := f.DependType.(*Enum) // nolint: errcheck
-		var pkgPrefix string
-		pkg := NewPackage(e)
-		if pkg.Name != rootPackage {
-			if !IsGooglePackage(e) {
-				pkgPrefix = pkg.Name + "."
-			}
-		}
-		tn := strings.TrimPrefix(f.TypeName(), e.Package()+".")
-		return pkgPrefix + PrefixEnum(strings.ReplaceAll(tn, ".", "_"))
-	default:
-		return "graphql.SkipDirective"
+		return m.GraphqlInputName()
 	}
+	return f.GraphqlType()
 }
 
 func (f *Field) IsResolve() bool {
@@ -0,0 +1,156 @@
+package spec
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+	descriptor "google.golang.org/protobuf/types/descriptorpb"
+)
+
+const fieldMaskMessage = "google.protobuf.FieldMask"
+
+// HasFieldMask returns true if the query sets FieldMask of the request from selected fields
+func (q *Query) HasFieldMask() bool {
+	return q.Request().GetFieldMask() != ""
+}
+
+// FieldMaskField returns FieldMask field of the request which field_mask option declares
+func (q *Query) FieldMaskField() *Field {
+	if !q.HasFieldMask() {
+		return nil
+	}
+	return findField(q.Input, q.Request().GetFieldMask())
+}
+
+// FieldMaskFieldName returns Go field name of FieldMask field
+func (q *Query) FieldMaskFieldName() string {
+	return strcase.ToCamel(q.FieldMaskField().Name())
+}
+
+// FieldMaskPrefix returns the response field which paths of FieldMask are prefixed with,
+// that is the items field of connection or pluck field, otherwise empty string
+func (q *Query) FieldMaskPrefix() string {
+	if q.IsConnection() {
+		return q.ConnectionItemsField().Name()
+	}
+	if q.IsPluckResponse() {
+		return q.PluckResponse()[0].Name()
+	}
+	return ""
+}
+
+// withoutFieldMask drops FieldMask field from arguments since it's set from selected fields
+func (q *Query) withoutFieldMask(fields []*Field) []*Field {
+	if !q.HasFieldMask() {
+		return fields
+	}
+	ret := make([]*Field, 0, len(fields))
+	for _, f := range fields {
+		if f.Name() != q.Request().GetFieldMask() {
+			ret = append(ret, f)
+		}
+	}
+	return ret
+}
+
+// ValidateFieldMask checks that the request has singular FieldMask field which field_mask option declares
+func (q *Query) ValidateFieldMask() error {
+	if !q.HasFieldMask() {
+		return nil
+	}
+	return validateFieldMask(q.Input, q.Request().GetFieldMask())
+}
+
+// HasUpdateMask returns true if the mutation sets FieldMask of the request from provided input fields
+func (m *Mutation) HasUpdateMask() bool {
+	return m.Request().GetUpdateMask() != ""
+}
+
+// UpdateMaskField returns FieldMask field of the request which update_mask option declares
+func (m *Mutation) UpdateMaskField() *Field {
+	if !m.HasUpdateMask() {
+		return nil
+	}
+	return findField(m.Input, m.Request().GetUpdateMask())
+}
+
+// UpdateMaskFieldName returns Go field name of update mask field
+func (m *Mutation) UpdateMaskFieldName() string {
+	return strcase.ToCamel(m.UpdateMaskField().Name())
+}
+
+// UpdateMaskResource returns the resource field which paths of update mask are relative to,
+// that is the only singular message field of the request other than the mask, otherwise nil.
+// Requests which have multiple of them are rejected by ValidateUpdateMask.
+func (m *Mutation) UpdateMaskResource() *Field {
+	if resources := m.updateMaskResources(); len(resources) == 1 {
+		return resources[0]
+	}
+	return nil
+}
+
+func (m *Mutation) updateMaskResources() []*Field {
+	var resources []*Field
+	for _, f := range m.Input.Fields() {
+		if f.Name() == m.Request().GetUpdateMask() || f.IsRepeated() || f.Type() != descriptor.FieldDescriptorProto_TYPE_MESSAGE {
+			continue
+		}
+		resources = append(resources, f)
+	}
+	return resources
+}
+
+// UpdateMaskArgument returns dotted GraphQL path of the input which update mask is built from,
+// e.g. "input.user", or empty string for all arguments
+func (m *Mutation) UpdateMaskArgument() string {
+	var names []string
+	if name := m.InputName(); name != "" {
+		names = append(names, name)
+	}
+	if f := m.UpdateMaskResource(); f != nil {
+		names = append(names, f.FieldName())
+	}
+	return strings.Join(names, ".")
+}
+
+// ValidateUpdateMask checks that the request has singular FieldMask field which update_mask option declares,
+// and at most one singular message field of the resource
+func (m *Mutation) ValidateUpdateMask() error {
+	if !m.HasUpdateMask() {
+		return nil
+	}
+	if err := validateFieldMask(m.Input, m.Request().GetUpdateMask()); err != nil {
+		return err
+	}
+	// paths can't be made relative to one of multiple resources
+	if resources := m.updateMaskResources(); len(resources) > 1 {
+		names := make([]string, len(resources))
+		for i, f := range resources {
+			names[i] = f.Name()
+		}
+		return fmt.Errorf("update mask %s of %s is ambiguous because the request has multiple message fields: %s",
+			m.Request().GetUpdateMask(), m.Input.FullPath(), strings.Join(names, ", "))
+	}
+	return nil
+}
+
+func findField(m *Message, name string) *Field {
+	for _, f := range m.Fields() {
+		if f.Name() == name {
+			return f
+		}
+	}
+	return nil
+}
+
+func validateFieldMask(m *Message, name string) error {
+	f := findField(m, name)
+	if f == nil {
+		return fmt.Errorf("%s doesn't have field mask field %s", m.FullPath(), name)
+	}
+	if f.IsRepeated() || f.Type() != descriptor.FieldDescriptorProto_TYPE_MESSAGE || f.TypeName() != fieldMaskMessage {
+		return fmt.Errorf("field mask field %s of %s must be %s", f.Name(), m.FullPath(), fieldMaskMessage)
+	}
+	return nil
+}
@@ -20,7 +20,10 @@ type File struct {
 	services []*Service
 	enums    []*Enum
 
-	isCamel bool
+	isCamel         bool
+	int64AsInt      bool
+	dateTime        bool
+	wrapperAsObject bool
 
 	CompilerVersion *pluginpb.Version
 }
@@ -28,7 +31,7 @@ type File struct {
 func NewFile(
 	d *descriptorpb.FileDescriptorProto,
 	cv *pluginpb.Version,
-	isCamel bool,
+	params *Params,
 ) *File {
 
 	f := &File{
@@ -36,10 +39,13 @@ func NewFile(
 		descriptor:      d,
 		comments:        makeComments(d),
 
-		services: make([]*Service, 0),
-		messages: make([]*Message, 0),
-		enums:    make([]*Enum, 0),
-		isCamel:  isCamel,
+		services:        make([]*Service, 0),
+		messages:        make([]*Message, 0),
+		enums:           make([]*Enum, 0),
+		isCamel:         params.FieldCamelCase,
+		int64AsInt:      params.IsInt64AsInt(),
+		dateTime:        params.DateTime,
+		wrapperAsObject: params.IsWrapperAsObject(),
 	}
 	for i, s := range d.GetService() {
 		f.services = append(f.services, NewService(s, f, 6, i)) // nolint: gomnd
@@ -1,6 +1,7 @@
 package spec
 
 import (
+	"log"
 	"strings"
 
 	"path/filepath"
@@ -42,6 +43,7 @@ func NewMessage(
 		ps := make([]int, len(paths))
 		copy(ps, paths)
 		ff := NewField(field, f, isCamel, append(ps, 2, i)...) // nolint: gomnd
+		ff.owner = m
 		if !ff.IsOmit() {
 			m.fields = append(m.fields, ff)
 		}
@@ -112,12 +114,54 @@ func (m *Message) FullPath() string {
 	return m.File.Package() + "." + m.Name()
 }
 
-func (m *Message) Interfaces() (ifs []*Message) {
+// ResolveFields returns fields which are resolved by another query
+func (m *Message) ResolveFields() []*Field {
+	var fields []*Field
 	for _, f := range m.fields {
-		if !f.IsCyclic {
-			continue
+		if f.IsResolve() {
+			fields = append(fields, f)
 		}
-		ifs = append(ifs, f.DependType.(*Message))
 	}
-	return ifs
+	return fields
+}
+
+// GraphqlTypeName returns object type name in schema, or scalar name if the message is mapped to scalar
+func (m *Message) GraphqlTypeName() string {
+	if name := m.ScalarName(); name != "" {
+		return name
+	}
+	return graphqlName(m, "Type", m.TypeName())
+}
+
+// GraphqlInputName returns input object type name in schema, or scalar name if the message is mapped to scalar
+func (m *Message) GraphqlInputName() string {
+	if name := m.ScalarName(); name != "" {
+		return name
+	}
+	return graphqlName(m, "Input", m.TypeName())
+}
+
+// TypeFunc returns Go expression which returns object type definition.
+// The function is qualified with package name when the message is defined in another package.
+func (m *Message) TypeFunc(rootPackage string) string {
+	return m.packagePrefix(rootPackage) + PrefixType(m.TypeName())
+}
+
+// InputFunc returns Go expression which returns input object type definition
+func (m *Message) InputFunc(rootPackage string) string {
+	return m.packagePrefix(rootPackage) + PrefixInput(m.TypeName())
+}
+
+func (m *Message) packagePrefix(rootPackage string) string {
+	if IsGooglePackage(m) {
+		ptypeName, err := getImplementedPtypes(m)
+		if err != nil {
+			log.Fatalln("[PROTOC-GEN-GRAPHQL] Error:", err)
+		}
+		return "gql_ptypes_" + ptypeName + "."
+	}
+	if pkg := NewPackage(m); pkg.Name != rootPackage {
+		return pkg.Name + "."
+	}
+	return ""
 }
@@ -112,51 +112,28 @@ func (m *Mutation) Args() []*Field {
 	return m.PluckRequest()
 }
 
+// MutationType returns SDL type reference of mutation field
 func (m *Mutation) MutationType() string {
-	var pkgPrefix string
-	if m.GoPackage() != m.Output.GoPackage() {
-		if IsGooglePackage(m.Output) {
-			ptypeName, err := getImplementedPtypes(m.Output)
-			if err != nil {
-				log.Fatalln("[PROTOC-GEN-GRAPHQL] Error:", err)
-			}
-			pkgPrefix = "gql_ptypes_" + ptypeName + "."
-		} else {
-			pkgPrefix = m.Output.GoPackage()
-			if pkgPrefix != mainPackage {
-				pkgPrefix += "."
-			}
-		}
+	if m.IsPluckResponse() {
+		return m.PluckResponse()[0].SchemaType()
 	}
-	typeName := pkgPrefix + PrefixType(m.Output.Name())
+
+	typeName := m.Output.GraphqlTypeName()
 	if resp := m.Response(); resp != nil {
 		if resp.GetRequired() {
-			typeName = "graphql.NewNonNull(" + typeName + ")"
+			typeName += "!"
 		}
 	}
 	return typeName
 }
 
-func (m *Mutation) OutputName() string {
-	if fields := m.PluckResponse(); len(fields) > 0 {
-		field := fields[0]
-		fieldType := field.GraphqlType()
-		if field.IsRepeated() {
-			fieldType = "[" + fieldType + "]"
-		}
-		if field.IsRequired() {
-			fieldType += "!"
-		}
-		return fieldType
+// SchemaArgs returns SDL of mutation arguments.
+// When request name is specified, whole request is accepted as one input object argument.
+func (m *Mutation) SchemaArgs() string {
+	if name := m.InputName(); name != "" {
+		return name + ": " + m.Input.GraphqlInputName() + "!"
 	}
-
-	typeName := m.Output.Name()
-	if resp := m.Response(); resp != nil {
-		if resp.GetRequired() {
-			typeName += "!"
-		}
-	}
-	return typeName
+	return schemaArgs(m.Args())
 }
 
 func (m *Mutation) InputType() string {
@@ -166,6 +143,20 @@ func (m *Mutation) InputType() string {
 	return m.Input.Name()
 }
 
+func (m *Mutation) OutputType() string {
+	if m.Method.GoPackage() != m.Output.GoPackage() {
+		if IsGooglePackage(m.Output) {
+			ptypeName, err := getImplementedPtypes(m.Output)
+			if err != nil {
+				log.Fatalln("[PROTOC-GEN-GRAPHQL] Error:", err)
+			}
+			return "gql_ptypes_" + ptypeName + "." + m.Output.Name()
+		}
+		return m.Output.StructName(false)
+	}
+	return m.Output.Name()
+}
+
 func (m *Mutation) PluckResponseFieldName() string {
 	fields := m.PluckResponse()
 	return strcase.ToCamel(fields[0].Name())
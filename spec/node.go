@@ -0,0 +1,91 @@
+package spec
+
+import (
+	"fmt"
+
+	graphqlv1 "github.com/nebucloud/nebucloud-gateway/gen/go/graphql/v1"
+	"google.golang.org/protobuf/proto"
+	descriptor "google.golang.org/protobuf/types/descriptorpb"
+)
+
+// globalIDField is the field of Node interface which exposes global ID
+const globalIDField = "id"
+
+// NodeOption returns the Relay Node option of this message
+func (m *Message) NodeOption() *graphqlv1.GraphqlNode {
+	opts := m.descriptor.GetOptions()
+	if opts == nil {
+		return nil
+	}
+	if node, ok := proto.GetExtension(opts, graphqlv1.E_Node).(*graphqlv1.GraphqlNode); ok {
+		return node
+	}
+	return nil
+}
+
+func (m *Message) IsNode() bool {
+	return m.NodeOption() != nil
+}
+
+// NodeIDField returns the field which holds backend ID
+func (m *Message) NodeIDField() *Field {
+	name := m.NodeOption().GetId()
+	if name == "" {
+		name = globalIDField
+	}
+	for _, f := range m.Fields() {
+		if f.Name() == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// NodeResolver returns the query which fetches the node, or nil if services don't define it
+func (m *Message) NodeResolver(services []*Service) *Query {
+	name := m.NodeOption().GetResolver()
+	for _, s := range services {
+		for _, q := range s.Queries {
+			if q.QueryName() == name {
+				return q
+			}
+		}
+	}
+	return nil
+}
+
+// isGlobalIDField reports whether field is exposed as global ID of Node interface
+func (m *Message) isGlobalIDField(f *Field) bool {
+	return m.IsNode() && f.FieldName() == globalIDField
+}
+
+// ValidateNode checks that node declares scalar ID field and the resolver which accepts it is defined in services
+func (m *Message) ValidateNode(services []*Service) error {
+	if !m.IsNode() {
+		return nil
+	}
+	id := m.NodeIDField()
+	if id == nil {
+		return fmt.Errorf("id field of node %s is not defined", m.FullPath())
+	}
+	switch {
+	case id.IsRepeated(), id.Type() == descriptor.FieldDescriptorProto_TYPE_MESSAGE, id.Type() == descriptor.FieldDescriptorProto_TYPE_ENUM:
+		return fmt.Errorf("id field %s of node %s must be a scalar", id.Name(), m.FullPath())
+	}
+	for _, f := range m.Fields() {
+		if f != id && f.FieldName() == globalIDField {
+			return fmt.Errorf("field %s of node %s conflicts with global ID field", f.Name(), m.FullPath())
+		}
+	}
+
+	q := m.NodeResolver(services)
+	if q == nil {
+		return fmt.Errorf("resolver %q of node %s is not defined in package %s", m.NodeOption().GetResolver(), m.FullPath(), m.Package())
+	}
+	for _, f := range q.Input.Fields() {
+		if f.FieldName() == id.FieldName() {
+			return nil
+		}
+	}
+	return fmt.Errorf("request %s of node resolver %q doesn't have id field %s", q.Input.FullPath(), q.QueryName(), id.FieldName())
+}
@@ -11,6 +11,16 @@ var acceptablePathsValues = map[string]struct{}{
 	"source_relative": {},
 }
 
+var acceptableInt64Values = map[string]struct{}{
+	"string": {},
+	"int":    {},
+}
+
+var acceptableWrappersValues = map[string]struct{}{
+	"scalar": {},
+	"object": {},
+}
+
 // Params spec have plugin parameters
 type Params struct {
 	QueryOut       string
@@ -18,6 +28,14 @@ type Params struct {
 	Verbose        bool
 	FieldCamelCase bool
 	Paths          string
+	// Int64 is "string" to map 64-bit integers to Int64 and UInt64 scalars which are serialized as string,
+	// or "int" to map them to 32-bit Int as before. Default is "string".
+	Int64 string
+	// DateTime maps google.protobuf.Timestamp to DateTime scalar instead of object type
+	DateTime bool
+	// Wrappers is "scalar" to map google.protobuf wrappers like Int32Value to nullable scalars of their value,
+	// or "object" to map them to object types which have value field as before. Default is "scalar".
+	Wrappers string
 }
 
 func NewParams(p string) (*Params, error) {
@@ -49,6 +67,8 @@ func NewParams(p string) (*Params, error) {
 			params.Excludes = append(params.Excludes, regex)
 		case "field_camel":
 			params.FieldCamelCase = true
+		case "datetime":
+			params.DateTime = true
 		case "paths":
 			if len(kv) == 1 {
 				return nil, errors.New("argument " + kv[0] + " must have value")
@@ -56,6 +76,20 @@ func NewParams(p string) (*Params, error) {
 				return nil, errors.New("argument " + kv[0] + " value must either of import and source_relative")
 			}
 			params.Paths = kv[1]
+		case "int64":
+			if len(kv) == 1 {
+				return nil, errors.New("argument " + kv[0] + " must have value")
+			} else if _, ok := acceptableInt64Values[kv[1]]; !ok {
+				return nil, errors.New("argument " + kv[0] + " value must either of string and int")
+			}
+			params.Int64 = kv[1]
+		case "wrappers":
+			if len(kv) == 1 {
+				return nil, errors.New("argument " + kv[0] + " must have value")
+			} else if _, ok := acceptableWrappersValues[kv[1]]; !ok {
+				return nil, errors.New("argument " + kv[0] + " value must either of scalar and object")
+			}
+			params.Wrappers = kv[1]
 		default:
 			return nil, errors.New("Unacceptable argument " + kv[0] + " provided")
 		}
@@ -75,3 +109,13 @@ func (p *Params) IsExclude(pkg string) bool {
 func (p *Params) IsSourceRelative() bool {
 	return p.Paths == "source_relative"
 }
+
+// IsInt64AsInt reports whether 64-bit integers are mapped to Int for compatibility
+func (p *Params) IsInt64AsInt() bool {
+	return p.Int64 == "int"
+}
+
+// IsWrapperAsObject reports whether wrappers are mapped to object types for compatibility
+func (p *Params) IsWrapperAsObject() bool {
+	return p.Wrappers == "object"
+}
@@ -0,0 +1,198 @@
+package spec
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	graphqlv1 "github.com/nebucloud/nebucloud-gateway/gen/go/graphql/v1"
+	"google.golang.org/grpc/codes"
+)
+
+// CallPolicy returns the backend call policy option of this method
+func (m *Method) CallPolicy() *graphqlv1.GraphqlCallPolicy {
+	return m.Schema.GetPolicy()
+}
+
+func (m *Method) HasCallPolicy() bool {
+	return m.CallPolicy() != nil
+}
+
+// CallPolicyName returns variable name of generated runtime.CallPolicy,
+// or "nil" if policy is not declared.
+func (m *Method) CallPolicyName() string {
+	if !m.HasCallPolicy() {
+		return "nil"
+	}
+	return "gql__policy_" + m.ServiceName() + "_" + m.Name()
+}
+
+// ParsedCallPolicy is the call policy option whose durations and status codes are parsed
+type ParsedCallPolicy struct {
+	Timeout    time.Duration
+	Idempotent bool
+	// Retry is nil when the option doesn't declare retry
+	Retry *ParsedRetryPolicy
+}
+
+// ParsedRetryPolicy is the retry option whose durations and status codes are parsed
+type ParsedRetryPolicy struct {
+	MaxAttempts       int
+	InitialBackoff    time.Duration
+	MaxBackoff        time.Duration
+	BackoffMultiplier float64
+	RetryableCodes    []codes.Code
+}
+
+// ParseCallPolicy parses durations and status codes in policy option.
+// It returns nil if policy is not declared.
+func (m *Method) ParseCallPolicy() (*ParsedCallPolicy, error) {
+	p := m.CallPolicy()
+	if p == nil {
+		return nil, nil
+	}
+	timeout, err := parseDuration(p.GetTimeout())
+	if err != nil {
+		return nil, fmt.Errorf("invalid call policy of %s.%s: %w", m.ServiceName(), m.Name(), err)
+	}
+	policy := &ParsedCallPolicy{
+		Timeout:    timeout,
+		Idempotent: p.GetIdempotent(),
+	}
+	r := p.GetRetry()
+	if r == nil {
+		return policy, nil
+	}
+	policy.Retry = &ParsedRetryPolicy{
+		MaxAttempts:       int(r.GetMaxAttempts()),
+		BackoffMultiplier: r.GetBackoffMultiplier(),
+	}
+	if policy.Retry.InitialBackoff, err = parseDuration(r.GetInitialBackoff()); err != nil {
+		return nil, fmt.Errorf("invalid call policy of %s.%s: %w", m.ServiceName(), m.Name(), err)
+	}
+	if policy.Retry.MaxBackoff, err = parseDuration(r.GetMaxBackoff()); err != nil {
+		return nil, fmt.Errorf("invalid call policy of %s.%s: %w", m.ServiceName(), m.Name(), err)
+	}
+	for _, c := range r.GetRetryableStatusCodes() {
+		code, err := parseStatusCode(c)
+		if err != nil {
+			return nil, fmt.Errorf("invalid call policy of %s.%s: %w", m.ServiceName(), m.Name(), err)
+		}
+		policy.Retry.RetryableCodes = append(policy.Retry.RetryableCodes, code)
+	}
+	return policy, nil
+}
+
+// ValidateCallPolicy checks durations and status codes in policy option are parsable
+func (m *Method) ValidateCallPolicy() error {
+	_, err := m.ParseCallPolicy()
+	return err
+}
+
+// CallPolicyImports returns packages which are needed for generated policy literal
+func (m *Method) CallPolicyImports() []string {
+	p := m.CallPolicy()
+	if p == nil {
+		return nil
+	}
+	var imports []string
+	r := p.GetRetry()
+	if p.GetTimeout() != "" || r.GetInitialBackoff() != "" || r.GetMaxBackoff() != "" {
+		imports = append(imports, "time")
+	}
+	if len(r.GetRetryableStatusCodes()) > 0 {
+		imports = append(imports, "google.golang.org/grpc/codes")
+	}
+	return imports
+}
+
+// CallPolicyLiteral returns Go code of runtime.CallPolicy.
+// Note that policy must be validated by ValidateCallPolicy beforehand.
+func (m *Method) CallPolicyLiteral() string {
+	p, err := m.ParseCallPolicy()
+	if err != nil || p == nil {
+		return "nil"
+	}
+
+	b := new(strings.Builder)
+	b.WriteString("&runtime.CallPolicy{\n")
+	if p.Timeout > 0 {
+		b.WriteString("Timeout: " + durationLiteral(p.Timeout) + ",\n")
+	}
+	if p.Idempotent {
+		b.WriteString("Idempotent: true,\n")
+	}
+	if r := p.Retry; r != nil {
+		b.WriteString("Retry: &runtime.RetryPolicy{\n")
+		if r.MaxAttempts > 0 {
+			b.WriteString("MaxAttempts: " + strconv.Itoa(r.MaxAttempts) + ",\n")
+		}
+		if r.InitialBackoff > 0 {
+			b.WriteString("InitialBackoff: " + durationLiteral(r.InitialBackoff) + ",\n")
+		}
+		if r.MaxBackoff > 0 {
+			b.WriteString("MaxBackoff: " + durationLiteral(r.MaxBackoff) + ",\n")
+		}
+		if r.BackoffMultiplier > 0 {
+			b.WriteString("BackoffMultiplier: " + strconv.FormatFloat(r.BackoffMultiplier, 'g', -1, 64) + ",\n")
+		}
+		if len(r.RetryableCodes) > 0 {
+			names := make([]string, len(r.RetryableCodes))
+			for i, code := range r.RetryableCodes {
+				names[i] = "codes." + code.String()
+			}
+			b.WriteString("RetryableCodes: []codes.Code{" + strings.Join(names, ", ") + "},\n")
+		}
+		b.WriteString("},\n")
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+func parseDuration(v string) (time.Duration, error) {
+	if v == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, err
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("duration \"%s\" must not be negative", v)
+	}
+	return d, nil
+}
+
+// parseStatusCode accepts canonical status code name like "NOT_FOUND"
+func parseStatusCode(v string) (codes.Code, error) {
+	var c codes.Code
+	if err := c.UnmarshalJSON([]byte(strconv.Quote(strings.ToUpper(v)))); err != nil {
+		return c, fmt.Errorf("unknown status code \"%s\"", v)
+	}
+	return c, nil
+}
+
+// durationLiteral formats duration as readable Go expression, say "100 * time.Millisecond"
+func durationLiteral(d time.Duration) string {
+	units := []struct {
+		unit time.Duration
+		name string
+	}{
+		{time.Hour, "time.Hour"},
+		{time.Minute, "time.Minute"},
+		{time.Second, "time.Second"},
+		{time.Millisecond, "time.Millisecond"},
+		{time.Microsecond, "time.Microsecond"},
+	}
+	for _, u := range units {
+		if d%u.unit != 0 {
+			continue
+		}
+		if n := d / u.unit; n != 1 {
+			return strconv.FormatInt(int64(n), 10) + " * " + u.name
+		}
+		return u.name
+	}
+	return "time.Duration(" + strconv.FormatInt(int64(d), 10) + ")"
+}
@@ -19,9 +19,14 @@ func PrefixInput(name string) string {
 	return "Gql__input_" + name + "()"
 }
 
-// PrefixInterface adds prefix to avoid conflicting name
-func PrefixInterface(name string) string {
-	return "Gql__interface_" + name + "()"
+// graphqlName makes type name in schema which is unique among packages,
+// e.g. Greeter_Type_HelloReply or Google_Type_Timestamp
+func graphqlName(p PackageGetter, kind, name string) string {
+	prefix := NewPackage(p).CamelName
+	if IsGooglePackage(p) {
+		prefix = "Google"
+	}
+	return prefix + "_" + kind + "_" + name
 }
 
 func IsGooglePackage(p PackageGetter) bool {
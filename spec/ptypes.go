@@ -14,6 +14,10 @@ var supportedPtypes = []string{
 	"timestamp",
 	"wrappers",
 	"empty",
+	"duration",
+	"struct",
+	"any",
+	"field_mask",
 }
 
 // After protoc v3.14.0, go_package option name have been changed.
@@ -22,6 +26,10 @@ var supportedPtypesLaterV3_14_0 = []string{
 	"timestamppb",
 	"wrapperspb",
 	"emptypb",
+	"durationpb",
+	"structpb",
+	"anypb",
+	"fieldmaskpb",
 }
 
 func getSupportedPtypeNames(cv *pluginpb.Version) []string {
@@ -1,9 +1,7 @@
 package spec
 
 import (
-	"fmt"
 	"log"
-	"strings"
 
 	"path/filepath"
 
@@ -72,7 +70,7 @@ func (q *Query) PluckRequest() []*Field {
 	}
 
 	if len(plucks) == 0 {
-		return q.Input.Fields()
+		return q.withoutFieldMask(q.Input.Fields())
 	}
 	var fields []*Field
 	for _, f := range q.Input.Fields() {
@@ -83,7 +81,7 @@ func (q *Query) PluckRequest() []*Field {
 			fields = append(fields, f)
 		}
 	}
-	return fields
+	return q.withoutFieldMask(fields)
 }
 
 func (q *Query) PluckResponse() []*Field {
@@ -105,76 +103,46 @@ func (q *Query) PluckResponse() []*Field {
 	return fields
 }
 
+// QueryType returns SDL type reference of query field
 func (q *Query) QueryType() string {
-	if q.IsPluckResponse() {
-		field := q.PluckResponse()[0]
-		return field.FieldType(q.GoPackage())
-	}
-
-	var pkgPrefix string
-	if q.GoPackage() != q.Output.GoPackage() {
-		if IsGooglePackage(q.Output) {
-			ptypeName, err := getImplementedPtypes(q.Output)
-			if err != nil {
-				log.Fatalf("[PROTOC-GEN-GRAPHQL] Error: %s\n", err)
-			}
-			pkgPrefix = "gql_ptypes_" + ptypeName + "."
-		} else {
-			pkgPrefix = filepath.Base(q.GoPackage())
-			if index := strings.Index(pkgPrefix, ";"); index > -1 {
-				pkgPrefix = pkgPrefix[index+1:]
-			}
-			if pkgPrefix != mainPackage {
-				pkgPrefix += "."
-			}
+	if q.IsConnection() {
+		typeName := q.ConnectionTypeName()
+		if q.Response().GetRequired() {
+			typeName += "!"
 		}
+		return typeName
+	}
+	if q.IsPluckResponse() {
+		return q.PluckResponse()[0].SchemaType()
 	}
 
-	typeName := pkgPrefix + PrefixType(q.Output.Name())
+	typeName := q.Output.GraphqlTypeName()
 	if resp := q.Response(); resp != nil {
 		if resp.GetRequired() {
-			typeName = "graphqlv1.NewNonNull(" + typeName + ")"
+			typeName += "!"
 		}
 	}
 	return typeName
 }
 
 func (q *Query) Args() []*Field {
+	if q.IsConnection() {
+		return q.connectionArgs()
+	}
 	return q.PluckRequest()
 }
 
+// SchemaArgs returns SDL of query arguments.
+// Connection accepts first and after arguments instead of page_size and page_token.
 func (q *Query) SchemaArgs() string {
-	args := make([]string, len(q.Input.Fields()))
-	for i, v := range q.Input.Fields() {
-		var defValue string
-		if d := v.DefaultValue(); d != "" {
-			defValue = " = " + d
-		}
-		args[i] = fmt.Sprintf("%s: %s%s", v.Name(), v.SchemaType(), defValue)
-	}
-	return strings.Join(args, ", ")
-}
-
-func (q *Query) OutputName() string {
-	if fields := q.PluckResponse(); len(fields) > 0 {
-		field := fields[0]
-		fieldType := field.GraphqlType()
-		if field.IsRepeated() {
-			fieldType = "[" + fieldType + "]"
-		}
-		if field.IsRequired() {
-			fieldType += "!"
-		}
-		return fieldType
+	args := schemaArgs(q.Args())
+	if !q.IsConnection() {
+		return args
 	}
-
-	typeName := q.Output.Name()
-	if resp := q.Response(); resp != nil {
-		if resp.GetRequired() {
-			typeName += "!"
-		}
+	if args == "" {
+		return connectionArgs
 	}
-	return typeName
+	return args + ", " + connectionArgs
 }
 
 func (q *Query) InputType() string {
@@ -191,6 +159,20 @@ func (q *Query) InputType() string {
 	return q.Input.Name()
 }
 
+func (q *Query) OutputType() string {
+	if q.Method.GoPackage() != q.Output.GoPackage() {
+		if IsGooglePackage(q.Output) {
+			ptypeName, err := getImplementedPtypes(q.Output)
+			if err != nil {
+				log.Fatalln("[PROTOC-GEN-GRAPHQL] Error:", err)
+			}
+			return "gql_ptypes_" + ptypeName + "." + q.Output.Name()
+		}
+		return q.Output.StructName(false)
+	}
+	return q.Output.Name()
+}
+
 func (q *Query) PluckResponseFieldName() string {
 	fields := q.PluckResponse()
 	return strcase.ToCamel(fields[0].Name())
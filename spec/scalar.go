@@ -0,0 +1,88 @@
+package spec
+
+// Custom scalars which keep values of protobuf types that GraphQL built-in scalars can't represent.
+// 64-bit integers are serialized as string since Int is 32-bit and JSON numbers lose precision above 2^53,
+// and bytes are serialized as base64 string like protojson does.
+const (
+	Int64Scalar  = "Int64"
+	UInt64Scalar = "UInt64"
+	BytesScalar  = "Bytes"
+	// DateTimeScalar represents google.protobuf.Timestamp as RFC 3339 string when datetime parameter is provided
+	DateTimeScalar = "DateTime"
+	// DurationScalar represents google.protobuf.Duration as string like "1.5s"
+	DurationScalar = "Duration"
+	// JSONScalar represents google.protobuf.Struct, Value, ListValue and Any as JSON value
+	JSONScalar = "JSON"
+	// FieldMaskScalar represents google.protobuf.FieldMask as comma separated paths
+	FieldMaskScalar = "FieldMask"
+)
+
+var scalarDefinitions = map[string]string{
+	Int64Scalar: `"""64-bit signed integer which is serialized as string, accepts string or integer as input"""
+scalar Int64`,
+	UInt64Scalar: `"""64-bit unsigned integer which is serialized as string, accepts string or integer as input"""
+scalar UInt64`,
+	BytesScalar: `"""Bytes which are serialized as base64 string"""
+scalar Bytes`,
+	DateTimeScalar: `"""Date and time which are serialized as RFC 3339 string, e.g. 2006-01-02T15:04:05Z"""
+scalar DateTime`,
+	DurationScalar: `"""Duration which is serialized as seconds with suffix "s", accepts ISO 8601 duration e.g. PT1M30S as input"""
+scalar Duration`,
+	JSONScalar: `"""Arbitrary JSON value"""
+scalar JSON`,
+	FieldMaskScalar: `"""Set of field paths which are separated by comma, e.g. name,address.city"""
+scalar FieldMask`,
+}
+
+// scalarMessages are google's ptypes which are always mapped to scalars
+var scalarMessages = map[string]string{
+	"google.protobuf.Duration":  DurationScalar,
+	"google.protobuf.Struct":    JSONScalar,
+	"google.protobuf.Value":     JSONScalar,
+	"google.protobuf.ListValue": JSONScalar,
+	// Any is represented as protojson does, which has "@type" key with the fields of contained message
+	"google.protobuf.Any":       JSONScalar,
+	"google.protobuf.FieldMask": FieldMaskScalar,
+}
+
+// wrapperScalars are nullable scalars which google.protobuf wrappers are mapped to unless wrappers parameter is "object".
+// BytesValue is always mapped to Bytes scalar since it has no object type.
+var wrapperScalars = map[string]string{
+	"google.protobuf.DoubleValue": "Float",
+	"google.protobuf.FloatValue":  "Float",
+	"google.protobuf.Int64Value":  Int64Scalar,
+	"google.protobuf.UInt64Value": UInt64Scalar,
+	"google.protobuf.Int32Value":  "Int",
+	"google.protobuf.UInt32Value": "Int",
+	"google.protobuf.BoolValue":   "Boolean",
+	"google.protobuf.StringValue": "String",
+	"google.protobuf.BytesValue":  BytesScalar,
+}
+
+// ScalarDefinition returns SDL of custom scalar which the field refers, or empty string for other types.
+// Union of Any refers JSON scalar as input.
+func (f *Field) ScalarDefinition() string {
+	if d, ok := scalarDefinitions[f.GraphqlType()]; ok {
+		return d
+	}
+	return scalarDefinitions[f.GraphqlInputType()]
+}
+
+// ScalarName returns name of scalar which the message is mapped to instead of object type, or empty string
+func (m *Message) ScalarName() string {
+	if m.dateTime && m.FullPath() == "google.protobuf.Timestamp" {
+		return DateTimeScalar
+	}
+	if name, ok := wrapperScalars[m.FullPath()]; ok && (!m.wrapperAsObject || name == BytesScalar) {
+		if m.int64AsInt && (name == Int64Scalar || name == UInt64Scalar) {
+			return "Int"
+		}
+		return name
+	}
+	return scalarMessages[m.FullPath()]
+}
+
+// ScalarDefinition returns SDL of custom scalar which the message is mapped to, or empty string
+func (m *Message) ScalarDefinition() string {
+	return scalarDefinitions[m.ScalarName()]
+}
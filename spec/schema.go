@@ -0,0 +1,97 @@
+package spec
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ObjectDefinition returns SDL of object type.
+// Fields which have resolver option are defined with arguments of the resolver query.
+// Node implements Node interface whose id field is global ID.
+func (m *Message) ObjectDefinition(services []*Service) string {
+	b := new(strings.Builder)
+	b.WriteString(schemaDescription(m.Comment(), ""))
+	b.WriteString("type " + m.GraphqlTypeName())
+	if m.IsNode() {
+		b.WriteString(" implements Node")
+	}
+	b.WriteString(" {\n")
+	if id := m.NodeIDField(); m.IsNode() && (id == nil || id.FieldName() != globalIDField) {
+		b.WriteString("  " + globalIDField + ": ID!\n")
+	}
+	for _, f := range m.Fields() {
+		if m.isGlobalIDField(f) {
+			b.WriteString(schemaDescription(f.Comment(), "  "))
+			b.WriteString("  " + globalIDField + ": ID!\n")
+			continue
+		}
+		if f.IsResolve() {
+			q := f.ResolveSubField(services)
+			b.WriteString(schemaDescription(q.Comment(), "  "))
+			b.WriteString("  " + f.FieldName())
+			if args := q.SchemaArgs(); args != "" {
+				b.WriteString("(" + args + ")")
+			}
+			b.WriteString(": " + q.QueryType() + "\n")
+			continue
+		}
+		b.WriteString(schemaDescription(f.Comment(), "  "))
+		b.WriteString("  " + f.FieldName() + ": " + f.SchemaType() + "\n")
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// InputDefinition returns SDL of input object type
+func (m *Message) InputDefinition() string {
+	b := new(strings.Builder)
+	b.WriteString(schemaDescription(m.Comment(), ""))
+	b.WriteString("input " + m.GraphqlInputName() + " {\n")
+	for _, f := range m.Fields() {
+		b.WriteString(schemaDescription(f.Comment(), "  "))
+		b.WriteString("  " + f.FieldName() + ": " + f.SchemaInputType())
+		if d := f.DefaultValue(); d != "" {
+			b.WriteString(" = " + d)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// EnumDefinition returns SDL of enum type
+func (e *Enum) EnumDefinition() string {
+	b := new(strings.Builder)
+	b.WriteString(schemaDescription(e.Comment(), ""))
+	b.WriteString("enum " + e.GraphqlEnumName() + " {\n")
+	for _, v := range e.Values() {
+		b.WriteString(schemaDescription(v.Comment(), "  "))
+		b.WriteString("  " + v.Name() + "\n")
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// schemaArgs returns SDL of field arguments, e.g. `name: String!, count: Int = 1`
+func schemaArgs(fields []*Field) string {
+	args := make([]string, len(fields))
+	for i, f := range fields {
+		var description, defValue string
+		if c := f.Comment(); c != "" {
+			description = strings.TrimSuffix(schemaDescription(c, ""), "\n") + " "
+		}
+		if d := f.DefaultValue(); d != "" {
+			defValue = " = " + d
+		}
+		args[i] = fmt.Sprintf("%s%s: %s%s", description, f.FieldName(), f.SchemaInputType(), defValue)
+	}
+	return strings.Join(args, ", ")
+}
+
+// schemaDescription formats comment as block string description
+func schemaDescription(comment, indent string) string {
+	if comment == "" {
+		return ""
+	}
+	return indent + `"""` + strings.ReplaceAll(comment, `"""`, `\"""`) + `"""` + "\n"
+}
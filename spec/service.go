@@ -1,11 +1,18 @@
 package spec
 
 import (
+	"fmt"
+	"regexp"
+	"strings"
+
 	graphqlv1 "github.com/nebucloud/nebucloud-gateway/gen/go/graphql/v1"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/descriptorpb"
 )
 
+// graphqlName matches names of GraphQL spec
+var graphqlNamePattern = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+
 // Service spec wraps ServiceDescriptorProto with GraphqlService option.
 type Service struct {
 	descriptor *descriptorpb.ServiceDescriptorProto
@@ -45,7 +52,7 @@ func NewService(
 	for i, m := range d.GetMethod() {
 		ps := make([]int, len(paths))
 		copy(ps, paths)
-		s.methods = append(s.methods, NewMethod(m, s, append(ps, 4, i)...)) // nolint: gomnd
+		s.methods = append(s.methods, NewMethod(m, s, append(ps, 2, i)...)) // nolint: gomnd
 	}
 	return s
 }
@@ -69,9 +76,44 @@ func (s *Service) Host() string {
 	return s.Option.GetHost()
 }
 
+func (s *Service) Namespace() string {
+	if s.Option == nil {
+		return ""
+	}
+	return s.Option.GetNamespace()
+}
+
+// ValidateNamespace checks that namespace option can be a field name of GraphQL
+func (s *Service) ValidateNamespace() error {
+	ns := s.Namespace()
+	if ns == "" {
+		return nil
+	}
+	if !graphqlNamePattern.MatchString(ns) || strings.HasPrefix(ns, "__") {
+		return fmt.Errorf("namespace %q of %s is not a valid GraphQL name", ns, s.Name())
+	}
+	return nil
+}
+
 func (s *Service) Insecure() bool {
 	if s.Option == nil {
 		return false
 	}
 	return s.Option.GetInsecure()
 }
+
+// CallPolicies returns GraphQL exposed methods which declare call policy
+func (s *Service) CallPolicies() []*Method {
+	var methods []*Method
+	for _, q := range s.Queries {
+		if q.HasCallPolicy() {
+			methods = append(methods, q.Method)
+		}
+	}
+	for _, m := range s.Mutations {
+		if m.HasCallPolicy() {
+			methods = append(methods, m.Method)
+		}
+	}
+	return methods
+}